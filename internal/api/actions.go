@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const (
+	ActionStart    = "start"
+	ActionShutdown = "shutdown"
+	ActionStop     = "stop"
+	ActionReboot   = "reboot"
+	ActionSuspend  = "suspend"
+	ActionResume   = "resume"
+)
+
+func guestPath(node, guestType string, vmid int) string {
+	return fmt.Sprintf("/nodes/%s/%s/%d", url.PathEscape(node), guestType, vmid)
+}
+
+func (c *Client) GuestAction(ctx context.Context, node, guestType string, vmid int, action string) (string, error) {
+	switch action {
+	case ActionStart, ActionShutdown, ActionStop, ActionReboot, ActionSuspend, ActionResume:
+	default:
+		return "", fmt.Errorf("unknown guest action %q", action)
+	}
+
+	var upid string
+	if err := c.call(ctx, "POST", guestPath(node, guestType, vmid)+"/status/"+action, url.Values{}, &upid); err != nil {
+		return "", err
+	}
+
+	return upid, nil
+}
+
+func (c *Client) GetTaskStatus(ctx context.Context, node, upid string) (*models.TaskStatus, error) {
+	var status models.TaskStatus
+	path := fmt.Sprintf("/nodes/%s/tasks/%s/status", url.PathEscape(node), url.PathEscape(upid))
+	if err := c.get(ctx, path, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const (
+	BackupModeSnapshot = "snapshot"
+	BackupModeSuspend  = "suspend"
+	BackupModeStop     = "stop"
+)
+
+func (c *Client) GetStorageBackups(ctx context.Context, node, storage string) ([]models.Backup, error) {
+	path := fmt.Sprintf("/nodes/%s/storage/%s/content?content=backup", url.PathEscape(node), url.PathEscape(storage))
+
+	var backups []models.Backup
+	if err := c.get(ctx, path, &backups); err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		backups[i].Storage = storage
+		backups[i].Node = node
+	}
+	return backups, nil
+}
+
+type StorageError struct {
+	Storage models.Storage
+	Err     error
+}
+
+type BackupsResult struct {
+	Backups       []models.Backup
+	StorageErrors []StorageError
+}
+
+func (c *Client) GetBackups(ctx context.Context) (*BackupsResult, error) {
+	res, err := c.GetClusterResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A shared storage is listed once per node; any node that has it
+	// active can list its backups.
+	var targets, inactive []models.Storage
+	seen := make(map[string]bool)
+	for _, st := range res.Storage {
+		switch {
+		case !strings.Contains(st.Content, "backup"):
+		case st.Shared:
+			if st.Active && !seen[st.Storage] {
+				seen[st.Storage] = true
+				targets = append(targets, st)
+			}
+		case st.Active:
+			targets = append(targets, st)
+		default:
+			inactive = append(inactive, st)
+		}
+	}
+	for _, st := range res.Storage {
+		if st.Shared && !seen[st.Storage] && strings.Contains(st.Content, "backup") {
+			seen[st.Storage] = true
+			inactive = append(inactive, st)
+		}
+	}
+
+	perStorage := make([][]models.Backup, len(targets))
+	errs := c.forEach(ctx, len(targets), func(i int) error {
+		backups, err := c.GetStorageBackups(ctx, targets[i].Node, targets[i].Storage)
+		perStorage[i] = backups
+		return err
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &BackupsResult{Backups: []models.Backup{}}
+	for _, st := range inactive {
+		result.StorageErrors = append(result.StorageErrors, StorageError{Storage: st, Err: fmt.Errorf("storage status is %s", st.Status)})
+	}
+	for i, st := range targets {
+		if errs[i] != nil {
+			result.StorageErrors = append(result.StorageErrors, StorageError{Storage: st, Err: errs[i]})
+		}
+		result.Backups = append(result.Backups, perStorage[i]...)
+	}
+	if len(result.StorageErrors) > 0 && len(result.StorageErrors) == len(targets)+len(inactive) {
+		first := result.StorageErrors[0]
+		return result, fmt.Errorf("no backup storage answered (%s on %s: %w)", first.Storage.Storage, first.Storage.Node, first.Err)
+	}
+	return result, nil
+}
+
+func (c *Client) StartBackup(ctx context.Context, node string, vmids []int, opts models.BackupOptions) (string, error) {
+	if len(vmids) == 0 {
+		return "", fmt.Errorf("no guests to back up")
+	}
+
+	ids := make([]string, len(vmids))
+	for i, vmid := range vmids {
+		ids[i] = strconv.Itoa(vmid)
+	}
+	data := url.Values{}
+	data.Set("vmid", strings.Join(ids, ","))
+	data.Set("storage", opts.Storage)
+	if opts.Mode != "" {
+		data.Set("mode", opts.Mode)
+	}
+	if opts.Compress != "" {
+		data.Set("compress", opts.Compress)
+	}
+
+	var upid string
+	if err := c.call(ctx, "POST", fmt.Sprintf("/nodes/%s/vzdump", url.PathEscape(node)), data, &upid); err != nil {
+		return "", err
+	}
+	return upid, nil
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type cephOSDMap struct {
+	NumOSDs   int         `json:"num_osds"`
+	NumUpOSDs int         `json:"num_up_osds"`
+	NumInOSDs int         `json:"num_in_osds"`
+	OSDMap    *cephOSDMap `json:"osdmap"`
+}
+
+type cephStatus struct {
+	Health struct {
+		Status string `json:"status"`
+		Checks map[string]struct {
+			Severity string `json:"severity"`
+			Summary  struct {
+				Message string `json:"message"`
+			} `json:"summary"`
+			Detail []struct {
+				Message string `json:"message"`
+			} `json:"detail"`
+			Muted bool `json:"muted"`
+		} `json:"checks"`
+	} `json:"health"`
+	OSDMap cephOSDMap `json:"osdmap"`
+	PGMap  struct {
+		NumPGs     int `json:"num_pgs"`
+		PGsByState []struct {
+			StateName string `json:"state_name"`
+			Count     int    `json:"count"`
+		} `json:"pgs_by_state"`
+		BytesUsed            int64   `json:"bytes_used"`
+		BytesAvail           int64   `json:"bytes_avail"`
+		BytesTotal           int64   `json:"bytes_total"`
+		ReadBytesSec         int64   `json:"read_bytes_sec"`
+		WriteBytesSec        int64   `json:"write_bytes_sec"`
+		ReadOpsSec           int64   `json:"read_op_per_sec"`
+		WriteOpsSec          int64   `json:"write_op_per_sec"`
+		RecoveringBytesSec   int64   `json:"recovering_bytes_per_sec"`
+		RecoveringObjectsSec int64   `json:"recovering_objects_per_sec"`
+		DegradedRatio        float64 `json:"degraded_ratio"`
+		MisplacedRatio       float64 `json:"misplaced_ratio"`
+	} `json:"pgmap"`
+}
+
+var cephSeverityOrder = map[string]int{"HEALTH_ERR": 0, "HEALTH_WARN": 1}
+
+func (s cephStatus) status() models.CephStatus {
+	osdMap := s.OSDMap
+	// Ceph before Octopus nests the OSD counters one level deeper.
+	if osdMap.OSDMap != nil {
+		osdMap = *osdMap.OSDMap
+	}
+
+	status := models.CephStatus{
+		Health:               s.Health.Status,
+		NumOSDs:              osdMap.NumOSDs,
+		UpOSDs:               osdMap.NumUpOSDs,
+		InOSDs:               osdMap.NumInOSDs,
+		NumPGs:               s.PGMap.NumPGs,
+		BytesUsed:            s.PGMap.BytesUsed,
+		BytesAvail:           s.PGMap.BytesAvail,
+		BytesTotal:           s.PGMap.BytesTotal,
+		ReadBytesSec:         s.PGMap.ReadBytesSec,
+		WriteBytesSec:        s.PGMap.WriteBytesSec,
+		ReadOpsSec:           s.PGMap.ReadOpsSec,
+		WriteOpsSec:          s.PGMap.WriteOpsSec,
+		RecoveringBytesSec:   s.PGMap.RecoveringBytesSec,
+		RecoveringObjectsSec: s.PGMap.RecoveringObjectsSec,
+		DegradedRatio:        s.PGMap.DegradedRatio,
+		MisplacedRatio:       s.PGMap.MisplacedRatio,
+	}
+
+	for name, check := range s.Health.Checks {
+		c := models.CephHealthCheck{
+			Name:     name,
+			Severity: check.Severity,
+			Message:  check.Summary.Message,
+			Muted:    check.Muted,
+		}
+		for _, detail := range check.Detail {
+			c.Details = append(c.Details, detail.Message)
+		}
+		status.Checks = append(status.Checks, c)
+	}
+	sort.Slice(status.Checks, func(i, j int) bool {
+		a, b := status.Checks[i], status.Checks[j]
+		if cephSeverityOrder[a.Severity] != cephSeverityOrder[b.Severity] {
+			return cephSeverityOrder[a.Severity] < cephSeverityOrder[b.Severity]
+		}
+		return a.Name < b.Name
+	})
+
+	for _, pg := range s.PGMap.PGsByState {
+		status.PGStates = append(status.PGStates, models.CephPGState{State: pg.StateName, Count: pg.Count})
+	}
+	sort.Slice(status.PGStates, func(i, j int) bool {
+		return status.PGStates[i].Count > status.PGStates[j].Count
+	})
+	return status
+}
+
+type cephOSDNode struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Status      string        `json:"status"`
+	In          int           `json:"in"`
+	Host        string        `json:"host"`
+	DeviceClass string        `json:"device_class"`
+	BytesUsed   int64         `json:"bytes_used"`
+	TotalSpace  int64         `json:"total_space"`
+	Children    []cephOSDNode `json:"children"`
+}
+
+func (n cephOSDNode) collect(host string, osds []models.CephOSD) []models.CephOSD {
+	if n.Type == "host" {
+		host = n.Name
+	}
+	if n.Type == "osd" {
+		if n.Host != "" {
+			host = n.Host
+		}
+		return append(osds, models.CephOSD{
+			ID:          n.ID,
+			Name:        n.Name,
+			Host:        host,
+			Status:      n.Status,
+			In:          n.In == 1,
+			DeviceClass: n.DeviceClass,
+			Used:        n.BytesUsed,
+			Total:       n.TotalSpace,
+		})
+	}
+	for _, child := range n.Children {
+		osds = child.collect(host, osds)
+	}
+	return osds
+}
+
+var cephMissingMessages = []string{"not installed", "not initialized", "rados_connect failed"}
+
+func IsCephUnavailable(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusNotImplemented {
+		return true
+	}
+	for _, msg := range cephMissingMessages {
+		if strings.Contains(apiErr.Message, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) GetCephOSDs(ctx context.Context, node string) ([]models.CephOSD, error) {
+	var tree struct {
+		Root cephOSDNode `json:"root"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/ceph/osd", url.PathEscape(node)), &tree); err != nil {
+		return nil, err
+	}
+
+	osds := tree.Root.collect("", nil)
+	sort.Slice(osds, func(i, j int) bool {
+		return osds[i].ID < osds[j].ID
+	})
+	return osds, nil
+}
+
+func (c *Client) GetCephPools(ctx context.Context, node string) ([]models.CephPool, error) {
+	var pools []models.CephPool
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/ceph/pool", url.PathEscape(node)), &pools); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].Name < pools[j].Name
+	})
+	return pools, nil
+}
+
+func (c *Client) GetCeph(ctx context.Context) (*models.Ceph, error) {
+	var raw cephStatus
+	if err := c.get(ctx, "/cluster/ceph/status", &raw); err != nil {
+		return nil, err
+	}
+	ceph := &models.Ceph{Status: raw.status()}
+
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+
+	// The OSD tree and the pool list are cluster-wide; any node running
+	// Ceph can answer for all of them.
+	err = errors.New("no online node to query ceph on")
+	for _, node := range nodes {
+		if node.Status != "" && node.Status != "online" {
+			continue
+		}
+		if ceph.OSDs, err = c.GetCephOSDs(ctx, node.Node); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if ceph.Pools, err = c.GetCephPools(ctx, node.Node); err == nil {
+			return ceph, nil
+		}
+	}
+	return nil, err
+}
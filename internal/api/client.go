@@ -1,12 +1,16 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/berocorpdotnet/pvetop/internal/models"
@@ -18,15 +22,21 @@ type Client struct {
 	ticket     string
 	csrfToken  string
 	token      string 
+	workers    int
+	votesMu    sync.Mutex
+	votes      map[string]int
+	votesAt    time.Time
 }
 
+const defaultWorkers = 4
+
 func NewClient(host, port string) *Client {
 	return &Client{
 		baseURL: fmt.Sprintf("https://%s:%s/api2/json", host, port),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				TLSClientConfig: &tls.Config{},
 			},
 		},
 	}
@@ -39,18 +49,18 @@ func NewClientWithToken(host, port, token string) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+				TLSClientConfig: &tls.Config{},
 			},
 		},
 	}
 }
 
-func (c *Client) Login(username, password string) error {
+func (c *Client) Login(ctx context.Context, username, password string) error {
 	data := url.Values{}
 	data.Set("username", username)
 	data.Set("password", password)
 
-	req, err := http.NewRequest("POST", c.baseURL+"/access/ticket", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/access/ticket", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -62,6 +72,13 @@ func (c *Client) Login(username, password string) error {
 	}
 	defer resp.Body.Close()
 
+	if err := checkResponse(resp, "POST", "/access/ticket"); err != nil {
+		if IsUnauthorized(err) {
+			return fmt.Errorf("login failed: wrong username, realm or password")
+		}
+		return err
+	}
+
 	var result struct {
 		Data struct {
 			Ticket              string `json:"ticket"`
@@ -79,7 +96,7 @@ func (c *Client) Login(username, password string) error {
 	return nil
 }
 
-func (c *Client) CreateAPIToken(username, tokenID string) (string, error) {
+func (c *Client) CreateAPIToken(ctx context.Context, username, tokenID string) (string, error) {
 	if c.ticket == "" {
 		return "", fmt.Errorf("not authenticated - call Login first")
 	}
@@ -89,61 +106,77 @@ func (c *Client) CreateAPIToken(username, tokenID string) (string, error) {
 	data.Set("privsep", "0") 
 
 	path := fmt.Sprintf("/access/users/%s/token/%s", url.PathEscape(username), url.PathEscape(tokenID))
-	resp, err := c.doRequest("POST", path, data)
-	if err != nil {
-		return "", fmt.Errorf("failed to create token: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("failed to create token: HTTP %d", resp.StatusCode)
-	}
 
 	var result struct {
-		Data struct {
-			Value string `json:"value"`
-		} `json:"data"`
+		Value string `json:"value"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to parse token response: %w", err)
+	if err := c.call(ctx, "POST", path, data, &result); err != nil {
+		return "", fmt.Errorf("failed to create token: %w", err)
 	}
 
-	fullToken := fmt.Sprintf("%s!%s=%s", username, tokenID, result.Data.Value)
+	fullToken := fmt.Sprintf("%s!%s=%s", username, tokenID, result.Value)
 	return fullToken, nil
 }
 
-func (c *Client) DeleteAPIToken(username, tokenID string) error {
+func (c *Client) DeleteAPIToken(ctx context.Context, username, tokenID string) error {
 	if c.ticket == "" && c.token == "" {
 		return fmt.Errorf("not authenticated")
 	}
 
 	path := fmt.Sprintf("/access/users/%s/token/%s", url.PathEscape(username), url.PathEscape(tokenID))
-	resp, err := c.doRequest("DELETE", path, nil)
-	if err != nil {
+	if err := c.call(ctx, "DELETE", path, nil, nil); err != nil {
 		return fmt.Errorf("failed to delete token: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to delete token: HTTP %d", resp.StatusCode)
+	return nil
+}
+
+func (c *Client) SetConcurrency(workers int) {
+	c.workers = workers
+}
+
+func (c *Client) forEach(ctx context.Context, n int, fn func(i int) error) []error {
+	workers := c.workers
+	if workers <= 0 {
+		workers = defaultWorkers
 	}
 
-	return nil
+	errs := make([]error, n)
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+	return errs
 }
 
-func (c *Client) doRequest(method, path string, data url.Values) (*http.Response, error) {
+func (c *Client) doRequest(ctx context.Context, method, path string, data url.Values) (*http.Response, error) {
 	var req *http.Request
 	var err error
 
 	if data != nil && method != "GET" {
-		req, err = http.NewRequest(method, c.baseURL+path, strings.NewReader(data.Encode()))
+		req, err = http.NewRequestWithContext(ctx, method, c.baseURL+path, strings.NewReader(data.Encode()))
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	} else {
-		req, err = http.NewRequest(method, c.baseURL+path, nil)
+		req, err = http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -159,143 +192,161 @@ func (c *Client) doRequest(method, path string, data url.Values) (*http.Response
 	return c.httpClient.Do(req)
 }
 
-func (c *Client) GetNodes() ([]models.Node, error) {
-	resp, err := c.doRequest("GET", "/nodes", nil)
+func (c *Client) call(ctx context.Context, method, path string, data url.Values, out any) error {
+	resp, err := c.doRequest(ctx, method, path, data)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		Data []models.Node `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := checkResponse(resp, method, path); err != nil {
+		return err
 	}
 
-	return result.Data, nil
-}
-
-func (c *Client) GetVMs(node string) ([]models.Guest, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/nodes/%s/qemu", node), nil)
-	if err != nil {
-		return nil, err
+	if out == nil {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	var result struct {
-		Data []models.Guest `json:"data"`
-	}
+	result := struct {
+		Data any `json:"data"`
+	}{Data: out}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
 	}
 
-	for i := range result.Data {
-		result.Data[i].Type = "qemu"
-		result.Data[i].Node = node
-		if result.Data[i].Status == "running" {
-			if status, err := c.GetVMStatus(node, result.Data[i].VMID); err == nil {
-				result.Data[i].DiskRead = status.DiskRead
-				result.Data[i].DiskWrite = status.DiskWrite
-				result.Data[i].NetIn = status.NetIn
-				result.Data[i].NetOut = status.NetOut
-			}
-		}
-	}
+	return nil
+}
 
-	return result.Data, nil
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	return c.call(ctx, "GET", path, nil, out)
 }
 
-func (c *Client) GetContainers(node string) ([]models.Guest, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/nodes/%s/lxc", node), nil)
-	if err != nil {
+func (c *Client) GetNodes(ctx context.Context) ([]models.Node, error) {
+	var nodes []models.Node
+	if err := c.get(ctx, "/nodes", &nodes); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result struct {
-		Data []models.Guest `json:"data"`
-	}
+	return nodes, nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+func (c *Client) GetVMs(ctx context.Context, node string) ([]models.Guest, error) {
+	var guests []models.Guest
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/qemu", node), &guests); err != nil {
 		return nil, err
 	}
 
-	for i := range result.Data {
-		result.Data[i].Type = "lxc"
-		result.Data[i].Node = node
-		if result.Data[i].Status == "running" {
-			if status, err := c.GetContainerStatus(node, result.Data[i].VMID); err == nil {
-				result.Data[i].DiskRead = status.DiskRead
-				result.Data[i].DiskWrite = status.DiskWrite
-				result.Data[i].NetIn = status.NetIn
-				result.Data[i].NetOut = status.NetOut
-			}
+	for i := range guests {
+		guests[i].Type = "qemu"
+		guests[i].Node = node
+		if guests[i].Status == "running" {
+			c.fillGuestStatus(ctx, &guests[i])
 		}
 	}
 
-	return result.Data, nil
+	return guests, nil
 }
 
-func (c *Client) GetVMStatus(node string, vmid int) (*models.GuestStatus, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/nodes/%s/qemu/%d/status/current", node, vmid), nil)
-	if err != nil {
+func (c *Client) GetContainers(ctx context.Context, node string) ([]models.Guest, error) {
+	var guests []models.Guest
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/lxc", node), &guests); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result struct {
-		Data models.GuestStatus `json:"data"`
+	for i := range guests {
+		guests[i].Type = "lxc"
+		guests[i].Node = node
+		if guests[i].Status == "running" {
+			c.fillGuestStatus(ctx, &guests[i])
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
+	return guests, nil
+}
 
-	return &result.Data, nil
+func (c *Client) GetVMStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error) {
+	return c.getGuestStatus(ctx, node, "qemu", vmid)
 }
 
-func (c *Client) GetContainerStatus(node string, vmid int) (*models.GuestStatus, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/nodes/%s/lxc/%d/status/current", node, vmid), nil)
-	if err != nil {
+func (c *Client) GetContainerStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error) {
+	return c.getGuestStatus(ctx, node, "lxc", vmid)
+}
+
+func (c *Client) getGuestStatus(ctx context.Context, node, guestType string, vmid int) (*models.GuestStatus, error) {
+	var raw json.RawMessage
+	if err := c.get(ctx, guestPath(node, guestType, vmid)+"/status/current", &raw); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result struct {
-		Data models.GuestStatus `json:"data"`
+	var status models.GuestStatus
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return nil, err
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&status.Raw); err != nil {
 		return nil, err
 	}
+	status.UpdatedAt = time.Now()
 
-	return &result.Data, nil
+	return &status, nil
 }
 
-func (c *Client) GetAllGuests() ([]models.Guest, error) {
-	nodes, err := c.GetNodes()
+type GuestsResult struct {
+	Nodes      []models.Node
+	Guests     []models.Guest
+	NodeErrors map[string]error
+}
+
+func (c *Client) GetAllGuests(ctx context.Context) (*GuestsResult, error) {
+	nodes, err := c.GetNodes(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var allGuests []models.Guest
+	result := &GuestsResult{
+		Nodes:      nodes,
+		NodeErrors: make(map[string]error),
+	}
 
+	var names []string
 	for _, node := range nodes {
-		vms, err := c.GetVMs(node.Node)
-		if err != nil {
+		if node.Status != "" && node.Status != "online" {
+			result.NodeErrors[node.Node] = fmt.Errorf("node is %s", node.Status)
 			continue
 		}
-		allGuests = append(allGuests, vms...)
+		names = append(names, node.Node)
+	}
+
+	perNode := make([][]models.Guest, len(names))
+	partial := make([]bool, len(names))
+	errs := c.forEach(ctx, len(names), func(i int) error {
+		vms, vmErr := c.GetVMs(ctx, names[i])
+		containers, ctErr := c.GetContainers(ctx, names[i])
+		perNode[i] = append(vms, containers...)
+		partial[i] = vmErr == nil || ctErr == nil
+		return errors.Join(vmErr, ctErr)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-		containers, err := c.GetContainers(node.Node)
-		if err != nil {
-			continue
+	// A node only counts as failed when neither listing answered; if one
+	// did, its guests are kept and the other listing's error is reported.
+	failed := 0
+	for i, name := range names {
+		if errs[i] != nil {
+			result.NodeErrors[name] = errs[i]
+			if !partial[i] {
+				failed++
+			}
 		}
-		allGuests = append(allGuests, containers...)
+		result.Guests = append(result.Guests, perNode[i]...)
+	}
+	if failed > 0 && failed == len(names) {
+		return result, fmt.Errorf("no online node answered (%s: %w)", names[0], errs[0])
 	}
 
-	return allGuests, nil
+	return result, nil
 }
@@ -0,0 +1,267 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/fakepve"
+)
+
+func newFakeServer(t *testing.T) *fakepve.Server {
+	t.Helper()
+	s := fakepve.New(fakepve.Options{Nodes: 3, GuestsPerNode: 4, Seed: 1})
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T, s *fakepve.Server, token string) *api.Client {
+	t.Helper()
+	host, port := s.HostPort()
+	client := api.NewClientWithToken(host, port, token)
+	if err := client.ConfigureTLS(api.TLSConfig{Fingerprint: s.Fingerprint()}); err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestGetClusterResources(t *testing.T) {
+	s := newFakeServer(t)
+	client := newTestClient(t, s, s.Token())
+
+	res, err := client.GetClusterResources(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Nodes) != 3 {
+		t.Errorf("got %d nodes, want 3", len(res.Nodes))
+	}
+	if len(res.Guests) != 12 {
+		t.Errorf("got %d guests, want 12", len(res.Guests))
+	}
+
+	nodes := make(map[string]bool)
+	for _, n := range res.Nodes {
+		nodes[n.Node] = true
+	}
+	for _, g := range res.Guests {
+		if !nodes[g.Node] || g.VMID == 0 || (g.Type != "qemu" && g.Type != "lxc") {
+			t.Errorf("unexpected guest %+v", g)
+		}
+	}
+}
+
+func TestGetAllGuestsFallback(t *testing.T) {
+	s := newFakeServer(t)
+	client := newTestClient(t, s, s.Token())
+	ctx := context.Background()
+
+	s.Fail("GET", "/cluster/resources", http.StatusForbidden, "Permission check failed (/, Sys.Audit)")
+	if _, err := client.GetClusterResources(ctx); !api.IsForbidden(err) {
+		t.Fatalf("cluster resources: got %v, want a 403", err)
+	}
+
+	result, err := client.GetAllGuests(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Guests) != 12 || len(result.NodeErrors) != 0 {
+		t.Errorf("got %d guests and node errors %v, want 12 and none", len(result.Guests), result.NodeErrors)
+	}
+
+	s.Fail("GET", "/nodes/pve2/qemu", http.StatusInternalServerError, "node unreachable")
+	result, err = client.GetAllGuests(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NodeErrors["pve2"] == nil || len(result.NodeErrors) != 1 {
+		t.Errorf("node errors = %v, want only pve2", result.NodeErrors)
+	}
+	for _, g := range result.Guests {
+		if g.Node == "pve2" && g.Type == "qemu" {
+			t.Errorf("got VM %d from the failing node", g.VMID)
+		}
+	}
+
+	s.Fail("GET", "/nodes/*/lxc", http.StatusInternalServerError, "node unreachable")
+	result, err = client.GetAllGuests(ctx)
+	if err != nil {
+		t.Fatalf("got %v, want the VMs of the nodes that still list them", err)
+	}
+	if len(result.NodeErrors) != 3 {
+		t.Errorf("node errors = %v, want all three", result.NodeErrors)
+	}
+	for _, g := range result.Guests {
+		if g.Type != "qemu" || g.Node == "pve2" {
+			t.Errorf("got %s %d on %s from a failing listing", g.Type, g.VMID, g.Node)
+		}
+	}
+	if len(result.Guests) == 0 {
+		t.Error("the VMs of pve1 and pve3 were dropped")
+	}
+
+	s.Fail("GET", "/nodes/*/qemu", http.StatusInternalServerError, "node unreachable")
+	result, err = client.GetAllGuests(ctx)
+	if err == nil || !strings.Contains(err.Error(), "no online node answered") {
+		t.Fatalf("got %v, want an error when every node fails", err)
+	}
+	if result == nil || len(result.NodeErrors) != 3 {
+		t.Errorf("want the node errors alongside the error, got %+v", result)
+	}
+}
+
+func TestGetReplicationNodeErrors(t *testing.T) {
+	s := newFakeServer(t)
+	client := newTestClient(t, s, s.Token())
+	ctx := context.Background()
+
+	s.Fail("GET", "/nodes/pve2/replication", http.StatusInternalServerError, "node unreachable")
+	result, err := client.GetReplication(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NodeErrors["pve2"] == nil || len(result.NodeErrors) != 1 {
+		t.Errorf("node errors = %v, want only pve2", result.NodeErrors)
+	}
+	for _, job := range result.Jobs {
+		if job.Source == "pve2" {
+			t.Errorf("got job %s from the failing node", job.ID)
+		}
+	}
+
+	s.Fail("GET", "/nodes/*/replication", http.StatusInternalServerError, "node unreachable")
+	result, err = client.GetReplication(ctx)
+	if err == nil {
+		t.Fatal("no error when every node fails")
+	}
+	if result == nil || len(result.NodeErrors) != 3 {
+		t.Errorf("want the node errors alongside the error, got %+v", result)
+	}
+}
+
+func TestGetBackupsStorageErrors(t *testing.T) {
+	s := newFakeServer(t)
+	client := newTestClient(t, s, s.Token())
+
+	s.Fail("GET", "/nodes/pve2/storage/local/content", http.StatusInternalServerError, "storage 'local' is not online")
+	result, err := client.GetBackups(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.StorageErrors) != 1 {
+		t.Fatalf("storage errors = %+v, want one", result.StorageErrors)
+	}
+	if failed := result.StorageErrors[0].Storage; failed.Storage != "local" || failed.Node != "pve2" {
+		t.Errorf("failed storage = %s on %s, want local on pve2", failed.Storage, failed.Node)
+	}
+	for _, b := range result.Backups {
+		if b.Storage == "local" && b.Node == "pve2" {
+			t.Errorf("got backup %s from the failing storage", b.VolID)
+		}
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		fail   int
+		status int
+		check  func(error) bool
+	}{
+		{"revoked token", "root@pam!gone=00000000-0000-0000-0000-000000000000", 0, http.StatusUnauthorized, api.IsUnauthorized},
+		{"expired ticket", "", http.StatusUnauthorized, http.StatusUnauthorized, api.IsUnauthorized},
+		{"missing privilege", "", http.StatusForbidden, http.StatusForbidden, api.IsForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeServer(t)
+			token := tt.token
+			if token == "" {
+				token = s.Token()
+			}
+			if tt.fail != 0 {
+				s.Fail("GET", "/nodes", tt.fail, "Permission check failed (/nodes, Sys.Audit)")
+			}
+
+			_, err := newTestClient(t, s, token).GetNodes(context.Background())
+			if !tt.check(err) {
+				t.Fatalf("got %v", err)
+			}
+			var apiErr *api.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got %T, want *api.Error", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Method != "GET" || apiErr.Path != "/nodes" {
+				t.Errorf("got %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestCertificatePinning(t *testing.T) {
+	s := newFakeServer(t)
+	host, port := s.HostPort()
+	ctx := context.Background()
+
+	fingerprint, err := api.FetchFingerprint(ctx, host, port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint != s.Fingerprint() {
+		t.Errorf("fetched %s, want %s", fingerprint, s.Fingerprint())
+	}
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		mismatch    bool
+	}{
+		{"pinned", s.Fingerprint(), false},
+		{"lower case with dashes", strings.ReplaceAll(strings.ToLower(s.Fingerprint()), ":", "-"), false},
+		{"other certificate", strings.Repeat("AB:", 31) + "AB", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := api.NewClientWithToken(host, port, s.Token())
+			if err := client.ConfigureTLS(api.TLSConfig{Fingerprint: tt.fingerprint}); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := client.GetNodes(ctx)
+			if api.IsCertificateMismatch(err) != tt.mismatch {
+				t.Errorf("got %v, want mismatch %v", err, tt.mismatch)
+			}
+		})
+	}
+
+	unpinned := api.NewClientWithToken(host, port, s.Token())
+	if _, err := unpinned.GetNodes(ctx); err == nil {
+		t.Error("an unpinned client accepted the self-signed certificate")
+	}
+}
+
+func TestClusterConfigIsCached(t *testing.T) {
+	s := newFakeServer(t)
+	client := newTestClient(t, s, s.Token())
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		status, err := client.GetClusterStatus(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !status.Quorate || status.ExpectedVotes != 3 {
+			t.Fatalf("status = %+v, want 3 votes", status)
+		}
+	}
+	if got := s.Requests("GET", "/cluster/status"); got != 5 {
+		t.Errorf("cluster status fetched %d times, want 5", got)
+	}
+	if got := s.Requests("GET", "/cluster/config/nodes"); got != 1 {
+		t.Errorf("cluster config fetched %d times, want 1", got)
+	}
+}
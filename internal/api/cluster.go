@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const clusterConfigRefresh = 5 * time.Minute
+
+type clusterStatusEntry struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	NodeID  int    `json:"nodeid"`
+	IP      string `json:"ip"`
+	Online  int    `json:"online"`
+	Local   int    `json:"local"`
+	Quorate int    `json:"quorate"`
+	Version int    `json:"version"`
+}
+
+type clusterConfigNode struct {
+	Node        string      `json:"node"`
+	QuorumVotes json.Number `json:"quorum_votes"`
+}
+
+func (c *Client) quorumVotes(ctx context.Context, nodes []models.ClusterNode) (map[string]int, error) {
+	c.votesMu.Lock()
+	defer c.votesMu.Unlock()
+
+	fresh := c.votes != nil && time.Since(c.votesAt) < clusterConfigRefresh
+	for _, n := range nodes {
+		if _, ok := c.votes[n.Name]; !ok {
+			fresh = false
+		}
+	}
+	if fresh {
+		return c.votes, nil
+	}
+
+	var config []clusterConfigNode
+	if err := c.get(ctx, "/cluster/config/nodes", &config); err != nil {
+		return nil, err
+	}
+	votes := make(map[string]int, len(config))
+	for _, n := range config {
+		votes[n.Node] = 1
+		if v, err := n.QuorumVotes.Int64(); err == nil {
+			votes[n.Node] = int(v)
+		}
+	}
+	c.votes = votes
+	c.votesAt = time.Now()
+	return votes, nil
+}
+
+func (c *Client) GetClusterStatus(ctx context.Context) (*models.ClusterStatus, error) {
+	var entries []clusterStatusEntry
+	if err := c.get(ctx, "/cluster/status", &entries); err != nil {
+		return nil, err
+	}
+
+	status := &models.ClusterStatus{Quorate: true}
+	for _, e := range entries {
+		switch e.Type {
+		case "cluster":
+			status.Name = e.Name
+			status.Quorate = e.Quorate == 1
+			status.Version = e.Version
+		case "node":
+			status.Nodes = append(status.Nodes, models.ClusterNode{
+				Name:   e.Name,
+				NodeID: e.NodeID,
+				IP:     e.IP,
+				Online: e.Online == 1,
+				Local:  e.Local == 1,
+				Votes:  1,
+			})
+		}
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool {
+		return status.Nodes[i].Name < status.Nodes[j].Name
+	})
+
+	// /cluster/status has no vote counts; corosync's per-node votes are
+	// in the cluster config, which a standalone node does not have.
+	if status.Name != "" {
+		if votes, err := c.quorumVotes(ctx, status.Nodes); err == nil {
+			for i, n := range status.Nodes {
+				if v, ok := votes[n.Name]; ok {
+					status.Nodes[i].Votes = v
+				}
+			}
+		} else if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	for _, n := range status.Nodes {
+		status.ExpectedVotes += n.Votes
+		if n.Online {
+			status.TotalVotes += n.Votes
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+var (
+	diskKeyPattern = regexp.MustCompile(`^(ide|sata|scsi|virtio|mp|unused)\d+$|^(efidisk0|tpmstate0|rootfs)$`)
+	nicKeyPattern  = regexp.MustCompile(`^net\d+$`)
+	nicModels      = map[string]bool{"virtio": true, "e1000": true, "e1000e": true, "rtl8139": true, "vmxnet3": true}
+)
+
+func (c *Client) GetGuestConfig(ctx context.Context, node, guestType string, vmid int) (*models.GuestConfig, error) {
+	var raw map[string]any
+	if err := c.get(ctx, guestPath(node, guestType, vmid)+"/config", &raw); err != nil {
+		return nil, err
+	}
+
+	return parseGuestConfig(raw), nil
+}
+
+func (c *Client) SetGuestTags(ctx context.Context, node, guestType string, vmid int, tags []string, digest string) error {
+	data := url.Values{}
+	if len(tags) == 0 {
+		data.Set("delete", "tags")
+	} else {
+		data.Set("tags", strings.Join(tags, ";"))
+	}
+	if digest != "" {
+		data.Set("digest", digest)
+	}
+
+	return c.call(ctx, "PUT", guestPath(node, guestType, vmid)+"/config", data, nil)
+}
+
+func parseGuestConfig(raw map[string]any) *models.GuestConfig {
+	cfg := &models.GuestConfig{Raw: make(map[string]string, len(raw))}
+	for k, v := range raw {
+		switch v := v.(type) {
+		case float64:
+			cfg.Raw[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			cfg.Raw[k] = v
+		default:
+			cfg.Raw[k] = fmt.Sprint(v)
+		}
+	}
+
+	cfg.Name = cfg.Raw["name"]
+	if cfg.Name == "" {
+		cfg.Name = cfg.Raw["hostname"]
+	}
+	cfg.OSType = cfg.Raw["ostype"]
+	cfg.CPUType = defaultOption(cfg.Raw["cpu"], "cputype")
+	if cfg.CPUType == "" {
+		cfg.CPUType = cfg.Raw["arch"]
+	}
+	cfg.Sockets = atoiDefault(cfg.Raw["sockets"], 1)
+	cfg.Cores = atoiDefault(cfg.Raw["cores"], 1)
+	cfg.Memory = int64(atoiDefault(defaultOption(cfg.Raw["memory"], "current"), 0))
+	cfg.Balloon = int64(atoiDefault(cfg.Raw["balloon"], -1))
+	cfg.Swap = int64(atoiDefault(cfg.Raw["swap"], 0))
+	cfg.Boot = cfg.Raw["boot"]
+	cfg.OnBoot = cfg.Raw["onboot"] == "1"
+	cfg.Description = cfg.Raw["description"]
+	cfg.Tags = ParseTags(cfg.Raw["tags"])
+
+	var keys []string
+	for k := range cfg.Raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch {
+		case diskKeyPattern.MatchString(k):
+			cfg.Disks = append(cfg.Disks, parseDisk(k, cfg.Raw[k]))
+		case nicKeyPattern.MatchString(k):
+			cfg.NICs = append(cfg.NICs, parseNIC(k, cfg.Raw[k]))
+		}
+	}
+
+	return cfg
+}
+
+func ParseTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ';' || r == ',' || r == ' '
+	})
+}
+
+func atoiDefault(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func splitOptions(value string) (string, map[string]string, []string) {
+	parts := strings.Split(value, ",")
+	opts := make(map[string]string)
+	var order []string
+	first := ""
+	for i, part := range parts {
+		k, v, hasValue := strings.Cut(part, "=")
+		if i == 0 && !hasValue {
+			first = part
+			continue
+		}
+		opts[k] = v
+		order = append(order, k)
+	}
+	return first, opts, order
+}
+
+// PVE 8 writes some settings as property strings, e.g. "memory: current=4096"
+// or "cpu: cputype=host,flags=+aes", where the bare form is the default key.
+func defaultOption(value, key string) string {
+	first, opts, _ := splitOptions(value)
+	if first == "" {
+		return opts[key]
+	}
+	return first
+}
+
+func parseDisk(key, value string) models.GuestDisk {
+	volume, opts, order := splitOptions(value)
+	disk := models.GuestDisk{
+		Key:    key,
+		Volume: volume,
+		Size:   opts["size"],
+		Media:  opts["media"],
+		Mount:  opts["mp"],
+	}
+	if disk.Volume == "" {
+		disk.Volume = opts["file"]
+	}
+
+	var extra []string
+	for _, k := range order {
+		switch k {
+		case "size", "media", "mp", "file":
+			continue
+		}
+		extra = append(extra, k+"="+opts[k])
+	}
+	disk.Options = strings.Join(extra, ",")
+	return disk
+}
+
+func parseNIC(key, value string) models.GuestNIC {
+	_, opts, order := splitOptions(value)
+	nic := models.GuestNIC{
+		Key:      key,
+		Name:     opts["name"],
+		Model:    opts["type"],
+		MAC:      opts["hwaddr"],
+		Bridge:   opts["bridge"],
+		VLAN:     opts["tag"],
+		IP:       opts["ip"],
+		Firewall: opts["firewall"] == "1",
+	}
+	for _, k := range order {
+		if nicModels[k] {
+			nic.Model = k
+			nic.MAC = opts[k]
+		}
+	}
+	return nic
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+func TestParseGuestConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  map[string]any
+		want models.GuestConfig
+	}{
+		{
+			name: "qemu",
+			raw: map[string]any{
+				"name":    "web-01",
+				"ostype":  "l26",
+				"cpu":     "x86-64-v2-AES",
+				"sockets": float64(2),
+				"cores":   float64(4),
+				"memory":  "8192",
+				"balloon": float64(2048),
+				"boot":    "order=scsi0;net0",
+				"onboot":  float64(1),
+				"tags":    "prod;web",
+				"scsi0":   "local-lvm:vm-100-disk-0,iothread=1,size=32G",
+				"ide2":    "nfs-iso:iso/debian.iso,media=cdrom",
+				"net0":    "virtio=BC:24:11:00:00:01,bridge=vmbr0,firewall=1,tag=20",
+			},
+			want: models.GuestConfig{
+				Name:    "web-01",
+				OSType:  "l26",
+				CPUType: "x86-64-v2-AES",
+				Sockets: 2,
+				Cores:   4,
+				Memory:  8192,
+				Balloon: 2048,
+				Boot:    "order=scsi0;net0",
+				OnBoot:  true,
+				Tags:    []string{"prod", "web"},
+				Disks: []models.GuestDisk{
+					{Key: "ide2", Volume: "nfs-iso:iso/debian.iso", Media: "cdrom"},
+					{Key: "scsi0", Volume: "local-lvm:vm-100-disk-0", Size: "32G", Options: "iothread=1"},
+				},
+				NICs: []models.GuestNIC{
+					{Key: "net0", Model: "virtio", MAC: "BC:24:11:00:00:01", Bridge: "vmbr0", VLAN: "20", Firewall: true},
+				},
+			},
+		},
+		{
+			name: "qemu property strings",
+			raw: map[string]any{
+				"name":   "db-01",
+				"cpu":    "cputype=host,flags=+aes",
+				"memory": "current=4096",
+			},
+			want: models.GuestConfig{
+				Name:    "db-01",
+				CPUType: "host",
+				Sockets: 1,
+				Cores:   1,
+				Memory:  4096,
+				Balloon: -1,
+				Tags:    []string{},
+			},
+		},
+		{
+			name: "lxc",
+			raw: map[string]any{
+				"hostname": "dns",
+				"ostype":   "debian",
+				"arch":     "amd64",
+				"cores":    float64(2),
+				"memory":   float64(512),
+				"swap":     float64(256),
+				"rootfs":   "local-lvm:subvol-200-disk-0,size=8G",
+				"mp0":      "ceph-vm:subvol-200-disk-1,mp=/srv,backup=1,size=16G",
+				"net0":     "name=eth0,bridge=vmbr0,hwaddr=BC:24:11:00:00:02,ip=dhcp,type=veth",
+			},
+			want: models.GuestConfig{
+				Name:    "dns",
+				OSType:  "debian",
+				CPUType: "amd64",
+				Sockets: 1,
+				Cores:   2,
+				Memory:  512,
+				Balloon: -1,
+				Swap:    256,
+				Tags:    []string{},
+				Disks: []models.GuestDisk{
+					{Key: "mp0", Volume: "ceph-vm:subvol-200-disk-1", Size: "16G", Mount: "/srv", Options: "backup=1"},
+					{Key: "rootfs", Volume: "local-lvm:subvol-200-disk-0", Size: "8G"},
+				},
+				NICs: []models.GuestNIC{
+					{Key: "net0", Name: "eth0", Model: "veth", MAC: "BC:24:11:00:00:02", Bridge: "vmbr0", IP: "dhcp"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *parseGuestConfig(tt.raw)
+			got.Raw = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGuestConfig =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDisk(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       models.GuestDisk
+	}{
+		{"virtio0", "ceph-vm:vm-101-disk-0,cache=writeback,discard=on,size=100G", models.GuestDisk{Key: "virtio0", Volume: "ceph-vm:vm-101-disk-0", Size: "100G", Options: "cache=writeback,discard=on"}},
+		{"ide2", "none,media=cdrom", models.GuestDisk{Key: "ide2", Volume: "none", Media: "cdrom"}},
+		{"efidisk0", "file=local-lvm:vm-101-disk-1,efitype=4m,size=4M", models.GuestDisk{Key: "efidisk0", Volume: "local-lvm:vm-101-disk-1", Size: "4M", Options: "efitype=4m"}},
+		{"mp1", "/mnt/data,mp=/data", models.GuestDisk{Key: "mp1", Volume: "/mnt/data", Mount: "/data"}},
+		{"unused0", "local-lvm:vm-101-disk-2", models.GuestDisk{Key: "unused0", Volume: "local-lvm:vm-101-disk-2"}},
+	}
+	for _, tt := range tests {
+		if got := parseDisk(tt.key, tt.value); got != tt.want {
+			t.Errorf("parseDisk(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseNIC(t *testing.T) {
+	tests := []struct {
+		value string
+		want  models.GuestNIC
+	}{
+		{"virtio=BC:24:11:AA:BB:CC,bridge=vmbr0", models.GuestNIC{Key: "net0", Model: "virtio", MAC: "BC:24:11:AA:BB:CC", Bridge: "vmbr0"}},
+		{"e1000=BC:24:11:AA:BB:CC,bridge=vmbr1,firewall=0,tag=5", models.GuestNIC{Key: "net0", Model: "e1000", MAC: "BC:24:11:AA:BB:CC", Bridge: "vmbr1", VLAN: "5"}},
+		{"name=eth0,bridge=vmbr0,firewall=1,hwaddr=BC:24:11:AA:BB:CC,ip=10.0.0.5/24,type=veth", models.GuestNIC{Key: "net0", Name: "eth0", Model: "veth", MAC: "BC:24:11:AA:BB:CC", Bridge: "vmbr0", IP: "10.0.0.5/24", Firewall: true}},
+	}
+	for _, tt := range tests {
+		if got := parseNIC("net0", tt.value); got != tt.want {
+			t.Errorf("parseNIC(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
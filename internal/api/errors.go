@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Error struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Message    string
+	Errors     map[string]string
+}
+
+func (e *Error) Error() string {
+	var msg string
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		msg = "authentication failed: API token revoked, expired or invalid"
+	case http.StatusForbidden:
+		msg = fmt.Sprintf("permission denied on %s", e.Path)
+	case http.StatusNotFound:
+		msg = fmt.Sprintf("%s not found", e.Path)
+	default:
+		msg = fmt.Sprintf("%s %s: HTTP %d", e.Method, e.Path, e.StatusCode)
+	}
+
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	if len(e.Errors) > 0 {
+		var fields []string
+		for field, reason := range e.Errors {
+			fields = append(fields, fmt.Sprintf("%s: %s", field, reason))
+		}
+		sort.Strings(fields)
+		msg += " (" + strings.Join(fields, "; ") + ")"
+	}
+
+	return msg
+}
+
+func IsUnauthorized(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+func IsForbidden(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+func checkResponse(resp *http.Response, method, path string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       strings.SplitN(path, "?", 2)[0],
+	}
+
+	var payload struct {
+		Message string            `json:"message"`
+		Errors  map[string]string `json:"errors"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = strings.TrimSpace(payload.Message)
+		apiErr.Errors = payload.Errors
+	}
+
+	// Proxmox puts the reason in the status line, e.g.
+	// "403 Permission check failed (/nodes/pve, Sys.Audit)".
+	if apiErr.Message == "" {
+		reason := strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode))
+		reason = strings.TrimSpace(reason)
+		if reason != http.StatusText(resp.StatusCode) {
+			apiErr.Message = reason
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type haStatusEntry struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Node         string `json:"node"`
+	Status       string `json:"status"`
+	Quorate      int    `json:"quorate"`
+	Timestamp    int64  `json:"timestamp"`
+	SID          string `json:"sid"`
+	State        string `json:"state"`
+	CRMState     string `json:"crm_state"`
+	RequestState string `json:"request_state"`
+	MaxRestart   int    `json:"max_restart"`
+	MaxRelocate  int    `json:"max_relocate"`
+	Group        string `json:"group"`
+}
+
+type haResource struct {
+	SID         string `json:"sid"`
+	Type        string `json:"type"`
+	State       string `json:"state"`
+	Group       string `json:"group"`
+	MaxRestart  int    `json:"max_restart"`
+	MaxRelocate int    `json:"max_relocate"`
+	Comment     string `json:"comment"`
+}
+
+func haStatusWord(status string) string {
+	// Status lines look like "pve1 (active, Fri Oct 16 12:00:00 2026)".
+	open := strings.Index(status, "(")
+	if open < 0 {
+		return ""
+	}
+	word := status[open+1:]
+	if end := strings.IndexAny(word, ",)"); end >= 0 {
+		word = word[:end]
+	}
+	return strings.TrimSpace(word)
+}
+
+func haVMID(sid string) int {
+	_, id, _ := strings.Cut(sid, ":")
+	vmid, _ := strconv.Atoi(id)
+	return vmid
+}
+
+func (c *Client) GetHAStatus(ctx context.Context) (*models.HAStatus, error) {
+	var entries []haStatusEntry
+	if err := c.get(ctx, "/cluster/ha/status/current", &entries); err != nil {
+		return nil, err
+	}
+	var resources []haResource
+	if err := c.get(ctx, "/cluster/ha/resources", &resources); err != nil {
+		return nil, err
+	}
+
+	status := &models.HAStatus{}
+	services := make(map[string]haStatusEntry)
+	for _, e := range entries {
+		switch e.Type {
+		case "quorum":
+			status.Quorate = e.Quorate == 1
+			status.QuorumNode = e.Node
+		case "master":
+			status.Master = e.Node
+			status.MasterStatus = haStatusWord(e.Status)
+			status.MasterTimestamp = e.Timestamp
+		case "lrm":
+			state := e.State
+			if state == "" {
+				state = haStatusWord(e.Status)
+			}
+			status.LRMs = append(status.LRMs, models.HALRM{Node: e.Node, State: state, Status: e.Status, Timestamp: e.Timestamp})
+		case "service":
+			services[e.SID] = e
+		}
+	}
+	sort.Slice(status.LRMs, func(i, j int) bool {
+		return status.LRMs[i].Node < status.LRMs[j].Node
+	})
+
+	for _, r := range resources {
+		res := models.HAResource{
+			SID:         r.SID,
+			Type:        r.Type,
+			VMID:        haVMID(r.SID),
+			Requested:   r.State,
+			Group:       r.Group,
+			MaxRestart:  r.MaxRestart,
+			MaxRelocate: r.MaxRelocate,
+			Comment:     r.Comment,
+		}
+		if res.Requested == "" {
+			res.Requested = "started"
+		}
+		if svc, ok := services[r.SID]; ok {
+			res.Node = svc.Node
+			res.State = svc.State
+			if res.State == "" {
+				res.State = svc.CRMState
+			}
+		}
+		status.Resources = append(status.Resources, res)
+	}
+	sort.Slice(status.Resources, func(i, j int) bool {
+		return status.Resources[i].VMID < status.Resources[j].VMID
+	})
+	return status, nil
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type migratePrecondition struct {
+	Running         json.RawMessage `json:"running"`
+	AllowedNodes    []string        `json:"allowed_nodes"`
+	NotAllowedNodes map[string]struct {
+		UnavailableStorages  []string `json:"unavailable_storages"`
+		UnavailableResources []string `json:"unavailable_resources"`
+	} `json:"not_allowed_nodes"`
+	LocalDisks []struct {
+		VolID string `json:"volid"`
+	} `json:"local_disks"`
+	LocalResources []string `json:"local_resources"`
+}
+
+func (c *Client) GetMigratePrecondition(ctx context.Context, node string, vmid int, target string) (*models.MigratePrecondition, error) {
+	path := guestPath(node, "qemu", vmid) + "/migrate"
+	if target != "" {
+		path += "?" + url.Values{"target": {target}}.Encode()
+	}
+
+	var raw migratePrecondition
+	if err := c.get(ctx, path, &raw); err != nil {
+		return nil, err
+	}
+
+	running := strings.Trim(string(raw.Running), `"`)
+	pre := &models.MigratePrecondition{
+		Running:         running == "1" || running == "true",
+		AllowedNodes:    raw.AllowedNodes,
+		NotAllowedNodes: make(map[string]string),
+		LocalResources:  raw.LocalResources,
+	}
+	for name, reason := range raw.NotAllowedNodes {
+		var parts []string
+		if len(reason.UnavailableStorages) > 0 {
+			parts = append(parts, "storage unavailable: "+strings.Join(reason.UnavailableStorages, ", "))
+		}
+		if len(reason.UnavailableResources) > 0 {
+			parts = append(parts, "resources unavailable: "+strings.Join(reason.UnavailableResources, ", "))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "not allowed")
+		}
+		pre.NotAllowedNodes[name] = strings.Join(parts, "; ")
+	}
+	for _, disk := range raw.LocalDisks {
+		pre.LocalDisks = append(pre.LocalDisks, disk.VolID)
+	}
+	sort.Strings(pre.AllowedNodes)
+	return pre, nil
+}
+
+func (c *Client) MigrateGuest(ctx context.Context, node, guestType string, vmid int, target string, live, withLocalDisks bool) (string, error) {
+	if guestType != "qemu" && guestType != "lxc" {
+		return "", fmt.Errorf("unknown guest type %q", guestType)
+	}
+
+	data := url.Values{}
+	data.Set("target", target)
+	if live {
+		if guestType == "lxc" {
+			data.Set("restart", "1")
+		} else {
+			data.Set("online", "1")
+		}
+	}
+	if withLocalDisks && guestType == "qemu" {
+		data.Set("with-local-disks", "1")
+	}
+
+	var upid string
+	if err := c.call(ctx, "POST", guestPath(node, guestType, vmid)+"/migrate", data, &upid); err != nil {
+		return "", err
+	}
+	return upid, nil
+}
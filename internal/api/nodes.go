@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type nodeUsage struct {
+	Total int64 `json:"total"`
+	Used  int64 `json:"used"`
+}
+
+type nodeStatus struct {
+	CPU     float64       `json:"cpu"`
+	Wait    float64       `json:"wait"`
+	LoadAvg []json.Number `json:"loadavg"`
+	CPUInfo struct {
+		Model   string      `json:"model"`
+		MHz     json.Number `json:"mhz"`
+		Sockets int         `json:"sockets"`
+		Cores   int         `json:"cores"`
+		CPUs    int         `json:"cpus"`
+	} `json:"cpuinfo"`
+	KVersion      string `json:"kversion"`
+	CurrentKernel struct {
+		Release string `json:"release"`
+	} `json:"current-kernel"`
+	BootInfo struct {
+		Mode       string `json:"mode"`
+		SecureBoot int    `json:"secureboot"`
+	} `json:"boot-info"`
+	PVEVersion string    `json:"pveversion"`
+	Memory     nodeUsage `json:"memory"`
+	Swap       nodeUsage `json:"swap"`
+	RootFS     nodeUsage `json:"rootfs"`
+	KSM        struct {
+		Shared int64 `json:"shared"`
+	} `json:"ksm"`
+	Uptime int64 `json:"uptime"`
+}
+
+type pressurePoint struct {
+	CPUSome    *float64 `json:"pressurecpusome"`
+	IOSome     *float64 `json:"pressureiosome"`
+	IOFull     *float64 `json:"pressureiofull"`
+	MemorySome *float64 `json:"pressurememorysome"`
+	MemoryFull *float64 `json:"pressurememoryfull"`
+}
+
+func pveVersion(raw string) string {
+	// "pve-manager/8.2.7/3e0176e6bb2ade3b"
+	parts := strings.Split(raw, "/")
+	if len(parts) >= 2 {
+		return parts[1]
+	}
+	return raw
+}
+
+func (c *Client) GetNodeStatus(ctx context.Context, node string) (*models.NodeStatus, error) {
+	var raw nodeStatus
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/status", url.PathEscape(node)), &raw); err != nil {
+		return nil, err
+	}
+
+	status := &models.NodeStatus{
+		Node:       node,
+		CPUModel:   raw.CPUInfo.Model,
+		Sockets:    raw.CPUInfo.Sockets,
+		Cores:      raw.CPUInfo.Cores,
+		CPUs:       raw.CPUInfo.CPUs,
+		Kernel:     raw.CurrentKernel.Release,
+		PVEVersion: pveVersion(raw.PVEVersion),
+		BootMode:   raw.BootInfo.Mode,
+		SecureBoot: raw.BootInfo.SecureBoot == 1,
+		CPU:        raw.CPU,
+		IOWait:     raw.Wait,
+		MemUsed:    raw.Memory.Used,
+		MemTotal:   raw.Memory.Total,
+		SwapUsed:   raw.Swap.Used,
+		SwapTotal:  raw.Swap.Total,
+		RootUsed:   raw.RootFS.Used,
+		RootTotal:  raw.RootFS.Total,
+		KSMShared:  raw.KSM.Shared,
+		Uptime:     raw.Uptime,
+	}
+	status.CPUMHz, _ = raw.CPUInfo.MHz.Float64()
+	if status.Kernel == "" {
+		status.Kernel = raw.KVersion
+	}
+	for i, load := range raw.LoadAvg {
+		if i < len(status.LoadAvg) {
+			status.LoadAvg[i], _ = load.Float64()
+		}
+	}
+
+	// The status call has no PSI; nodes that collect it report the
+	// pressure averages alongside the other metrics in their RRD data.
+	query := url.Values{}
+	query.Set("timeframe", TimeframeHour)
+	query.Set("cf", "AVERAGE")
+	var points []pressurePoint
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/rrddata?%s", url.PathEscape(node), query.Encode()), &points); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return status, nil
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		p := points[i]
+		if p.CPUSome == nil {
+			continue
+		}
+		status.Pressure = &models.NodePressure{
+			CPUSome:    *p.CPUSome,
+			IOSome:     valueOrZero(p.IOSome),
+			IOFull:     valueOrZero(p.IOFull),
+			MemorySome: valueOrZero(p.MemorySome),
+			MemoryFull: valueOrZero(p.MemoryFull),
+		}
+		break
+	}
+	return status, nil
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const defaultReplicationSchedule = "*/15"
+
+type replicationJob struct {
+	ID        string  `json:"id"`
+	Guest     int     `json:"guest"`
+	JobNum    int     `json:"jobnum"`
+	VMType    string  `json:"vmtype"`
+	Source    string  `json:"source"`
+	Target    string  `json:"target"`
+	Schedule  string  `json:"schedule"`
+	Comment   string  `json:"comment"`
+	Disable   int     `json:"disable"`
+	LastSync  int64   `json:"last_sync"`
+	LastTry   int64   `json:"last_try"`
+	NextSync  int64   `json:"next_sync"`
+	Duration  float64 `json:"duration"`
+	FailCount int     `json:"fail_count"`
+	Error     string  `json:"error"`
+	PID       int     `json:"pid"`
+}
+
+func (j replicationJob) job(node string) models.ReplicationJob {
+	job := models.ReplicationJob{
+		ID:        j.ID,
+		Guest:     j.Guest,
+		JobNum:    j.JobNum,
+		GuestType: j.VMType,
+		Source:    j.Source,
+		Target:    j.Target,
+		Schedule:  j.Schedule,
+		Comment:   j.Comment,
+		Disabled:  j.Disable == 1,
+		LastSync:  j.LastSync,
+		LastTry:   j.LastTry,
+		NextSync:  j.NextSync,
+		Duration:  j.Duration,
+		FailCount: j.FailCount,
+		Error:     j.Error,
+		Running:   j.PID != 0,
+	}
+	if job.Source == "" {
+		job.Source = node
+	}
+	if job.Schedule == "" {
+		job.Schedule = defaultReplicationSchedule
+	}
+	return job
+}
+
+func (c *Client) GetNodeReplication(ctx context.Context, node string) ([]models.ReplicationJob, error) {
+	var raw []replicationJob
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/replication", url.PathEscape(node)), &raw); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]models.ReplicationJob, len(raw))
+	for i, j := range raw {
+		jobs[i] = j.job(node)
+	}
+	return jobs, nil
+}
+
+type ReplicationResult struct {
+	Jobs       []models.ReplicationJob
+	NodeErrors map[string]error
+}
+
+func (c *Client) GetReplication(ctx context.Context) (*ReplicationResult, error) {
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplicationResult{
+		Jobs:       []models.ReplicationJob{},
+		NodeErrors: make(map[string]error),
+	}
+
+	var names []string
+	for _, node := range nodes {
+		if node.Status != "" && node.Status != "online" {
+			result.NodeErrors[node.Node] = fmt.Errorf("node is %s", node.Status)
+			continue
+		}
+		names = append(names, node.Node)
+	}
+
+	perNode := make([][]models.ReplicationJob, len(names))
+	errs := c.forEach(ctx, len(names), func(i int) error {
+		jobs, err := c.GetNodeReplication(ctx, names[i])
+		perNode[i] = jobs
+		return err
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	failed := 0
+	for i, name := range names {
+		if errs[i] != nil {
+			result.NodeErrors[name] = errs[i]
+			failed++
+		}
+		result.Jobs = append(result.Jobs, perNode[i]...)
+	}
+	if failed > 0 && failed == len(names) {
+		return result, fmt.Errorf("no online node answered (%s: %w)", names[0], errs[0])
+	}
+
+	sort.Slice(result.Jobs, func(i, j int) bool {
+		if result.Jobs[i].Guest != result.Jobs[j].Guest {
+			return result.Jobs[i].Guest < result.Jobs[j].Guest
+		}
+		return result.Jobs[i].JobNum < result.Jobs[j].JobNum
+	})
+	return result, nil
+}
+
+func (c *Client) ScheduleReplication(ctx context.Context, node, id string) error {
+	path := fmt.Sprintf("/nodes/%s/replication/%s/schedule_now", url.PathEscape(node), url.PathEscape(id))
+	return c.call(ctx, "POST", path, url.Values{}, nil)
+}
+
+func (c *Client) GetReplicationLog(ctx context.Context, node, id string) ([]models.TaskLogLine, error) {
+	var lines []models.TaskLogLine
+	if err := c.get(ctx, fmt.Sprintf("/nodes/%s/replication/%s/log", url.PathEscape(node), url.PathEscape(id)), &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
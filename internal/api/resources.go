@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type resource struct {
+	ID         string  `json:"id"`
+	Type       string  `json:"type"`
+	Node       string  `json:"node"`
+	VMID       int     `json:"vmid"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	CPU        float64 `json:"cpu"`
+	MaxCPU     int     `json:"maxcpu"`
+	Mem        int64   `json:"mem"`
+	MaxMem     int64   `json:"maxmem"`
+	Disk       int64   `json:"disk"`
+	MaxDisk    int64   `json:"maxdisk"`
+	DiskRead   *int64  `json:"diskread"`
+	DiskWrite  *int64  `json:"diskwrite"`
+	NetIn      *int64  `json:"netin"`
+	NetOut     *int64  `json:"netout"`
+	Uptime     int64   `json:"uptime"`
+	Storage    string  `json:"storage"`
+	PluginType string  `json:"plugintype"`
+	Shared     int     `json:"shared"`
+	Content    string  `json:"content"`
+	Tags       string  `json:"tags"`
+	Pool       string  `json:"pool"`
+}
+
+func (r resource) hasIOCounters() bool {
+	return r.DiskRead != nil && r.DiskWrite != nil && r.NetIn != nil && r.NetOut != nil
+}
+
+func (r resource) node() models.Node {
+	return models.Node{
+		Node:    r.Node,
+		Status:  r.Status,
+		CPU:     r.CPU,
+		MaxCPU:  r.MaxCPU,
+		Mem:     r.Mem,
+		MaxMem:  r.MaxMem,
+		Disk:    r.Disk,
+		MaxDisk: r.MaxDisk,
+		Uptime:  r.Uptime,
+	}
+}
+
+func (r resource) guest() models.Guest {
+	g := models.Guest{
+		VMID:    r.VMID,
+		Name:    r.Name,
+		Type:    r.Type,
+		Status:  r.Status,
+		Node:    r.Node,
+		CPU:     r.CPU,
+		CPUs:    r.MaxCPU,
+		Mem:     r.Mem,
+		MaxMem:  r.MaxMem,
+		Disk:    r.Disk,
+		MaxDisk: r.MaxDisk,
+		Uptime:  r.Uptime,
+		Tags:    r.Tags,
+		Pool:    r.Pool,
+	}
+	if r.DiskRead != nil {
+		g.DiskRead = *r.DiskRead
+	}
+	if r.DiskWrite != nil {
+		g.DiskWrite = *r.DiskWrite
+	}
+	if r.NetIn != nil {
+		g.NetIn = *r.NetIn
+	}
+	if r.NetOut != nil {
+		g.NetOut = *r.NetOut
+	}
+	return g
+}
+
+func (r resource) storage() models.Storage {
+	return models.Storage{
+		Storage: r.Storage,
+		Node:    r.Node,
+		Type:    r.PluginType,
+		Status:  r.Status,
+		Shared:  r.Shared == 1,
+		Content: r.Content,
+		Used:    r.Disk,
+		Total:   r.MaxDisk,
+		Avail:   r.MaxDisk - r.Disk,
+		Enabled: true,
+		Active:  r.Status == "available",
+	}
+}
+
+func (c *Client) GetClusterResources(ctx context.Context) (*models.ClusterResources, error) {
+	var resources []resource
+	if err := c.get(ctx, "/cluster/resources", &resources); err != nil {
+		return nil, err
+	}
+
+	res := &models.ClusterResources{}
+	var incomplete []int
+
+	for _, r := range resources {
+		switch r.Type {
+		case "node":
+			res.Nodes = append(res.Nodes, r.node())
+		case "qemu", "lxc":
+			if r.Status == "running" && !r.hasIOCounters() {
+				incomplete = append(incomplete, len(res.Guests))
+			}
+			res.Guests = append(res.Guests, r.guest())
+		case "storage":
+			res.Storage = append(res.Storage, r.storage())
+		}
+	}
+
+	c.forEach(ctx, len(incomplete), func(i int) error {
+		c.fillGuestStatus(ctx, &res.Guests[incomplete[i]])
+		return nil
+	})
+
+	sort.Slice(res.Nodes, func(i, j int) bool {
+		return res.Nodes[i].Node < res.Nodes[j].Node
+	})
+
+	return res, nil
+}
+
+func (c *Client) fillGuestStatus(ctx context.Context, guest *models.Guest) {
+	var status *models.GuestStatus
+	var err error
+	if guest.Type == "lxc" {
+		status, err = c.GetContainerStatus(ctx, guest.Node, guest.VMID)
+	} else {
+		status, err = c.GetVMStatus(ctx, guest.Node, guest.VMID)
+	}
+	if err != nil {
+		return
+	}
+	guest.DiskRead = status.DiskRead
+	guest.DiskWrite = status.DiskWrite
+	guest.NetIn = status.NetIn
+	guest.NetOut = status.NetOut
+}
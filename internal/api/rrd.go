@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const (
+	TimeframeHour = "hour"
+	TimeframeDay  = "day"
+	TimeframeWeek = "week"
+)
+
+type rrdPoint struct {
+	Time      int64    `json:"time"`
+	CPU       *float64 `json:"cpu"`
+	MaxCPU    *float64 `json:"maxcpu"`
+	Mem       *float64 `json:"mem"`
+	MaxMem    *float64 `json:"maxmem"`
+	MemUsed   *float64 `json:"memused"`
+	MemTotal  *float64 `json:"memtotal"`
+	DiskRead  *float64 `json:"diskread"`
+	DiskWrite *float64 `json:"diskwrite"`
+	NetIn     *float64 `json:"netin"`
+	NetOut    *float64 `json:"netout"`
+}
+
+func valueOrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func (p rrdPoint) point() models.RRDPoint {
+	point := models.RRDPoint{
+		Time:      p.Time,
+		CPU:       valueOrZero(p.CPU),
+		MaxCPU:    valueOrZero(p.MaxCPU),
+		Mem:       valueOrZero(p.Mem),
+		MaxMem:    valueOrZero(p.MaxMem),
+		DiskRead:  valueOrZero(p.DiskRead),
+		DiskWrite: valueOrZero(p.DiskWrite),
+		NetIn:     valueOrZero(p.NetIn),
+		NetOut:    valueOrZero(p.NetOut),
+	}
+	if p.MemUsed != nil {
+		point.Mem = *p.MemUsed
+		point.MaxMem = valueOrZero(p.MemTotal)
+	}
+	return point
+}
+
+func (c *Client) GetGuestRRD(ctx context.Context, node, guestType string, vmid int, timeframe string) ([]models.RRDPoint, error) {
+	return c.getRRD(ctx, guestPath(node, guestType, vmid), timeframe)
+}
+
+func (c *Client) GetNodeRRD(ctx context.Context, node, timeframe string) ([]models.RRDPoint, error) {
+	return c.getRRD(ctx, fmt.Sprintf("/nodes/%s", url.PathEscape(node)), timeframe)
+}
+
+func (c *Client) getRRD(ctx context.Context, path, timeframe string) ([]models.RRDPoint, error) {
+	query := url.Values{}
+	query.Set("timeframe", timeframe)
+	query.Set("cf", "AVERAGE")
+
+	var raw []rrdPoint
+	if err := c.get(ctx, path+"/rrddata?"+query.Encode(), &raw); err != nil {
+		return nil, err
+	}
+
+	points := make([]models.RRDPoint, 0, len(raw))
+	for _, p := range raw {
+		if p.CPU == nil {
+			continue
+		}
+		points = append(points, p.point())
+	}
+	return points, nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type snapshotEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parent      string `json:"parent"`
+	SnapTime    int64  `json:"snaptime"`
+	VMState     int    `json:"vmstate"`
+}
+
+func snapshotPath(node, guestType string, vmid int) string {
+	return guestPath(node, guestType, vmid) + "/snapshot"
+}
+
+func (c *Client) GetSnapshots(ctx context.Context, node, guestType string, vmid int) ([]models.Snapshot, error) {
+	var raw []snapshotEntry
+	if err := c.get(ctx, snapshotPath(node, guestType, vmid), &raw); err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]models.Snapshot, len(raw))
+	for i, e := range raw {
+		snapshots[i] = models.Snapshot{
+			Name:        e.Name,
+			Description: e.Description,
+			Parent:      e.Parent,
+			SnapTime:    e.SnapTime,
+			VMState:     e.VMState == 1,
+		}
+	}
+	return snapshots, nil
+}
+
+func (c *Client) CreateSnapshot(ctx context.Context, node, guestType string, vmid int, name, description string, vmstate bool) (string, error) {
+	data := url.Values{}
+	data.Set("snapname", name)
+	if description != "" {
+		data.Set("description", description)
+	}
+	if vmstate && guestType == "qemu" {
+		data.Set("vmstate", "1")
+	}
+
+	var upid string
+	if err := c.call(ctx, "POST", snapshotPath(node, guestType, vmid), data, &upid); err != nil {
+		return "", err
+	}
+	return upid, nil
+}
+
+func (c *Client) RollbackSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error) {
+	var upid string
+	if err := c.call(ctx, "POST", snapshotPath(node, guestType, vmid)+"/"+url.PathEscape(name)+"/rollback", url.Values{}, &upid); err != nil {
+		return "", err
+	}
+	return upid, nil
+}
+
+func (c *Client) DeleteSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error) {
+	var upid string
+	if err := c.call(ctx, "DELETE", snapshotPath(node, guestType, vmid)+"/"+url.PathEscape(name), nil, &upid); err != nil {
+		return "", err
+	}
+	return upid, nil
+}
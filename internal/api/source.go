@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type DataSource interface {
+	GetNodes(ctx context.Context) ([]models.Node, error)
+	GetNodeStatus(ctx context.Context, node string) (*models.NodeStatus, error)
+	GetClusterStatus(ctx context.Context) (*models.ClusterStatus, error)
+	GetClusterResources(ctx context.Context) (*models.ClusterResources, error)
+	GetAllGuests(ctx context.Context) (*GuestsResult, error)
+	GetVMStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error)
+	GetContainerStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error)
+	GetGuestConfig(ctx context.Context, node, guestType string, vmid int) (*models.GuestConfig, error)
+	SetGuestTags(ctx context.Context, node, guestType string, vmid int, tags []string, digest string) error
+	GetGuestRRD(ctx context.Context, node, guestType string, vmid int, timeframe string) ([]models.RRDPoint, error)
+	GetNodeRRD(ctx context.Context, node, timeframe string) ([]models.RRDPoint, error)
+	GetCeph(ctx context.Context) (*models.Ceph, error)
+	GetHAStatus(ctx context.Context) (*models.HAStatus, error)
+	GetReplication(ctx context.Context) (*ReplicationResult, error)
+	ScheduleReplication(ctx context.Context, node, id string) error
+	GetReplicationLog(ctx context.Context, node, id string) ([]models.TaskLogLine, error)
+	GuestAction(ctx context.Context, node, guestType string, vmid int, action string) (string, error)
+	GetMigratePrecondition(ctx context.Context, node string, vmid int, target string) (*models.MigratePrecondition, error)
+	MigrateGuest(ctx context.Context, node, guestType string, vmid int, target string, live, withLocalDisks bool) (string, error)
+	GetSnapshots(ctx context.Context, node, guestType string, vmid int) ([]models.Snapshot, error)
+	CreateSnapshot(ctx context.Context, node, guestType string, vmid int, name, description string, vmstate bool) (string, error)
+	RollbackSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error)
+	DeleteSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error)
+	GetBackups(ctx context.Context) (*BackupsResult, error)
+	StartBackup(ctx context.Context, node string, vmids []int, opts models.BackupOptions) (string, error)
+	GetTaskStatus(ctx context.Context, node, upid string) (*models.TaskStatus, error)
+	GetTasks(ctx context.Context) ([]models.TaskStatus, error)
+	GetTaskLog(ctx context.Context, node, upid string, start int) ([]models.TaskLogLine, error)
+}
+
+var _ DataSource = (*Client)(nil)
+var _ DataSource = (*StaticSource)(nil)
+
+var ErrReadOnly = errors.New("not available in a read-only recording")
+
+var ErrNotRecorded = errors.New("not part of the recording")
+
+type StaticSource struct {
+	mu        sync.RWMutex
+	resources models.ClusterResources
+}
+
+func NewStaticSource(resources models.ClusterResources) *StaticSource {
+	return &StaticSource{resources: resources}
+}
+
+func LoadStaticSource(path string) (*StaticSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var resources models.ClusterResources
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, fmt.Errorf("failed to parse recording %s: %w", path, err)
+	}
+
+	return NewStaticSource(resources), nil
+}
+
+func SaveRecording(path string, resources *models.ClusterResources) error {
+	data, err := json.MarshalIndent(resources, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func (s *StaticSource) Set(resources models.ClusterResources) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources = resources
+}
+
+func (s *StaticSource) GetNodes(ctx context.Context) ([]models.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.Node(nil), s.resources.Nodes...), nil
+}
+
+func (s *StaticSource) GetClusterResources(ctx context.Context) (*models.ClusterResources, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &models.ClusterResources{
+		Nodes:   append([]models.Node(nil), s.resources.Nodes...),
+		Guests:  append([]models.Guest(nil), s.resources.Guests...),
+		Storage: append([]models.Storage(nil), s.resources.Storage...),
+	}, nil
+}
+
+func (s *StaticSource) GetAllGuests(ctx context.Context) (*GuestsResult, error) {
+	res, err := s.GetClusterResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GuestsResult{
+		Nodes:      res.Nodes,
+		NodeErrors: make(map[string]error),
+	}
+	for _, node := range res.Nodes {
+		if node.Status != "online" {
+			result.NodeErrors[node.Node] = fmt.Errorf("node is %s", node.Status)
+		}
+	}
+	for _, guest := range res.Guests {
+		if _, failed := result.NodeErrors[guest.Node]; !failed {
+			result.Guests = append(result.Guests, guest)
+		}
+	}
+
+	return result, nil
+}
+
+func notRecorded(path string) error {
+	return &Error{StatusCode: http.StatusNotFound, Method: "GET", Path: path, Message: "not part of the recording"}
+}
+
+func unrecorded(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return ErrNotRecorded
+}
+
+func (s *StaticSource) findGuest(ctx context.Context, node, guestType string, vmid int) (models.Guest, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Guest{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, g := range s.resources.Guests {
+		if g.VMID == vmid && g.Node == node && (guestType == "" || g.Type == guestType) {
+			return g, nil
+		}
+	}
+	return models.Guest{}, notRecorded(fmt.Sprintf("/nodes/%s/%s/%d", node, guestType, vmid))
+}
+
+func (s *StaticSource) findNode(ctx context.Context, name string) (models.Node, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Node{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, n := range s.resources.Nodes {
+		if n.Node == name {
+			return n, nil
+		}
+	}
+	return models.Node{}, notRecorded("/nodes/" + name)
+}
+
+func (s *StaticSource) guestStatus(ctx context.Context, guestType, node string, vmid int) (*models.GuestStatus, error) {
+	g, err := s.findGuest(ctx, node, guestType, vmid)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GuestStatus{
+		VMID:      g.VMID,
+		Name:      g.Name,
+		Status:    g.Status,
+		CPU:       g.CPU,
+		CPUs:      g.CPUs,
+		Mem:       g.Mem,
+		MaxMem:    g.MaxMem,
+		Disk:      g.Disk,
+		MaxDisk:   g.MaxDisk,
+		NetIn:     g.NetIn,
+		NetOut:    g.NetOut,
+		DiskRead:  g.DiskRead,
+		DiskWrite: g.DiskWrite,
+		Uptime:    g.Uptime,
+		PID:       g.PID,
+	}, nil
+}
+
+func (s *StaticSource) GetVMStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error) {
+	return s.guestStatus(ctx, "qemu", node, vmid)
+}
+
+func (s *StaticSource) GetContainerStatus(ctx context.Context, node string, vmid int) (*models.GuestStatus, error) {
+	return s.guestStatus(ctx, "lxc", node, vmid)
+}
+
+func (s *StaticSource) GuestAction(ctx context.Context, node, guestType string, vmid int, action string) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) GetMigratePrecondition(ctx context.Context, node string, vmid int, target string) (*models.MigratePrecondition, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) MigrateGuest(ctx context.Context, node, guestType string, vmid int, target string, live, withLocalDisks bool) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) GetSnapshots(ctx context.Context, node, guestType string, vmid int) ([]models.Snapshot, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) CreateSnapshot(ctx context.Context, node, guestType string, vmid int, name, description string, vmstate bool) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) RollbackSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) DeleteSnapshot(ctx context.Context, node, guestType string, vmid int, name string) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) GetBackups(ctx context.Context) (*BackupsResult, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) StartBackup(ctx context.Context, node string, vmids []int, opts models.BackupOptions) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (s *StaticSource) GetTaskStatus(ctx context.Context, node, upid string) (*models.TaskStatus, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetTasks(ctx context.Context) ([]models.TaskStatus, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetTaskLog(ctx context.Context, node, upid string, start int) ([]models.TaskLogLine, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetGuestConfig(ctx context.Context, node, guestType string, vmid int) (*models.GuestConfig, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) SetGuestTags(ctx context.Context, node, guestType string, vmid int, tags []string, digest string) error {
+	return ErrReadOnly
+}
+
+func (s *StaticSource) GetGuestRRD(ctx context.Context, node, guestType string, vmid int, timeframe string) ([]models.RRDPoint, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetNodeRRD(ctx context.Context, node, timeframe string) ([]models.RRDPoint, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetCeph(ctx context.Context) (*models.Ceph, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetHAStatus(ctx context.Context) (*models.HAStatus, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetClusterStatus(ctx context.Context) (*models.ClusterStatus, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetReplication(ctx context.Context) (*ReplicationResult, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) ScheduleReplication(ctx context.Context, node, id string) error {
+	return ErrReadOnly
+}
+
+func (s *StaticSource) GetReplicationLog(ctx context.Context, node, id string) ([]models.TaskLogLine, error) {
+	return nil, unrecorded(ctx)
+}
+
+func (s *StaticSource) GetNodeStatus(ctx context.Context, node string) (*models.NodeStatus, error) {
+	n, err := s.findNode(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NodeStatus{
+		Node:      n.Node,
+		CPUs:      n.MaxCPU,
+		CPU:       n.CPU,
+		MemUsed:   n.Mem,
+		MemTotal:  n.MaxMem,
+		RootUsed:  n.Disk,
+		RootTotal: n.MaxDisk,
+		Uptime:    n.Uptime,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+func testRecording() *StaticSource {
+	return NewStaticSource(models.ClusterResources{
+		Nodes: []models.Node{
+			{Node: "pve1", Status: "online", CPU: 0.25, MaxCPU: 8, Mem: 4 << 30, MaxMem: 16 << 30, Uptime: 100},
+			{Node: "pve2", Status: "online", MaxCPU: 8, MaxMem: 16 << 30},
+			{Node: "pve3", Status: "offline"},
+		},
+		Guests: []models.Guest{
+			{VMID: 100, Name: "web", Type: "qemu", Status: "running", Node: "pve1", CPUs: 2, MaxMem: 2 << 30, Tags: "prod;web"},
+			{VMID: 101, Name: "db", Type: "lxc", Status: "stopped", Node: "pve2", CPUs: 1, MaxMem: 1 << 30},
+		},
+	})
+}
+
+func TestStaticSourceReads(t *testing.T) {
+	src := testRecording()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"GetClusterResources", func() error { _, err := src.GetClusterResources(ctx); return err }, nil},
+		{"GetAllGuests", func() error { _, err := src.GetAllGuests(ctx); return err }, nil},
+		{"GetVMStatus", func() error { _, err := src.GetVMStatus(ctx, "pve1", 100); return err }, nil},
+		{"GetNodeStatus", func() error { _, err := src.GetNodeStatus(ctx, "pve1"); return err }, nil},
+		{"GetClusterStatus", func() error { _, err := src.GetClusterStatus(ctx); return err }, ErrNotRecorded},
+		{"GetTasks", func() error { _, err := src.GetTasks(ctx); return err }, ErrNotRecorded},
+		{"GetTaskStatus", func() error { _, err := src.GetTaskStatus(ctx, "pve1", "UPID:pve1:1"); return err }, ErrNotRecorded},
+		{"GetTaskLog", func() error { _, err := src.GetTaskLog(ctx, "pve1", "UPID:pve1:1", 0); return err }, ErrNotRecorded},
+		{"GetGuestConfig", func() error { _, err := src.GetGuestConfig(ctx, "pve1", "qemu", 100); return err }, ErrNotRecorded},
+		{"GetGuestRRD", func() error { _, err := src.GetGuestRRD(ctx, "pve1", "qemu", 100, TimeframeHour); return err }, ErrNotRecorded},
+		{"GetNodeRRD", func() error { _, err := src.GetNodeRRD(ctx, "pve1", TimeframeHour); return err }, ErrNotRecorded},
+		{"GetBackups", func() error { _, err := src.GetBackups(ctx); return err }, ErrNotRecorded},
+		{"GetCeph", func() error { _, err := src.GetCeph(ctx); return err }, ErrNotRecorded},
+		{"GetHAStatus", func() error { _, err := src.GetHAStatus(ctx); return err }, ErrNotRecorded},
+		{"GetReplication", func() error { _, err := src.GetReplication(ctx); return err }, ErrNotRecorded},
+		{"GetReplicationLog", func() error { _, err := src.GetReplicationLog(ctx, "pve1", "100-0"); return err }, ErrNotRecorded},
+		{"GetSnapshots", func() error { _, err := src.GetSnapshots(ctx, "pve1", "qemu", 100); return err }, ErrNotRecorded},
+		{"GetMigratePrecondition", func() error { _, err := src.GetMigratePrecondition(ctx, "pve1", 100, ""); return err }, ErrNotRecorded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticSourceMutationsAreReadOnly(t *testing.T) {
+	src := testRecording()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GuestAction", func() error { _, err := src.GuestAction(ctx, "pve1", "qemu", 100, "stop"); return err }},
+		{"MigrateGuest", func() error { _, err := src.MigrateGuest(ctx, "pve1", "qemu", 100, "pve2", true, false); return err }},
+		{"CreateSnapshot", func() error { _, err := src.CreateSnapshot(ctx, "pve1", "qemu", 100, "s1", "", false); return err }},
+		{"RollbackSnapshot", func() error { _, err := src.RollbackSnapshot(ctx, "pve1", "qemu", 100, "s1"); return err }},
+		{"DeleteSnapshot", func() error { _, err := src.DeleteSnapshot(ctx, "pve1", "qemu", 100, "s1"); return err }},
+		{"StartBackup", func() error { _, err := src.StartBackup(ctx, "pve1", []int{100}, models.BackupOptions{}); return err }},
+		{"SetGuestTags", func() error { return src.SetGuestTags(ctx, "pve1", "qemu", 100, nil, "") }},
+		{"ScheduleReplication", func() error { return src.ScheduleReplication(ctx, "pve1", "100-0") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("got %v, want ErrReadOnly", err)
+			}
+		})
+	}
+}
+
+func TestStaticSourceDerivedData(t *testing.T) {
+	src := testRecording()
+	ctx := context.Background()
+
+	status, err := src.GetNodeStatus(ctx, "pve1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.CPUs != 8 || status.MemTotal != 16<<30 || status.Uptime != 100 {
+		t.Errorf("node status = %+v", status)
+	}
+
+	var apiErr *Error
+	if _, err := src.GetVMStatus(ctx, "pve1", 999); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("unknown guest: got %v, want a 404", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := src.GetTasks(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const taskLogLimit = 500
+
+type taskEntry struct {
+	UPID      string `json:"upid"`
+	Node      string `json:"node"`
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	User      string `json:"user"`
+	Status    string `json:"status"`
+	StartTime int64  `json:"starttime"`
+	EndTime   int64  `json:"endtime"`
+}
+
+func (e taskEntry) task() models.TaskStatus {
+	task := models.TaskStatus{
+		UPID:      e.UPID,
+		Node:      e.Node,
+		Type:      e.Type,
+		ID:        e.ID,
+		User:      e.User,
+		Status:    "running",
+		StartTime: e.StartTime,
+		EndTime:   e.EndTime,
+	}
+	if e.EndTime > 0 || (e.Status != "" && e.Status != "running") {
+		task.Status = "stopped"
+		task.ExitStatus = e.Status
+	}
+	return task
+}
+
+func (c *Client) GetClusterTasks(ctx context.Context) ([]models.TaskStatus, error) {
+	var raw []taskEntry
+	if err := c.get(ctx, "/cluster/tasks", &raw); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.TaskStatus, len(raw))
+	for i, e := range raw {
+		tasks[i] = e.task()
+	}
+	return tasks, nil
+}
+
+func (c *Client) GetNodeTasks(ctx context.Context, node string, activeOnly bool) ([]models.TaskStatus, error) {
+	query := url.Values{}
+	if activeOnly {
+		query.Set("source", "active")
+	}
+	path := fmt.Sprintf("/nodes/%s/tasks", url.PathEscape(node))
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var raw []taskEntry
+	if err := c.get(ctx, path, &raw); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.TaskStatus, len(raw))
+	for i, e := range raw {
+		if e.Node == "" {
+			e.Node = node
+		}
+		tasks[i] = e.task()
+	}
+	return tasks, nil
+}
+
+func (c *Client) GetTasks(ctx context.Context) ([]models.TaskStatus, error) {
+	tasks, err := c.GetClusterTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, node := range nodes {
+		if node.Status == "" || node.Status == "online" {
+			names = append(names, node.Node)
+		}
+	}
+
+	active := make([][]models.TaskStatus, len(names))
+	c.forEach(ctx, len(names), func(i int) error {
+		running, err := c.GetNodeTasks(ctx, names[i], true)
+		active[i] = running
+		return err
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.UPID] = i
+	}
+	for _, list := range active {
+		for _, task := range list {
+			if i, ok := index[task.UPID]; ok {
+				tasks[i] = task
+				continue
+			}
+			index[task.UPID] = len(tasks)
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].StartTime > tasks[j].StartTime
+	})
+	return tasks, nil
+}
+
+func (c *Client) GetTaskLog(ctx context.Context, node, upid string, start int) ([]models.TaskLogLine, error) {
+	query := url.Values{}
+	query.Set("start", strconv.Itoa(start))
+	query.Set("limit", strconv.Itoa(taskLogLimit))
+	path := fmt.Sprintf("/nodes/%s/tasks/%s/log?%s", url.PathEscape(node), url.PathEscape(upid), query.Encode())
+
+	var lines []models.TaskLogLine
+	if err := c.get(ctx, path, &lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type TLSConfig struct {
+	Fingerprint string
+	CAFile      string
+}
+
+type CertificateMismatchError struct {
+	Expected string
+	Got      string
+}
+
+func (e *CertificateMismatchError) Error() string {
+	return fmt.Sprintf("server certificate changed: expected SHA-256 fingerprint %s, got %s", e.Expected, e.Got)
+}
+
+func IsCertificateMismatch(err error) bool {
+	var mismatch *CertificateMismatchError
+	return errors.As(err, &mismatch)
+}
+
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":")
+}
+
+func normalizeFingerprint(fp string) string {
+	fp = strings.ToUpper(strings.TrimSpace(fp))
+	return strings.ReplaceAll(fp, "-", ":")
+}
+
+func FetchFingerprint(ctx context.Context, host, port string) (string, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		Config:    &tls.Config{InsecureSkipVerify: true},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s:%s: %w", host, port, err)
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", fmt.Errorf("server at %s:%s presented no certificate", host, port)
+	}
+
+	return Fingerprint(certs[0]), nil
+}
+
+func newTLSClientConfig(cfg TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.Fingerprint != "" {
+		expected := normalizeFingerprint(cfg.Fingerprint)
+		if cfg.CAFile == "" {
+			// The pin replaces chain and hostname verification, which
+			// self-signed Proxmox certificates would never pass.
+			tlsConfig.InsecureSkipVerify = true
+		}
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			cert, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return err
+			}
+			if got := Fingerprint(cert); got != expected {
+				return &CertificateMismatchError{Expected: expected, Got: got}
+			}
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+func (c *Client) ConfigureTLS(cfg TLSConfig) error {
+	tlsConfig, err := newTLSClientConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	c.httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	return nil
+}
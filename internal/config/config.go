@@ -18,6 +18,8 @@ type Config struct {
 	Port	string `json:"port"`
 	Username string `json:"username"`
 	Token    string `json:"token"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	CAFile      string `json:"ca_file,omitempty"`
 }
 
 type EncryptedConfig struct {
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func getFiltersPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "filters.json"), nil
+}
+
+func LoadFilters() (map[string]string, error) {
+	filtersPath, err := getFiltersPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filters path: %w", err)
+	}
+
+	data, err := os.ReadFile(filtersPath)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved filters: %w", err)
+	}
+
+	filters := map[string]string{}
+	if err := json.Unmarshal(data, &filters); err != nil {
+		return nil, fmt.Errorf("failed to parse saved filters %s: %w", filtersPath, err)
+	}
+	return filters, nil
+}
+
+func SaveFilters(filters map[string]string) error {
+	filtersPath, err := getFiltersPath()
+	if err != nil {
+		return fmt.Errorf("failed to get filters path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filtersPath), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(filters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved filters: %w", err)
+	}
+
+	if err := os.WriteFile(filtersPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write saved filters: %w", err)
+	}
+	return nil
+}
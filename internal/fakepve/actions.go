@@ -0,0 +1,78 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var guestActions = map[string]bool{
+	"start":    true,
+	"shutdown": true,
+	"stop":     true,
+	"reboot":   true,
+	"suspend":  true,
+	"resume":   true,
+}
+
+func (s *Server) handleGuestAction(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+			return
+		}
+
+		action := params[2]
+		if !guestActions[action] {
+			writeError(w, http.StatusNotImplemented, fmt.Sprintf("Method 'POST status/%s' not implemented", action))
+			return
+		}
+
+		running := g.status == "running"
+		switch {
+		case action == "start" && running:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM %d already running", vmid))
+			return
+		case action != "start" && !running:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM %d not running", vmid))
+			return
+		}
+
+		prefix := "qm"
+		if kind == "lxc" {
+			prefix = "vz"
+		}
+
+		t := s.newTask(g.node, prefix+action, strconv.Itoa(vmid), time.Duration(1+s.rng.Intn(4))*time.Second, func() string {
+			switch action {
+			case "start":
+				g.status = "running"
+				g.uptime = 0
+				g.cpu = 0.05
+				g.pid = 1000 + s.rng.Intn(60000)
+			case "shutdown", "stop":
+				g.status = "stopped"
+				g.uptime = 0
+				g.cpu = 0
+				g.pid = 0
+				g.paused = false
+			case "reboot":
+				g.uptime = 0
+				g.paused = false
+			case "suspend":
+				g.paused = true
+				g.cpu = 0
+			case "resume":
+				g.paused = false
+			}
+			return "OK"
+		})
+		writeData(w, t.upid)
+	}
+}
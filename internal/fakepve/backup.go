@@ -0,0 +1,205 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var backupModes = map[string]bool{"snapshot": true, "suspend": true, "stop": true}
+
+type backup struct {
+	storage string
+	node    string
+	vmid    int
+	kind    string
+	ctime   time.Time
+	size    int64
+}
+
+func (b *backup) volid(st *storage) string {
+	if st.kind == "pbs" {
+		subtype := "vm"
+		if b.kind == "lxc" {
+			subtype = "ct"
+		}
+		return fmt.Sprintf("%s:backup/%s/%d/%s", st.id, subtype, b.vmid, b.ctime.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s:backup/vzdump-%s-%d-%s.%s", st.id, b.kind, b.vmid, b.ctime.Format("2006_01_02-15_04_05"), b.format(st))
+}
+
+func (b *backup) format(st *storage) string {
+	switch {
+	case st.kind == "pbs" && b.kind == "lxc":
+		return "pbs-ct"
+	case st.kind == "pbs":
+		return "pbs-vm"
+	case b.kind == "lxc":
+		return "tar.zst"
+	default:
+		return "vma.zst"
+	}
+}
+
+func (s *Server) populateBackups() {
+	now := time.Now()
+	for _, g := range s.guests {
+		if g.vmid%9 == 4 {
+			continue
+		}
+		latest := now.Add(-time.Duration(2+g.vmid%20) * time.Hour)
+		if g.vmid%7 == 2 {
+			latest = now.Add(-10 * 24 * time.Hour)
+		}
+		for day := 2; day >= 0; day-- {
+			s.backups = append(s.backups, &backup{
+				storage: "pbs-backup",
+				vmid:    g.vmid,
+				kind:    g.kind,
+				ctime:   latest.Add(-time.Duration(day) * 24 * time.Hour),
+				size:    g.disk,
+			})
+		}
+		if g.vmid%5 == 0 {
+			s.backups = append(s.backups, &backup{
+				storage: "local",
+				node:    g.node,
+				vmid:    g.vmid,
+				kind:    g.kind,
+				ctime:   latest.Add(-30 * 24 * time.Hour),
+				size:    g.disk,
+			})
+		}
+	}
+}
+
+func (s *Server) findNodeStorage(n *node, id string) *storage {
+	for _, st := range s.nodeStorage(n) {
+		if st.id == id {
+			return st
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleStorageContent(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNode(params[0])
+	if n == nil {
+		writeError(w, http.StatusInternalServerError, "no such node")
+		return
+	}
+	st := s.findNodeStorage(n, params[1])
+	if st == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage '%s' does not exist", params[1]))
+		return
+	}
+	if !st.enabled || n.status != "online" {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("storage '%s' is not online", st.id))
+		return
+	}
+
+	list := []map[string]any{}
+	if content := r.URL.Query().Get("content"); content != "" && content != "backup" {
+		writeData(w, list)
+		return
+	}
+	for _, b := range s.backups {
+		if b.storage != st.id || (b.node != "" && b.node != n.name) {
+			continue
+		}
+		list = append(list, map[string]any{
+			"volid":   b.volid(st),
+			"content": "backup",
+			"ctime":   b.ctime.Unix(),
+			"size":    b.size,
+			"format":  b.format(st),
+			"vmid":    b.vmid,
+			"subtype": b.kind,
+		})
+	}
+	writeData(w, list)
+}
+
+func (s *Server) handleVzdump(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNode(params[0])
+	if n == nil {
+		writeError(w, http.StatusInternalServerError, "no such node")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	st := s.findNodeStorage(n, r.PostForm.Get("storage"))
+	mode := r.PostForm.Get("mode")
+	if mode == "" {
+		mode = "snapshot"
+	}
+	switch {
+	case st == nil:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("storage '%s' does not exist", r.PostForm.Get("storage")))
+		return
+	case !st.enabled || !strings.Contains(st.content, "backup"):
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("storage '%s' does not support backups", st.id))
+		return
+	case !backupModes[mode]:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("mode: value '%s' does not have a value in the enumeration 'snapshot, suspend, stop'", mode))
+		return
+	}
+
+	var guests []*guest
+	for _, id := range strings.Split(r.PostForm.Get("vmid"), ",") {
+		vmid, err := strconv.Atoi(strings.TrimSpace(id))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("vmid: invalid format - value '%s' does not look like a valid VM ID", id))
+			return
+		}
+		var found *guest
+		for _, g := range s.guests {
+			if g.vmid == vmid && g.node == n.name {
+				found = g
+			}
+		}
+		if found == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("guest %d is not on node '%s'", vmid, n.name))
+			return
+		}
+		guests = append(guests, found)
+	}
+
+	id := ""
+	if len(guests) == 1 {
+		id = strconv.Itoa(guests[0].vmid)
+	}
+	node := ""
+	if !st.shared() {
+		node = n.name
+	}
+	t := s.newTask(n.name, "vzdump", id, time.Duration(4*len(guests))*time.Second, func() string {
+		for _, g := range guests {
+			s.backups = append(s.backups, &backup{
+				storage: st.id,
+				node:    node,
+				vmid:    g.vmid,
+				kind:    g.kind,
+				ctime:   time.Now(),
+				size:    g.disk,
+			})
+		}
+		return "OK"
+	})
+	for _, g := range guests {
+		t.progress = append(t.progress, backupLog(g, 4)...)
+	}
+	t.progress = append(t.progress, "INFO: Backup job finished successfully")
+	writeData(w, t.upid)
+}
@@ -0,0 +1,276 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	cephOSDSize      = 3840 * gib
+	cephRecoveryRate = 0.0005
+)
+
+type osd struct {
+	id    int
+	node  string
+	class string
+	up    bool
+	in    bool
+	used  int64
+}
+
+type cephPool struct {
+	name    string
+	size    int
+	minSize int
+	pgs     int
+	used    int64
+}
+
+type cephState struct {
+	osds     []*osd
+	pools    []*cephPool
+	degraded float64
+	misplace float64
+}
+
+func (s *Server) populateCeph() {
+	id := 0
+	for i, n := range s.nodes {
+		for j := 0; j < 3; j++ {
+			o := &osd{id: id, node: n.name, class: "ssd", up: true, in: true}
+			if i%2 == 1 {
+				o.class = "nvme"
+			}
+			o.used = int64(float64(cephOSDSize) * (0.25 + s.rng.Float64()*0.2))
+			s.ceph.osds = append(s.ceph.osds, o)
+			id++
+		}
+	}
+	down := s.ceph.osds[len(s.ceph.osds)-1]
+	down.up, down.in = false, false
+
+	s.ceph.pools = []*cephPool{
+		{name: ".mgr", size: 3, minSize: 2, pgs: 1, used: 40 * 1024 * 1024},
+		{name: "ceph-vm", size: 3, minSize: 2, pgs: 128, used: 3100 * gib},
+		{name: "cephfs_data", size: 3, minSize: 2, pgs: 64, used: 420 * gib},
+		{name: "cephfs_metadata", size: 3, minSize: 2, pgs: 16, used: 2 * gib},
+	}
+	s.ceph.degraded = 0.12
+	s.ceph.misplace = 0.04
+}
+
+func (s *Server) advanceCeph(dt float64) {
+	s.ceph.degraded = clamp(s.ceph.degraded-cephRecoveryRate*dt, 0, 1)
+	s.ceph.misplace = clamp(s.ceph.misplace-cephRecoveryRate*dt/2, 0, 1)
+}
+
+func (s *Server) osdUp(o *osd) bool {
+	n := s.findNode(o.node)
+	return o.up && n != nil && n.status == "online"
+}
+
+func (s *Server) cephPGCount() int {
+	total := 0
+	for _, p := range s.ceph.pools {
+		total += p.pgs
+	}
+	return total
+}
+
+func (s *Server) cephCapacity() (used, total int64) {
+	for _, o := range s.ceph.osds {
+		if o.in {
+			used += o.used
+			total += cephOSDSize
+		}
+	}
+	return used, total
+}
+
+func (s *Server) cephStatusJSON() map[string]any {
+	var up, in int
+	var down []string
+	for _, o := range s.ceph.osds {
+		if s.osdUp(o) {
+			up++
+		} else {
+			down = append(down, fmt.Sprintf("osd.%d (root=default,host=%s) is down", o.id, o.node))
+		}
+		if o.in {
+			in++
+		}
+	}
+
+	health := "HEALTH_OK"
+	checks := map[string]any{}
+	addCheck := func(name, message string, details []string) {
+		var detail []map[string]string
+		for _, d := range details {
+			detail = append(detail, map[string]string{"message": d})
+		}
+		checks[name] = map[string]any{
+			"severity": "HEALTH_WARN",
+			"summary":  map[string]any{"message": message, "count": len(details)},
+			"detail":   detail,
+			"muted":    false,
+		}
+		health = "HEALTH_WARN"
+	}
+	if len(down) > 0 {
+		addCheck("OSD_DOWN", fmt.Sprintf("%d osds down", len(down)), down)
+	}
+
+	pgs := s.cephPGCount()
+	degradedPGs := int(float64(pgs) * s.ceph.degraded)
+	recovering := degradedPGs / 4
+	backfillWait := int(float64(pgs) * s.ceph.misplace)
+	backfilling := backfillWait / 3
+	states := []map[string]any{}
+	addState := func(name string, count int) {
+		if count > 0 {
+			states = append(states, map[string]any{"state_name": name, "count": count})
+		}
+	}
+	addState("active+clean", pgs-degradedPGs-backfillWait)
+	addState("active+undersized+degraded", degradedPGs-recovering)
+	addState("active+recovering+degraded", recovering)
+	addState("active+remapped+backfill_wait", backfillWait-backfilling)
+	addState("active+remapped+backfilling", backfilling)
+
+	objects := int64(120000)
+	pgmap := map[string]any{
+		"num_pgs":           pgs,
+		"pgs_by_state":      states,
+		"num_objects":       objects,
+		"read_bytes_sec":    int64(40*1024*1024 + s.rng.Float64()*20*1024*1024),
+		"write_bytes_sec":   int64(15*1024*1024 + s.rng.Float64()*10*1024*1024),
+		"read_op_per_sec":   900 + s.rng.Intn(400),
+		"write_op_per_sec":  300 + s.rng.Intn(200),
+		"degraded_ratio":    s.ceph.degraded / 3,
+		"degraded_objects":  int64(float64(objects*3) * s.ceph.degraded / 3),
+		"degraded_total":    objects * 3,
+		"misplaced_ratio":   s.ceph.misplace,
+		"misplaced_objects": int64(float64(objects*3) * s.ceph.misplace),
+		"misplaced_total":   objects * 3,
+	}
+	used, total := s.cephCapacity()
+	pgmap["bytes_used"] = used
+	pgmap["bytes_total"] = total
+	pgmap["bytes_avail"] = total - used
+	pgmap["data_bytes"] = used / 3
+
+	if degradedPGs > 0 || backfilling > 0 {
+		pgmap["recovering_bytes_per_sec"] = int64(180*1024*1024 + s.rng.Float64()*60*1024*1024)
+		pgmap["recovering_objects_per_sec"] = 40 + s.rng.Intn(20)
+		pgmap["num_objects_recovered"] = 12000
+	}
+	if degradedPGs > 0 {
+		addCheck("PG_DEGRADED", fmt.Sprintf("Degraded data redundancy: %d pgs degraded", degradedPGs), []string{
+			fmt.Sprintf("pg 2.%x is active+undersized+degraded, acting [0,3]", degradedPGs),
+		})
+	}
+
+	return map[string]any{
+		"fsid":   "2f0c8b3e-4a8e-4c3b-9d5e-1a2b3c4d5e6f",
+		"health": map[string]any{"status": health, "checks": checks},
+		"osdmap": map[string]any{
+			"epoch":       512,
+			"num_osds":    len(s.ceph.osds),
+			"num_up_osds": up,
+			"num_in_osds": in,
+		},
+		"pgmap": pgmap,
+	}
+}
+
+func (s *Server) handleCephStatus(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, s.cephStatusJSON())
+}
+
+func (s *Server) handleCephOSDs(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findNode(params[0]) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("hostname lookup '%s' failed", params[0]))
+		return
+	}
+
+	var hosts []map[string]any
+	for i, n := range s.nodes {
+		var children []map[string]any
+		for _, o := range s.ceph.osds {
+			if o.node != n.name {
+				continue
+			}
+			status := "down"
+			if s.osdUp(o) {
+				status = "up"
+			}
+			children = append(children, map[string]any{
+				"id":                o.id,
+				"name":              fmt.Sprintf("osd.%d", o.id),
+				"type":              "osd",
+				"host":              n.name,
+				"status":            status,
+				"in":                boolInt(o.in),
+				"device_class":      o.class,
+				"bytes_used":        o.used,
+				"total_space":       int64(cephOSDSize),
+				"percent_used":      float64(o.used) / cephOSDSize * 100,
+				"apply_latency_ms":  s.rng.Intn(4),
+				"commit_latency_ms": s.rng.Intn(4),
+			})
+		}
+		hosts = append(hosts, map[string]any{
+			"id":       -2 - i,
+			"name":     n.name,
+			"type":     "host",
+			"children": children,
+		})
+	}
+
+	writeData(w, map[string]any{
+		"flags": "sortbitwise,recovery_deletes,purged_snapdirs,pglog_hardlimit",
+		"root": map[string]any{
+			"id":       -1,
+			"name":     "default",
+			"type":     "root",
+			"children": hosts,
+		},
+	})
+}
+
+func (s *Server) handleCephPools(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findNode(params[0]) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("hostname lookup '%s' failed", params[0]))
+		return
+	}
+
+	used, total := s.cephCapacity()
+	maxAvail := (total - used) / 3
+	var pools []map[string]any
+	for i, p := range s.ceph.pools {
+		pools = append(pools, map[string]any{
+			"pool":              i + 1,
+			"pool_name":         p.name,
+			"size":              p.size,
+			"min_size":          p.minSize,
+			"pg_num":            p.pgs,
+			"pg_autoscale_mode": "on",
+			"crush_rule":        0,
+			"crush_rule_name":   "replicated_rule",
+			"bytes_used":        p.used,
+			"percent_used":      float64(p.used) / float64(p.used+maxAvail),
+			"type":              "replicated",
+		})
+	}
+	writeData(w, pools)
+}
@@ -0,0 +1,482 @@
+package fakepve
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	gib         = 1024 * 1024 * 1024
+	clusterName = "pvetop-demo"
+)
+
+var guestNames = []string{
+	"web", "db", "cache", "mail", "dns", "proxy", "build", "git",
+	"monitor", "backup", "vpn", "files", "ldap", "queue", "search", "media",
+}
+
+var guestPools = []string{"tenant-a", "tenant-b", "infra", "tenant-c", ""}
+
+type node struct {
+	name    string
+	status  string
+	maxCPU  int
+	maxMem  int64
+	baseMem int64
+	disk    int64
+	maxDisk int64
+	uptime  float64
+}
+
+type guest struct {
+	vmid      int
+	name      string
+	kind      string
+	node      string
+	status    string
+	cpu       float64
+	cpus      int
+	mem       int64
+	maxMem    int64
+	disk      int64
+	maxDisk   int64
+	diskRead  float64
+	diskWrite float64
+	netIn     float64
+	netOut    float64
+	ioRate    float64
+	netRate   float64
+	uptime    float64
+	pid       int
+	paused    bool
+	lock      string
+	snapshots []*snapshot
+	parent    string
+	tags      string
+	pool      string
+}
+
+func (s *Server) populate() {
+	vmid := 100
+	for i := 0; i < s.opts.Nodes; i++ {
+		n := &node{
+			name:    fmt.Sprintf("pve%d", i+1),
+			status:  "online",
+			maxCPU:  []int{16, 32, 48, 64}[s.rng.Intn(4)],
+			maxMem:  int64([]int{64, 128, 256}[s.rng.Intn(3)]) * gib,
+			baseMem: 4 * gib,
+			maxDisk: 100 * gib,
+			uptime:  float64(86400 * (1 + s.rng.Intn(90))),
+		}
+		n.disk = int64(float64(n.maxDisk) * (0.2 + s.rng.Float64()*0.5))
+		s.nodes = append(s.nodes, n)
+
+		for j := 0; j < s.opts.GuestsPerNode; j++ {
+			g := &guest{
+				vmid:    vmid,
+				name:    fmt.Sprintf("%s-%02d", guestNames[s.rng.Intn(len(guestNames))], vmid%100),
+				kind:    "qemu",
+				node:    n.name,
+				status:  "running",
+				cpus:    []int{1, 2, 4, 8}[s.rng.Intn(4)],
+				maxMem:  int64([]int{1, 2, 4, 8, 16}[s.rng.Intn(5)]) * gib,
+				maxDisk: int64([]int{8, 32, 64, 128}[s.rng.Intn(4)]) * gib,
+				cpu:     s.rng.Float64() * 0.3,
+				ioRate:  s.rng.Float64() * 4 * 1024 * 1024,
+				netRate: s.rng.Float64() * 2 * 1024 * 1024,
+				uptime:  float64(3600 * (1 + s.rng.Intn(2000))),
+				pid:     1000 + s.rng.Intn(60000),
+			}
+			if j%3 == 2 {
+				g.kind = "lxc"
+			}
+			g.tags = guestTags[vmid%len(guestTags)]
+			g.pool = guestPools[(vmid/2)%len(guestPools)]
+			if s.rng.Float64() < 0.2 {
+				g.status = "stopped"
+				g.cpu, g.uptime, g.pid = 0, 0, 0
+			}
+			g.mem = int64(float64(g.maxMem) * (0.2 + s.rng.Float64()*0.6))
+			g.disk = int64(float64(g.maxDisk) * s.rng.Float64() * 0.8)
+			s.guests = append(s.guests, g)
+			vmid++
+		}
+	}
+	s.populateStorage()
+	s.populateSnapshots()
+	s.populateBackups()
+	s.populateCeph()
+	s.populateHA()
+	s.populateReplication()
+	s.populateTasks()
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func (s *Server) advance() {
+	now := time.Now()
+	dt := now.Sub(s.last).Seconds()
+	if dt <= 0 {
+		return
+	}
+	s.last = now
+	s.advanceTasks(now)
+	s.advanceCeph(dt)
+	s.advanceHA(now, dt)
+	s.advanceReplication(now)
+
+	for _, n := range s.nodes {
+		if n.status == "online" {
+			n.uptime += dt
+		}
+	}
+
+	for _, g := range s.guests {
+		if g.status != "running" {
+			continue
+		}
+		g.uptime += dt
+		if g.paused {
+			continue
+		}
+		g.cpu = clamp(g.cpu+s.rng.NormFloat64()*0.05, 0.001, 1)
+		if s.rng.Float64() < 0.02 {
+			g.cpu = clamp(0.7+s.rng.Float64()*0.3, 0, 1)
+		}
+		g.mem = int64(clamp(float64(g.mem)+s.rng.NormFloat64()*float64(g.maxMem)*0.01, float64(g.maxMem)*0.05, float64(g.maxMem)))
+		g.ioRate = clamp(g.ioRate*(1+s.rng.NormFloat64()*0.2), 1024, 200*1024*1024)
+		g.netRate = clamp(g.netRate*(1+s.rng.NormFloat64()*0.2), 1024, 100*1024*1024)
+		g.diskRead += g.ioRate * 0.7 * dt
+		g.diskWrite += g.ioRate * 0.3 * dt
+		g.netIn += g.netRate * 0.6 * dt
+		g.netOut += g.netRate * 0.4 * dt
+	}
+}
+
+func (s *Server) findNode(name string) *node {
+	for _, n := range s.nodes {
+		if n.name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func (s *Server) findGuest(nodeName, kind string, vmid int) *guest {
+	for _, g := range s.guests {
+		if g.node == nodeName && g.kind == kind && g.vmid == vmid {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) nodeUsage(n *node) (cpu float64, mem int64) {
+	var cores float64
+	mem = n.baseMem
+	for _, g := range s.guests {
+		if g.node == n.name && g.status == "running" {
+			cores += g.cpu * float64(g.cpus)
+			mem += g.mem
+		}
+	}
+	return clamp(cores/float64(n.maxCPU)+0.01, 0, 1), mem
+}
+
+func (s *Server) nodeJSON(n *node) map[string]any {
+	cpu, mem := s.nodeUsage(n)
+	data := map[string]any{
+		"node":    n.name,
+		"status":  n.status,
+		"maxcpu":  n.maxCPU,
+		"maxmem":  n.maxMem,
+		"maxdisk": n.maxDisk,
+	}
+	if n.status == "online" {
+		data["cpu"] = cpu
+		data["mem"] = mem
+		data["disk"] = n.disk
+		data["uptime"] = int64(n.uptime)
+	}
+	return data
+}
+
+func (g *guest) json() map[string]any {
+	data := map[string]any{
+		"vmid":      g.vmid,
+		"name":      g.name,
+		"status":    g.status,
+		"cpu":       g.cpu,
+		"cpus":      g.cpus,
+		"maxcpu":    g.cpus,
+		"mem":       g.mem,
+		"maxmem":    g.maxMem,
+		"disk":      g.disk,
+		"maxdisk":   g.maxDisk,
+		"diskread":  int64(g.diskRead),
+		"diskwrite": int64(g.diskWrite),
+		"netin":     int64(g.netIn),
+		"netout":    int64(g.netOut),
+		"uptime":    int64(g.uptime),
+		"ha":        map[string]any{"managed": 0},
+	}
+	if g.lock != "" {
+		data["lock"] = g.lock
+	}
+	if g.tags != "" {
+		data["tags"] = g.tags
+	}
+	if g.kind == "lxc" {
+		data["type"] = "lxc"
+		data["swap"] = 0
+		data["maxswap"] = 512 * 1024 * 1024
+	} else {
+		data["running-machine"] = "pc-i440fx-8.1+pve0"
+		data["running-qemu"] = "8.1.5"
+		data["balloon"] = g.maxMem
+	}
+	if g.status == "running" {
+		data["pid"] = g.pid
+		data["qmpstatus"] = "running"
+		if g.paused {
+			data["qmpstatus"] = "paused"
+			data["cpu"] = 0
+		}
+		if g.kind == "qemu" {
+			data["freemem"] = g.maxMem - g.mem
+		}
+	} else {
+		data["mem"] = 0
+		data["cpu"] = 0
+	}
+	return data
+}
+
+func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var nodes []map[string]any
+	for _, n := range s.nodes {
+		nodes = append(nodes, s.nodeJSON(n))
+	}
+	writeData(w, nodes)
+}
+
+var cpuModels = map[int]struct {
+	model   string
+	mhz     string
+	sockets int
+}{
+	16: {"Intel(R) Xeon(R) E-2388G CPU @ 3.20GHz", "3200.000", 1},
+	32: {"AMD EPYC 7313P 16-Core Processor", "3000.000", 1},
+	48: {"Intel(R) Xeon(R) Silver 4310 CPU @ 2.10GHz", "2100.000", 2},
+	64: {"AMD EPYC 7543P 32-Core Processor", "2800.000", 1},
+}
+
+func (s *Server) nodeIOWait(n *node) float64 {
+	var io float64
+	for _, g := range s.guests {
+		if g.node == n.name && g.status == "running" && !g.paused {
+			io += g.ioRate
+		}
+	}
+	wait := io / (2048 * 1024 * 1024)
+	if len(s.nodes) > 1 && n == s.nodes[len(s.nodes)-1] {
+		wait = wait*12 + 0.08
+	}
+	return clamp(wait, 0.0005, 0.6)
+}
+
+func (s *Server) handleNodeStatus(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNode(params[0])
+	if n == nil {
+		writeError(w, http.StatusInternalServerError, "no such node")
+		return
+	}
+	if n.status != "online" {
+		writeError(w, 595, fmt.Sprintf("Connection refused (node '%s' is offline)", n.name))
+		return
+	}
+
+	cpu, mem := s.nodeUsage(n)
+	wait := s.nodeIOWait(n)
+	cpuModel := cpuModels[n.maxCPU]
+	load := float64(n.maxCPU)*cpu + wait*float64(n.maxCPU)*2
+	swapTotal := int64(8 * gib)
+	swapUsed := int64(0)
+	if float64(mem) > float64(n.maxMem)*0.7 {
+		swapUsed = int64(float64(swapTotal) * clamp(float64(mem)/float64(n.maxMem)-0.7, 0, 1))
+	}
+	writeData(w, map[string]any{
+		"cpu":     cpu,
+		"wait":    wait,
+		"idle":    0,
+		"uptime":  int64(n.uptime),
+		"loadavg": []string{fmt.Sprintf("%.2f", load), fmt.Sprintf("%.2f", load*0.9), fmt.Sprintf("%.2f", load*0.8)},
+		"cpuinfo": map[string]any{
+			"model":   cpuModel.model,
+			"mhz":     cpuModel.mhz,
+			"sockets": cpuModel.sockets,
+			"cores":   n.maxCPU / 2 / cpuModel.sockets,
+			"cpus":    n.maxCPU,
+			"hvm":     "1",
+			"user_hz": 100,
+		},
+		"kversion": "Linux 6.14.11-4-pve #1 SMP PREEMPT_DYNAMIC PMX 6.14.11-4 (2025-10-10T08:04Z)",
+		"current-kernel": map[string]any{
+			"sysname": "Linux",
+			"release": "6.14.11-4-pve",
+			"version": "#1 SMP PREEMPT_DYNAMIC PMX 6.14.11-4 (2025-10-10T08:04Z)",
+			"machine": "x86_64",
+		},
+		"boot-info":  map[string]any{"mode": "efi", "secureboot": 0},
+		"pveversion": "pve-manager/9.0.11/3bf5476b8a4699e2",
+		"memory":     map[string]any{"total": n.maxMem, "used": mem, "free": n.maxMem - mem},
+		"swap":       map[string]any{"total": swapTotal, "used": swapUsed, "free": swapTotal - swapUsed},
+		"rootfs":     map[string]any{"total": n.maxDisk, "used": n.disk, "free": n.maxDisk - n.disk, "avail": n.maxDisk - n.disk},
+		"ksm":        map[string]any{"shared": int64(float64(mem) * 0.04)},
+	})
+}
+
+func (s *Server) handleClusterStatus(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []map[string]any
+	if len(s.nodes) > 1 {
+		entries = append(entries, map[string]any{
+			"id":      "cluster",
+			"type":    "cluster",
+			"name":    clusterName,
+			"nodes":   len(s.nodes),
+			"quorate": boolInt(s.quorate()),
+			"version": len(s.nodes) + 2,
+		})
+	}
+	for i, n := range s.nodes {
+		entries = append(entries, map[string]any{
+			"id":     "node/" + n.name,
+			"type":   "node",
+			"name":   n.name,
+			"nodeid": i + 1,
+			"ip":     fmt.Sprintf("10.10.10.%d", i+11),
+			"online": boolInt(n.status == "online"),
+			"local":  boolInt(i == 0),
+			"level":  "",
+		})
+	}
+	writeData(w, entries)
+}
+
+func (s *Server) handleClusterConfigNodes(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.nodes) < 2 {
+		writeError(w, http.StatusInternalServerError, "no such file '/etc/pve/corosync.conf'")
+		return
+	}
+	var nodes []map[string]any
+	for i, n := range s.nodes {
+		nodes = append(nodes, map[string]any{
+			"node":         n.name,
+			"name":         n.name,
+			"nodeid":       strconv.Itoa(i + 1),
+			"quorum_votes": "1",
+			"ring0_addr":   fmt.Sprintf("10.10.10.%d", i+11),
+		})
+	}
+	writeData(w, nodes)
+}
+
+func (s *Server) handleGuests(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.findNode(params[0]) == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("hostname lookup '%s' failed", params[0]))
+			return
+		}
+
+		guests := []map[string]any{}
+		for _, g := range s.guests {
+			if g.node == params[0] && g.kind == kind {
+				guests = append(guests, g.json())
+			}
+		}
+		writeData(w, guests)
+	}
+}
+
+func (s *Server) handleGuestStatus(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+			return
+		}
+		data := g.json()
+		data["ha"] = s.haGuestJSON(g)
+		writeData(w, data)
+	}
+}
+
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources := []map[string]any{}
+	for _, n := range s.nodes {
+		data := s.nodeJSON(n)
+		data["id"] = "node/" + n.name
+		data["type"] = "node"
+		resources = append(resources, data)
+
+		for _, st := range s.nodeStorage(n) {
+			if st.enabled {
+				resources = append(resources, s.storageResource(n, st))
+			}
+		}
+	}
+
+	for _, g := range s.guests {
+		data := g.json()
+		data["id"] = fmt.Sprintf("%s/%d", g.kind, g.vmid)
+		data["type"] = g.kind
+		data["node"] = g.node
+		if g.pool != "" {
+			data["pool"] = g.pool
+		}
+		if res := s.haResource(g); res != nil {
+			data["hastate"] = res.crmState()
+		}
+		resources = append(resources, data)
+	}
+
+	if filter := r.URL.Query().Get("type"); filter != "" {
+		var filtered []map[string]any
+		for _, res := range resources {
+			kind := res["type"].(string)
+			if kind == filter || (filter == "vm" && (kind == "qemu" || kind == "lxc")) {
+				filtered = append(filtered, res)
+			}
+		}
+		resources = filtered
+	}
+
+	writeData(w, resources)
+}
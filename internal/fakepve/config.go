@@ -0,0 +1,137 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var guestTags = []string{"", "prod", "prod;web", "dev", "db;prod", "test", ""}
+
+func (g *guest) mac() string {
+	return fmt.Sprintf("BC:24:11:%02X:%02X:%02X", g.vmid>>16&0xff, g.vmid>>8&0xff, g.vmid&0xff)
+}
+
+func (g *guest) config() map[string]any {
+	memMiB := g.maxMem / (1024 * 1024)
+	diskGiB := g.maxDisk / gib
+	tags := g.tags
+
+	if g.kind == "lxc" {
+		data := map[string]any{
+			"hostname": g.name,
+			"arch":     "amd64",
+			"ostype":   "debian",
+			"cores":    g.cpus,
+			"memory":   memMiB,
+			"swap":     512,
+			"rootfs":   fmt.Sprintf("%s:vm-%d-disk-0,size=%dG", g.diskStorage(), g.vmid, diskGiB),
+			"net0":     fmt.Sprintf("name=eth0,bridge=vmbr0,hwaddr=%s,ip=dhcp,type=veth", g.mac()),
+			"onboot":   g.vmid % 2,
+			"digest":   configDigest(g.vmid),
+		}
+		if tags != "" {
+			data["tags"] = tags
+		}
+		if g.parent != "" {
+			data["parent"] = g.parent
+		}
+		return data
+	}
+
+	data := map[string]any{
+		"name":    g.name,
+		"ostype":  "l26",
+		"cpu":     "x86-64-v2-AES",
+		"sockets": 1,
+		"cores":   g.cpus,
+		"memory":  strconv.FormatInt(memMiB, 10),
+		"boot":    "order=scsi0;ide2;net0",
+		"scsihw":  "virtio-scsi-single",
+		"scsi0":   fmt.Sprintf("%s:vm-%d-disk-0,iothread=1,size=%dG", g.diskStorage(), g.vmid, diskGiB),
+		"ide2":    "none,media=cdrom",
+		"net0":    fmt.Sprintf("virtio=%s,bridge=vmbr0,firewall=1,tag=%d", g.mac(), 10*(1+g.vmid%3)),
+		"agent":   "1",
+		"onboot":  g.vmid % 2,
+		"digest":  configDigest(g.vmid),
+		"smbios1": fmt.Sprintf("uuid=00000000-0000-4000-8000-%012d", g.vmid),
+	}
+	if g.vmid%4 == 0 {
+		data["balloon"] = memMiB / 2
+	}
+	for _, res := range g.localResources() {
+		data[res] = "0000:01:00.0,pcie=1"
+	}
+	if tags != "" {
+		data["tags"] = tags
+	}
+	if g.parent != "" {
+		data["parent"] = g.parent
+	}
+	if g.vmid%5 == 0 {
+		data["description"] = fmt.Sprintf("Managed by pvetop demo.\nOwner: team-%d", g.vmid%7)
+	}
+	return data
+}
+
+func configDigest(seed int) string {
+	return fmt.Sprintf("%040x", uint64(seed)*0x9e3779b97f4a7c15)
+}
+
+func (s *Server) handleGuestConfig(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+			return
+		}
+		writeData(w, g.config())
+	}
+}
+
+func (s *Server) handleUpdateGuestConfig(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if g.lock != "" {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM is locked (%s)", g.lock))
+			return
+		}
+
+		for key := range r.PostForm {
+			if key != "tags" && key != "delete" && key != "digest" {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("property '%s' is not supported by the demo server", key))
+				return
+			}
+		}
+		if digest := r.PostForm.Get("digest"); digest != "" && digest != configDigest(g.vmid) {
+			writeError(w, http.StatusInternalServerError, "detected modified configuration - file changed by other user? Try again.")
+			return
+		}
+		for _, key := range strings.Split(r.PostForm.Get("delete"), ",") {
+			if key == "tags" {
+				g.tags = ""
+			}
+		}
+		if r.PostForm.Has("tags") {
+			g.tags = r.PostForm.Get("tags")
+		}
+		writeData(w, nil)
+	}
+}
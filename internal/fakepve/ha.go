@@ -0,0 +1,200 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const haRestartDelay = 8.0
+
+type haResource struct {
+	guest     *guest
+	state     string
+	group     string
+	failed    bool
+	restartIn float64
+}
+
+type haState struct {
+	resources []*haResource
+	lrmSeen   map[string]time.Time
+}
+
+func (s *Server) populateHA() {
+	s.ha.lrmSeen = make(map[string]time.Time)
+	for _, n := range s.nodes {
+		s.ha.lrmSeen[n.name] = s.last
+	}
+	failed := false
+	for _, g := range s.guests {
+		if g.vmid%5 != 0 {
+			continue
+		}
+		r := &haResource{guest: g, state: "started", group: "prefer-" + g.node}
+		if g.status != "running" {
+			if failed {
+				r.state = "stopped"
+			} else {
+				r.failed, failed = true, true
+			}
+		}
+		s.ha.resources = append(s.ha.resources, r)
+	}
+}
+
+func (s *Server) advanceHA(now time.Time, dt float64) {
+	for _, n := range s.nodes {
+		if n.status == "online" {
+			s.ha.lrmSeen[n.name] = now
+		}
+	}
+
+	for _, r := range s.ha.resources {
+		g := r.guest
+		if r.state != "started" || r.failed || g.status == "running" || g.lock != "" {
+			r.restartIn = 0
+			continue
+		}
+		if r.restartIn == 0 {
+			r.restartIn = haRestartDelay
+		}
+		r.restartIn -= dt
+		if r.restartIn > 0 {
+			continue
+		}
+		r.restartIn = 0
+		g.status = "running"
+		g.uptime = 0
+		g.cpu = 0.05
+		g.pid = 1000 + s.rng.Intn(60000)
+	}
+}
+
+func (s *Server) haResource(g *guest) *haResource {
+	for _, r := range s.ha.resources {
+		if r.guest == g {
+			return r
+		}
+	}
+	return nil
+}
+
+func (r *haResource) sid() string {
+	if r.guest.kind == "lxc" {
+		return fmt.Sprintf("ct:%d", r.guest.vmid)
+	}
+	return fmt.Sprintf("vm:%d", r.guest.vmid)
+}
+
+func (r *haResource) crmState() string {
+	switch {
+	case r.failed:
+		return "error"
+	case r.state == "started" && r.guest.status != "running":
+		return "recovery"
+	}
+	return r.state
+}
+
+func (s *Server) haGuestJSON(g *guest) map[string]any {
+	r := s.haResource(g)
+	if r == nil {
+		return map[string]any{"managed": 0}
+	}
+	return map[string]any{"managed": 1, "state": r.state, "group": r.group}
+}
+
+func (s *Server) quorate() bool {
+	online := 0
+	for _, n := range s.nodes {
+		if n.status == "online" {
+			online++
+		}
+	}
+	return online*2 > len(s.nodes)
+}
+
+func haStamp(name, state string, t time.Time) string {
+	return fmt.Sprintf("%s (%s, %s)", name, state, t.Format("Mon Jan _2 15:04:05 2006"))
+}
+
+func (s *Server) handleHAStatus(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	local := s.nodes[0].name
+	quorum := "OK"
+	if !s.quorate() {
+		quorum = "No quorum on node '" + local + "'!"
+	}
+	entries := []map[string]any{
+		{"id": "quorum", "type": "quorum", "node": local, "quorate": boolInt(s.quorate()), "status": quorum},
+	}
+
+	for _, n := range s.nodes {
+		if n.status == "online" {
+			seen := s.ha.lrmSeen[n.name]
+			entries = append(entries, map[string]any{
+				"id": "master", "type": "master", "node": n.name,
+				"status": haStamp(n.name, "active", seen), "timestamp": seen.Unix(),
+			})
+			break
+		}
+	}
+
+	for _, n := range s.nodes {
+		state := "idle"
+		for _, res := range s.ha.resources {
+			if res.guest.node == n.name && res.state != "stopped" {
+				state = "active"
+				break
+			}
+		}
+		seen := s.ha.lrmSeen[n.name]
+		if n.status != "online" {
+			state = "old timestamp - dead?"
+		}
+		entries = append(entries, map[string]any{
+			"id": "lrm:" + n.name, "type": "lrm", "node": n.name,
+			"status": haStamp(n.name, state, seen), "timestamp": seen.Unix(),
+		})
+	}
+
+	for _, res := range s.ha.resources {
+		entries = append(entries, map[string]any{
+			"id":            "service:" + res.sid(),
+			"type":          "service",
+			"sid":           res.sid(),
+			"node":          res.guest.node,
+			"state":         res.crmState(),
+			"crm_state":     res.crmState(),
+			"request_state": res.state,
+			"max_restart":   1,
+			"max_relocate":  1,
+			"group":         res.group,
+			"status":        fmt.Sprintf("%s (%s, %s)", res.sid(), res.guest.node, res.crmState()),
+		})
+	}
+
+	writeData(w, entries)
+}
+
+func (s *Server) handleHAResources(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources := []map[string]any{}
+	for _, res := range s.ha.resources {
+		resources = append(resources, map[string]any{
+			"sid":          res.sid(),
+			"type":         map[string]string{"qemu": "vm", "lxc": "ct"}[res.guest.kind],
+			"state":        res.state,
+			"group":        res.group,
+			"max_restart":  1,
+			"max_relocate": 1,
+			"digest":       "5f3c2a1b9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b",
+		})
+	}
+	writeData(w, resources)
+}
@@ -0,0 +1,185 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (g *guest) diskStorage() string {
+	if g.vmid%3 == 0 {
+		return "ceph-vm"
+	}
+	return "local-lvm"
+}
+
+func (g *guest) localResources() []string {
+	if g.kind == "qemu" && g.vmid%11 == 7 {
+		return []string{"hostpci0"}
+	}
+	return []string{}
+}
+
+func (g *guest) localDisks() []map[string]any {
+	disks := []map[string]any{}
+	if g.diskStorage() != "local-lvm" {
+		return disks
+	}
+	drive := "scsi0"
+	if g.kind == "lxc" {
+		drive = "rootfs"
+	}
+	disks = append(disks, map[string]any{
+		"volid":     fmt.Sprintf("local-lvm:vm-%d-disk-0", g.vmid),
+		"size":      g.maxDisk,
+		"drivename": drive,
+		"is_unused": 0,
+	})
+	return disks
+}
+
+func (s *Server) migrationTargets(g *guest) ([]string, map[string]any) {
+	allowed := []string{}
+	notAllowed := map[string]any{}
+	for _, n := range s.nodes {
+		if n.name == g.node {
+			continue
+		}
+		if n.status != "online" {
+			notAllowed[n.name] = map[string]any{"unavailable_storages": []string{g.diskStorage()}}
+			continue
+		}
+		allowed = append(allowed, n.name)
+	}
+	return allowed, notAllowed
+}
+
+func (s *Server) handleMigratePrecondition(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vmid, _ := strconv.Atoi(params[1])
+	g := s.findGuest(params[0], "qemu", vmid)
+	if g == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/qemu/%d.conf' does not exist", params[0], vmid))
+		return
+	}
+
+	allowed, notAllowed := s.migrationTargets(g)
+	writeData(w, map[string]any{
+		"running":           boolInt(g.status == "running"),
+		"allowed_nodes":     allowed,
+		"not_allowed_nodes": notAllowed,
+		"local_disks":       g.localDisks(),
+		"local_resources":   g.localResources(),
+	})
+}
+
+func (s *Server) handleMigrate(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		target := r.PostForm.Get("target")
+		n := s.findNode(target)
+		running := g.status == "running"
+		switch {
+		case g.lock != "":
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM is locked (%s)", g.lock))
+			return
+		case target == g.node:
+			writeError(w, http.StatusBadRequest, "target is local node.")
+			return
+		case n == nil:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("no such cluster node '%s'", target))
+			return
+		case n.status != "online":
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("target node '%s' is not online", target))
+			return
+		case len(g.localResources()) > 0:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("can't migrate VM which uses local devices: %s", g.localResources()[0]))
+			return
+		case kind == "qemu" && running && r.PostForm.Get("online") != "1":
+			writeError(w, http.StatusInternalServerError, "can't migrate running VM without --online")
+			return
+		case kind == "qemu" && running && len(g.localDisks()) > 0 && r.PostForm.Get("with-local-disks") != "1":
+			writeError(w, http.StatusInternalServerError, "can't live migrate attached local disks without with-local-disks option")
+			return
+		case kind == "lxc" && running && r.PostForm.Get("restart") != "1":
+			writeError(w, http.StatusInternalServerError, "lxc live migration is currently not implemented - use 'restart' mode")
+			return
+		}
+
+		prefix := "qm"
+		if kind == "lxc" {
+			prefix = "vz"
+		}
+		source := g.node
+		g.lock = "migrate"
+		duration := time.Duration(6+g.maxMem/gib) * time.Second
+		t := s.newTask(source, prefix+"migrate", strconv.Itoa(vmid), duration, func() string {
+			g.node = target
+			g.lock = ""
+			if kind == "lxc" && running {
+				g.uptime = 0
+			}
+			return "OK"
+		})
+		t.progress = migrationLog(g, kind, target, running, duration)
+		writeData(w, t.upid)
+	}
+}
+
+func migrationLog(g *guest, kind, target string, running bool, duration time.Duration) []string {
+	var lines []string
+	if kind == "lxc" {
+		if running {
+			lines = append(lines, fmt.Sprintf("shutdown CT %d", g.vmid))
+		}
+		lines = append(lines, fmt.Sprintf("starting migration of CT %d to node '%s'", g.vmid, target))
+	} else {
+		lines = append(lines, fmt.Sprintf("starting migration of VM %d to node '%s'", g.vmid, target))
+	}
+
+	for _, disk := range g.localDisks() {
+		lines = append(lines,
+			fmt.Sprintf("found local disk '%s' (attached)", disk["volid"]),
+			fmt.Sprintf("copying local disk images of VM %d", g.vmid),
+		)
+	}
+
+	if kind == "qemu" && running {
+		lines = append(lines,
+			fmt.Sprintf("starting VM %d on remote node '%s'", g.vmid, target),
+			"start remote tunnel",
+			"starting online/live migration",
+		)
+		steps := 5
+		for i := 1; i <= steps; i++ {
+			done := g.mem / int64(steps) * int64(i)
+			lines = append(lines, fmt.Sprintf("migration active, transferred %.1f GiB of %.1f GiB VM-state", float64(done)/float64(gib), float64(g.mem)/float64(gib)))
+		}
+		lines = append(lines, "migration status: completed")
+	}
+	if kind == "lxc" && running {
+		lines = append(lines, "start container on target node")
+	}
+	return append(lines, fmt.Sprintf("migration finished successfully (duration %s)", formatClock(duration)))
+}
+
+func formatClock(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
@@ -0,0 +1,250 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const replicationRetryLimit = 30 * time.Minute
+
+type replJob struct {
+	guest     *guest
+	jobnum    int
+	source    string
+	target    string
+	interval  time.Duration
+	lastSync  time.Time
+	lastTry   time.Time
+	nextSync  time.Time
+	started   time.Time
+	ends      time.Time
+	duration  float64
+	failCount int
+	err       string
+	broken    bool
+	running   bool
+	log       []string
+}
+
+func (s *Server) populateReplication() {
+	if len(s.nodes) < 2 {
+		return
+	}
+	for _, g := range s.guests {
+		if g.vmid%4 != 3 {
+			continue
+		}
+		j := &replJob{guest: g, source: g.node, interval: 15 * time.Minute}
+		for i, n := range s.nodes {
+			if n.name == g.node {
+				j.target = s.nodes[(i+1)%len(s.nodes)].name
+			}
+		}
+		if len(s.repl)%2 == 1 {
+			j.interval = 5 * time.Minute
+		}
+		j.lastSync = s.last.Add(-time.Duration(s.rng.Float64() * float64(j.interval)))
+		j.lastTry = j.lastSync
+		j.nextSync = j.lastSync.Add(j.interval)
+		j.duration = 2 + s.rng.Float64()*18
+		j.log = j.runLog(j.lastSync, s.rng.Float64()*40)
+		s.repl = append(s.repl, j)
+	}
+
+	if len(s.repl) > 0 {
+		j := s.repl[0]
+		j.broken = true
+		j.failCount = 3
+		j.lastSync = s.last.Add(-6 * time.Hour)
+		j.lastTry = s.last.Add(-4 * time.Minute)
+		j.nextSync = j.lastTry.Add(15 * time.Minute)
+		j.err = j.sshError()
+		j.log = j.runLog(j.lastTry, 0)
+	}
+}
+
+func (j *replJob) id() string {
+	return fmt.Sprintf("%d-%d", j.guest.vmid, j.jobnum)
+}
+
+func (j *replJob) schedule() string {
+	return fmt.Sprintf("*/%d", int(j.interval.Minutes()))
+}
+
+func (j *replJob) volume() string {
+	if j.guest.kind == "lxc" {
+		return fmt.Sprintf("local-zfs:subvol-%d-disk-0", j.guest.vmid)
+	}
+	return fmt.Sprintf("local-zfs:vm-%d-disk-0", j.guest.vmid)
+}
+
+func (j *replJob) sshError() string {
+	return fmt.Sprintf("command '/usr/bin/ssh -e none -o 'BatchMode=yes' -o 'HostKeyAlias=%s' root@%s -- pvesr prepare-local-job %s --scan local-zfs %s --last_sync %d' failed: exit code 255",
+		j.target, j.target, j.id(), j.volume(), j.lastSync.Unix())
+}
+
+func (j *replJob) runLog(start time.Time, sizeMiB float64) []string {
+	stamp := func(line string) string {
+		return fmt.Sprintf("%s %s: %s", start.Format("2006-01-02 15:04:05"), j.id(), line)
+	}
+	running := 0
+	if j.guest.status == "running" {
+		running = j.guest.pid
+	}
+	kind := "VM"
+	if j.guest.kind == "lxc" {
+		kind = "CT"
+	}
+	lines := []string{
+		stamp("start replication job"),
+		stamp(fmt.Sprintf("guest => %s %d, running => %d", kind, j.guest.vmid, running)),
+		stamp("volumes => " + j.volume()),
+	}
+	if j.broken {
+		return append(lines, stamp("end replication job with error: "+j.sshError()))
+	}
+
+	prev := fmt.Sprintf("__replicate_%s_%d__", j.id(), j.lastSync.Unix())
+	next := fmt.Sprintf("__replicate_%s_%d__", j.id(), start.Unix())
+	return append(lines,
+		stamp(fmt.Sprintf("create snapshot '%s' on %s", next, j.volume())),
+		stamp("using secure transmission, rate limit: none"),
+		stamp(fmt.Sprintf("incremental sync '%s' (%s => %s)", j.volume(), prev, next)),
+		stamp(fmt.Sprintf("send from @%s to %s@%s estimated size is %.1fM", prev, j.volume(), next, sizeMiB)),
+		stamp(fmt.Sprintf("total estimated size is %.1fM", sizeMiB)),
+		stamp(fmt.Sprintf("successfully imported '%s'", j.volume())),
+		stamp(fmt.Sprintf("delete previous replication snapshot '%s' on %s", prev, j.volume())),
+		stamp("end replication job"),
+	)
+}
+
+func (s *Server) advanceReplication(now time.Time) {
+	for _, j := range s.repl {
+		if j.guest.node != j.source {
+			if j.guest.node == j.target {
+				j.target = j.source
+			}
+			j.source = j.guest.node
+		}
+
+		if j.running {
+			if now.Before(j.ends) {
+				continue
+			}
+			j.running = false
+			j.lastTry = j.started
+			if j.broken {
+				j.failCount++
+				j.err = j.sshError()
+				retry := time.Duration(j.failCount) * 5 * time.Minute
+				if retry > replicationRetryLimit {
+					retry = replicationRetryLimit
+				}
+				j.nextSync = now.Add(retry)
+				continue
+			}
+			j.lastSync = j.started
+			j.failCount = 0
+			j.err = ""
+			j.nextSync = j.started.Truncate(j.interval).Add(j.interval)
+			continue
+		}
+
+		if now.Before(j.nextSync) {
+			continue
+		}
+		j.running = true
+		j.started = now
+		j.duration = 2 + s.rng.Float64()*6
+		j.ends = now.Add(time.Duration(j.duration * float64(time.Second)))
+		j.log = j.runLog(now, s.rng.Float64()*40)
+	}
+}
+
+func (s *Server) findReplJob(node, id string) *replJob {
+	for _, j := range s.repl {
+		if j.source == node && j.id() == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func (s *Server) handleReplication(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findNode(params[0]) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("hostname lookup '%s' failed", params[0]))
+		return
+	}
+
+	jobs := []map[string]any{}
+	for _, j := range s.repl {
+		if j.source != params[0] {
+			continue
+		}
+		data := map[string]any{
+			"id":         j.id(),
+			"guest":      j.guest.vmid,
+			"jobnum":     j.jobnum,
+			"type":       "local",
+			"vmtype":     j.guest.kind,
+			"source":     j.source,
+			"target":     j.target,
+			"schedule":   j.schedule(),
+			"last_sync":  unixOrZero(j.lastSync),
+			"last_try":   unixOrZero(j.lastTry),
+			"next_sync":  unixOrZero(j.nextSync),
+			"duration":   j.duration,
+			"fail_count": j.failCount,
+		}
+		if j.err != "" {
+			data["error"] = j.err
+		}
+		if j.running {
+			data["pid"] = 2000 + j.guest.vmid
+		}
+		jobs = append(jobs, data)
+	}
+	writeData(w, jobs)
+}
+
+func (s *Server) handleReplicationScheduleNow(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.findReplJob(params[0], params[1])
+	if j == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("no such replication job '%s'", params[1]))
+		return
+	}
+	if !j.running {
+		j.nextSync = time.Now()
+	}
+	writeData(w, j.id())
+}
+
+func (s *Server) handleReplicationLog(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.findReplJob(params[0], params[1])
+	if j == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("no such replication job '%s'", params[1]))
+		return
+	}
+	lines := []map[string]any{}
+	for i, line := range j.log {
+		lines = append(lines, map[string]any{"n": i + 1, "t": line})
+	}
+	writeData(w, lines)
+}
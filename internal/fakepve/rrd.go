@@ -0,0 +1,152 @@
+package fakepve
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const rrdPoints = 70
+
+var rrdSteps = map[string]int64{
+	"hour":  60,
+	"day":   1800,
+	"week":  10800,
+	"month": 43200,
+	"year":  604800,
+}
+
+type rrdSample struct {
+	cpu, maxCPU         float64
+	mem, maxMem         float64
+	diskRead, diskWrite float64
+	netIn, netOut       float64
+}
+
+func noise(seed, t int64) float64 {
+	x := uint64(seed)*0x9e3779b97f4a7c15 ^ uint64(t)
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x%10000) / 10000
+}
+
+func nameSeed(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+func rrdSeries(seed int64, timeframe string, now time.Time, current rrdSample, memKeys [2]string) ([]map[string]any, bool) {
+	step, ok := rrdSteps[timeframe]
+	if !ok {
+		return nil, false
+	}
+
+	end := now.Unix() / step * step
+	phase := noise(seed, 0) * 2 * math.Pi
+	var points []map[string]any
+	for i := rrdPoints - 1; i >= 0; i-- {
+		t := end - int64(i)*step
+		wave := 1 + 0.4*math.Sin(2*math.Pi*float64(t)/86400+phase) + (noise(seed, t)-0.5)*0.5
+		if i == 0 {
+			wave = 1
+		}
+		points = append(points, map[string]any{
+			"time":      t,
+			"cpu":       clamp(current.cpu*wave, 0, 1),
+			"maxcpu":    current.maxCPU,
+			memKeys[0]:  clamp(current.mem*wave, 0, current.maxMem),
+			memKeys[1]:  current.maxMem,
+			"diskread":  current.diskRead * wave,
+			"diskwrite": current.diskWrite * wave,
+			"netin":     current.netIn * wave,
+			"netout":    current.netOut * wave,
+		})
+	}
+	return points, true
+}
+
+func (g *guest) rrdSample() rrdSample {
+	sample := rrdSample{maxCPU: float64(g.cpus), maxMem: float64(g.maxMem)}
+	if g.status == "running" && !g.paused {
+		sample.cpu = g.cpu
+		sample.mem = float64(g.mem)
+		sample.diskRead = g.ioRate * 0.7
+		sample.diskWrite = g.ioRate * 0.3
+		sample.netIn = g.netRate * 0.6
+		sample.netOut = g.netRate * 0.4
+	}
+	return sample
+}
+
+func (s *Server) nodeRRDSample(n *node) rrdSample {
+	cpu, mem := s.nodeUsage(n)
+	sample := rrdSample{cpu: cpu, maxCPU: float64(n.maxCPU), mem: float64(mem), maxMem: float64(n.maxMem)}
+	for _, g := range s.guests {
+		if g.node == n.name {
+			gs := g.rrdSample()
+			sample.netIn += gs.netIn
+			sample.netOut += gs.netOut
+		}
+	}
+	return sample
+}
+
+func (s *Server) handleGuestRRD(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		vmid, _ := strconv.Atoi(params[1])
+		g := s.findGuest(params[0], kind, vmid)
+		if g == nil {
+			writeError(w, http.StatusInternalServerError, "no such VM")
+			return
+		}
+
+		points, ok := rrdSeries(int64(g.vmid), r.URL.Query().Get("timeframe"), time.Now(), g.rrdSample(), [2]string{"mem", "maxmem"})
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Parameter verification failed.")
+			return
+		}
+		writeData(w, points)
+	}
+}
+
+func (s *Server) handleNodeRRD(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNode(params[0])
+	if n == nil {
+		writeError(w, http.StatusInternalServerError, "no such node")
+		return
+	}
+
+	points, ok := rrdSeries(nameSeed(n.name), r.URL.Query().Get("timeframe"), time.Now(), s.nodeRRDSample(n), [2]string{"memused", "memtotal"})
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Parameter verification failed.")
+		return
+	}
+	seed := nameSeed(n.name)
+	wait := s.nodeIOWait(n)
+	for _, p := range points {
+		delete(p, "diskread")
+		delete(p, "diskwrite")
+		t := p["time"].(int64)
+		cpu := p["cpu"].(float64)
+		mem := p["memused"].(float64) / p["memtotal"].(float64)
+		p["iowait"] = wait * (0.6 + noise(seed, t)*0.8)
+		p["pressurecpusome"] = clamp(cpu*cpu*0.4, 0, 1)
+		p["pressureiosome"] = clamp(p["iowait"].(float64)*2.5, 0, 1)
+		p["pressureiofull"] = clamp(p["iowait"].(float64)*1.8, 0, 1)
+		p["pressurememorysome"] = clamp((mem-0.8)*0.5, 0, 1)
+		p["pressurememoryfull"] = clamp((mem-0.8)*0.3, 0, 1)
+	}
+	writeData(w, points)
+}
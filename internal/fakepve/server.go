@@ -0,0 +1,340 @@
+package fakepve
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+)
+
+const DemoToken = "root@pam!pvetop-demo=00000000-0000-0000-0000-000000000000"
+
+type Options struct {
+	Nodes         int
+	GuestsPerNode int
+	Seed          int64
+	Username      string
+	Password      string
+}
+
+type Server struct {
+	srv *httptest.Server
+
+	mu      sync.Mutex
+	rng     *mathrand.Rand
+	opts    Options
+	nodes   []*node
+	guests  []*guest
+	storage []*storage
+	tokens  map[string]bool
+	tickets map[string]bool
+	tasks   []*task
+	backups []*backup
+	ceph    cephState
+	ha      haState
+	repl    []*replJob
+	taskSeq int
+	last    time.Time
+	faults  []fault
+	hits    map[string]int
+}
+
+type fault struct {
+	method  string
+	pattern string
+	status  int
+	message string
+}
+
+func New(opts Options) *Server {
+	if opts.Nodes <= 0 {
+		opts.Nodes = 3
+	}
+	if opts.GuestsPerNode <= 0 {
+		opts.GuestsPerNode = 12
+	}
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.Username == "" {
+		opts.Username = "root@pam"
+	}
+	if opts.Password == "" {
+		opts.Password = "demo"
+	}
+
+	s := &Server{
+		rng:     mathrand.New(mathrand.NewSource(opts.Seed)),
+		opts:    opts,
+		tokens:  map[string]bool{DemoToken: true},
+		tickets: make(map[string]bool),
+		hits:    make(map[string]int),
+		last:    time.Now(),
+	}
+	s.populate()
+	s.srv = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	s.srv.StartTLS()
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+func (s *Server) HostPort() (string, string) {
+	u, _ := url.Parse(s.srv.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	return host, port
+}
+
+func (s *Server) Fingerprint() string {
+	return api.Fingerprint(s.srv.Certificate())
+}
+
+func (s *Server) Token() string {
+	return DemoToken
+}
+
+func (s *Server) Fail(method, pattern string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault{method: method, pattern: pattern, status: status, message: message})
+}
+
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[method+" "+path]
+}
+
+func (s *Server) fault(method, path string) (fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.faults {
+		if _, ok := matchRoute(f.pattern, path); ok && f.method == method {
+			return f, true
+		}
+	}
+	return fault{}, false
+}
+
+type route struct {
+	method  string
+	pattern string
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+func (s *Server) routes() []route {
+	return []route{
+		{"POST", "/access/ticket", s.handleTicket},
+		{"POST", "/access/users/*/token/*", s.handleCreateToken},
+		{"DELETE", "/access/users/*/token/*", s.handleDeleteToken},
+		{"GET", "/cluster/resources", s.handleResources},
+		{"GET", "/cluster/status", s.handleClusterStatus},
+		{"GET", "/cluster/config/nodes", s.handleClusterConfigNodes},
+		{"GET", "/nodes", s.handleNodes},
+		{"GET", "/nodes/*/qemu", s.handleGuests("qemu")},
+		{"GET", "/nodes/*/lxc", s.handleGuests("lxc")},
+		{"GET", "/nodes/*/qemu/*/status/current", s.handleGuestStatus("qemu")},
+		{"GET", "/nodes/*/lxc/*/status/current", s.handleGuestStatus("lxc")},
+		{"GET", "/nodes/*/qemu/*/config", s.handleGuestConfig("qemu")},
+		{"GET", "/nodes/*/lxc/*/config", s.handleGuestConfig("lxc")},
+		{"PUT", "/nodes/*/qemu/*/config", s.handleUpdateGuestConfig("qemu")},
+		{"PUT", "/nodes/*/lxc/*/config", s.handleUpdateGuestConfig("lxc")},
+		{"GET", "/nodes/*/qemu/*/rrddata", s.handleGuestRRD("qemu")},
+		{"GET", "/nodes/*/lxc/*/rrddata", s.handleGuestRRD("lxc")},
+		{"GET", "/nodes/*/status", s.handleNodeStatus},
+		{"GET", "/nodes/*/rrddata", s.handleNodeRRD},
+		{"GET", "/nodes/*/storage", s.handleNodeStorage},
+		{"GET", "/cluster/ceph/status", s.handleCephStatus},
+		{"GET", "/nodes/*/ceph/osd", s.handleCephOSDs},
+		{"GET", "/nodes/*/ceph/pool", s.handleCephPools},
+		{"GET", "/cluster/ha/status/current", s.handleHAStatus},
+		{"GET", "/cluster/ha/resources", s.handleHAResources},
+		{"GET", "/nodes/*/replication", s.handleReplication},
+		{"GET", "/nodes/*/replication/*/log", s.handleReplicationLog},
+		{"POST", "/nodes/*/replication/*/schedule_now", s.handleReplicationScheduleNow},
+		{"GET", "/nodes/*/storage/*/content", s.handleStorageContent},
+		{"POST", "/nodes/*/vzdump", s.handleVzdump},
+		{"POST", "/nodes/*/qemu/*/status/*", s.handleGuestAction("qemu")},
+		{"POST", "/nodes/*/lxc/*/status/*", s.handleGuestAction("lxc")},
+		{"GET", "/nodes/*/qemu/*/migrate", s.handleMigratePrecondition},
+		{"POST", "/nodes/*/qemu/*/migrate", s.handleMigrate("qemu")},
+		{"POST", "/nodes/*/lxc/*/migrate", s.handleMigrate("lxc")},
+		{"GET", "/nodes/*/qemu/*/snapshot", s.handleSnapshots("qemu")},
+		{"GET", "/nodes/*/lxc/*/snapshot", s.handleSnapshots("lxc")},
+		{"POST", "/nodes/*/qemu/*/snapshot", s.handleCreateSnapshot("qemu")},
+		{"POST", "/nodes/*/lxc/*/snapshot", s.handleCreateSnapshot("lxc")},
+		{"POST", "/nodes/*/qemu/*/snapshot/*/rollback", s.handleRollbackSnapshot("qemu")},
+		{"POST", "/nodes/*/lxc/*/snapshot/*/rollback", s.handleRollbackSnapshot("lxc")},
+		{"DELETE", "/nodes/*/qemu/*/snapshot/*", s.handleDeleteSnapshot("qemu")},
+		{"DELETE", "/nodes/*/lxc/*/snapshot/*", s.handleDeleteSnapshot("lxc")},
+		{"GET", "/cluster/tasks", s.handleClusterTasks},
+		{"GET", "/nodes/*/tasks", s.handleNodeTasks},
+		{"GET", "/nodes/*/tasks/*/status", s.handleTaskStatus},
+		{"GET", "/nodes/*/tasks/*/log", s.handleTaskLog},
+	}
+}
+
+func matchRoute(pattern, path string) ([]string, bool) {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+
+	var params []string
+	for i := range want {
+		if want[i] == "*" {
+			params = append(params, got[i])
+			continue
+		}
+		if want[i] != got[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/api2/json")
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown API path")
+		return
+	}
+
+	if path != "/access/ticket" && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "authentication failure")
+		return
+	}
+
+	s.mu.Lock()
+	s.hits[r.Method+" "+path]++
+	s.mu.Unlock()
+
+	if f, ok := s.fault(r.Method, path); ok {
+		writeError(w, f.status, f.message)
+		return
+	}
+
+	for _, rt := range s.routes() {
+		if rt.method != r.Method {
+			continue
+		}
+		if params, ok := matchRoute(rt.pattern, path); ok {
+			s.mu.Lock()
+			s.advance()
+			s.mu.Unlock()
+			rt.handler(w, r, params)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotImplemented, fmt.Sprintf("Method '%s %s' not implemented", r.Method, path))
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return s.tokens[strings.TrimPrefix(auth, "PVEAPIToken=")]
+	}
+	if cookie, err := r.Cookie("PVEAuthCookie"); err == nil {
+		return s.tickets[cookie.Value]
+	}
+	return false
+}
+
+func (s *Server) handleTicket(w http.ResponseWriter, r *http.Request, _ []string) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if r.PostForm.Get("username") != s.opts.Username || r.PostForm.Get("password") != s.opts.Password {
+		writeError(w, http.StatusUnauthorized, "authentication failure")
+		return
+	}
+
+	ticket := "PVE:" + s.opts.Username + ":" + randomHex(16)
+	s.mu.Lock()
+	s.tickets[ticket] = true
+	s.mu.Unlock()
+
+	writeData(w, map[string]string{
+		"ticket":              ticket,
+		"CSRFPreventionToken": randomHex(16),
+		"username":            s.opts.Username,
+	})
+}
+
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request, params []string) {
+	user, _ := url.PathUnescape(params[0])
+	tokenID, _ := url.PathUnescape(params[1])
+	secret := randomHex(16)
+
+	s.mu.Lock()
+	s.tokens[fmt.Sprintf("%s!%s=%s", user, tokenID, secret)] = true
+	s.mu.Unlock()
+
+	writeData(w, map[string]any{
+		"full-tokenid": user + "!" + tokenID,
+		"value":        secret,
+	})
+}
+
+func (s *Server) handleDeleteToken(w http.ResponseWriter, r *http.Request, params []string) {
+	user, _ := url.PathUnescape(params[0])
+	tokenID, _ := url.PathUnescape(params[1])
+	prefix := user + "!" + tokenID + "="
+
+	s.mu.Lock()
+	for token := range s.tokens {
+		if strings.HasPrefix(token, prefix) {
+			delete(s.tokens, token)
+		}
+	}
+	s.mu.Unlock()
+
+	writeData(w, nil)
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"data": nil, "message": message})
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
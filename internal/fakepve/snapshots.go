@@ -0,0 +1,251 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var snapshotName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_\-]+$`)
+
+type snapshot struct {
+	name        string
+	description string
+	parent      string
+	time        time.Time
+	vmstate     bool
+}
+
+func (s *Server) populateSnapshots() {
+	now := time.Now()
+	for _, g := range s.guests {
+		if g.vmid%4 != 1 {
+			continue
+		}
+		count := 1 + s.rng.Intn(3)
+		for i := 0; i < count; i++ {
+			snap := &snapshot{
+				name:        []string{"pre_upgrade", "before_kernel", "weekly", "clean_install"}[(g.vmid+i)%4] + strconv.Itoa(i+1),
+				description: []string{"", "before apt dist-upgrade", "automatic"}[i%3],
+				parent:      g.parent,
+				time:        now.Add(-time.Duration(count-i)*72*time.Hour - time.Duration(s.rng.Intn(12))*time.Hour),
+				vmstate:     g.kind == "qemu" && i%2 == 1,
+			}
+			g.snapshots = append(g.snapshots, snap)
+			g.parent = snap.name
+		}
+		if count > 1 {
+			branch := &snapshot{
+				name:        "experiment",
+				description: "branch off the first snapshot",
+				parent:      g.snapshots[0].name,
+				time:        g.snapshots[0].time.Add(time.Hour),
+			}
+			g.snapshots = append(g.snapshots, branch)
+		}
+	}
+}
+
+func (g *guest) findSnapshot(name string) *snapshot {
+	for _, snap := range g.snapshots {
+		if snap.name == name {
+			return snap
+		}
+	}
+	return nil
+}
+
+func (g *guest) snapshotsJSON() []map[string]any {
+	list := []map[string]any{}
+	for _, snap := range g.snapshots {
+		data := map[string]any{
+			"name":        snap.name,
+			"description": snap.description,
+			"snaptime":    snap.time.Unix(),
+		}
+		if g.kind == "qemu" {
+			data["vmstate"] = boolInt(snap.vmstate)
+		}
+		if snap.parent != "" {
+			data["parent"] = snap.parent
+		}
+		list = append(list, data)
+	}
+	current := map[string]any{
+		"name":        "current",
+		"description": "You are here!",
+	}
+	if g.kind == "qemu" {
+		current["running"] = boolInt(g.status == "running")
+	}
+	if g.parent != "" {
+		current["parent"] = g.parent
+	}
+	return append(list, current)
+}
+
+func (s *Server) snapshotGuest(w http.ResponseWriter, kind string, params []string) *guest {
+	vmid, _ := strconv.Atoi(params[1])
+	g := s.findGuest(params[0], kind, vmid)
+	if g == nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Configuration file 'nodes/%s/%s/%d.conf' does not exist", params[0], kind, vmid))
+		return nil
+	}
+	return g
+}
+
+func (s *Server) handleSnapshots(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if g := s.snapshotGuest(w, kind, params); g != nil {
+			writeData(w, g.snapshotsJSON())
+		}
+	}
+}
+
+func (s *Server) handleCreateSnapshot(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		g := s.snapshotGuest(w, kind, params)
+		if g == nil {
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		name := r.PostForm.Get("snapname")
+		vmstate := r.PostForm.Get("vmstate") == "1"
+		switch {
+		case !snapshotName.MatchString(name) || name == "current":
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid format - invalid configuration ID '%s'", name))
+			return
+		case g.findSnapshot(name) != nil:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("snapshot name '%s' already used", name))
+			return
+		case g.lock != "":
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM is locked (%s)", g.lock))
+			return
+		case vmstate && kind != "qemu":
+			writeError(w, http.StatusBadRequest, "property is not defined in schema and the schema does not allow additional properties")
+			return
+		}
+
+		snap := &snapshot{
+			name:        name,
+			description: r.PostForm.Get("description"),
+			vmstate:     vmstate && g.status == "running",
+		}
+		duration := 2 * time.Second
+		if snap.vmstate {
+			duration += time.Duration(g.mem/gib+1) * time.Second
+		}
+		g.lock = "snapshot"
+		t := s.newTask(g.node, snapshotTaskPrefix(kind)+"snapshot", strconv.Itoa(g.vmid), duration, func() string {
+			snap.parent = g.parent
+			snap.time = time.Now()
+			g.snapshots = append(g.snapshots, snap)
+			g.parent = snap.name
+			g.lock = ""
+			return "OK"
+		})
+		if snap.vmstate {
+			t.progress = []string{"saving VM state and RAM using storage 'local-lvm'", "completed saving the VM state"}
+		}
+		writeData(w, t.upid)
+	}
+}
+
+func (s *Server) handleRollbackSnapshot(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		g := s.snapshotGuest(w, kind, params)
+		if g == nil {
+			return
+		}
+		snap := g.findSnapshot(params[2])
+		switch {
+		case snap == nil:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("snapshot '%s' does not exist", params[2]))
+			return
+		case g.lock != "":
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM is locked (%s)", g.lock))
+			return
+		}
+
+		g.lock = "rollback"
+		t := s.newTask(g.node, snapshotTaskPrefix(kind)+"rollback", strconv.Itoa(g.vmid), 3*time.Second, func() string {
+			g.parent = snap.name
+			g.lock = ""
+			if snap.vmstate {
+				g.status = "running"
+				g.uptime = 0
+				g.cpu = 0.05
+				g.pid = 1000 + s.rng.Intn(60000)
+			} else if kind == "qemu" {
+				g.status = "stopped"
+				g.uptime, g.cpu, g.pid = 0, 0, 0
+			}
+			return "OK"
+		})
+		writeData(w, t.upid)
+	}
+}
+
+func (s *Server) handleDeleteSnapshot(kind string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		g := s.snapshotGuest(w, kind, params)
+		if g == nil {
+			return
+		}
+		snap := g.findSnapshot(params[2])
+		switch {
+		case snap == nil:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("snapshot '%s' does not exist", params[2]))
+			return
+		case g.lock != "":
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("VM is locked (%s)", g.lock))
+			return
+		}
+
+		g.lock = "snapshot-delete"
+		t := s.newTask(g.node, snapshotTaskPrefix(kind)+"delsnapshot", strconv.Itoa(g.vmid), 2*time.Second, func() string {
+			var kept []*snapshot
+			for _, other := range g.snapshots {
+				if other == snap {
+					continue
+				}
+				if other.parent == snap.name {
+					other.parent = snap.parent
+				}
+				kept = append(kept, other)
+			}
+			g.snapshots = kept
+			if g.parent == snap.name {
+				g.parent = snap.parent
+			}
+			g.lock = ""
+			return "OK"
+		})
+		writeData(w, t.upid)
+	}
+}
+
+func snapshotTaskPrefix(kind string) string {
+	if kind == "lxc" {
+		return "vz"
+	}
+	return "qm"
+}
@@ -0,0 +1,120 @@
+package fakepve
+
+import (
+	"net/http"
+)
+
+type storage struct {
+	id      string
+	node    string
+	kind    string
+	content string
+	enabled bool
+	used    int64
+	total   int64
+}
+
+func (st *storage) shared() bool {
+	return st.node == ""
+}
+
+func (s *Server) populateStorage() {
+	for i, n := range s.nodes {
+		thin := &storage{id: "local-lvm", node: n.name, kind: "lvmthin", content: "images,rootdir", enabled: true, total: 512 * gib}
+		thin.used = int64(float64(thin.total) * (0.3 + s.rng.Float64()*0.4))
+		if i == 0 {
+			thin.used = thin.total * 96 / 100
+		}
+		s.storage = append(s.storage,
+			&storage{id: "local", node: n.name, kind: "dir", content: "iso,vztmpl,backup", enabled: true},
+			thin,
+		)
+	}
+	s.storage = append(s.storage,
+		&storage{id: "ceph-vm", kind: "rbd", content: "images,rootdir", enabled: true, total: 8192 * gib, used: 3100 * gib},
+		&storage{id: "nfs-iso", kind: "nfs", content: "iso,vztmpl", enabled: true, total: 2048 * gib, used: 1650 * gib},
+		&storage{id: "pbs-backup", kind: "pbs", content: "backup", enabled: true, total: 16384 * gib, used: 9800 * gib},
+		&storage{id: "old-nfs", kind: "nfs", content: "backup", enabled: false},
+	)
+}
+
+func (s *Server) nodeStorage(n *node) []*storage {
+	var list []*storage
+	for _, st := range s.storage {
+		if st.node == "" || st.node == n.name {
+			list = append(list, st)
+		}
+	}
+	return list
+}
+
+func (s *Server) storageUsage(n *node, st *storage) (used, total int64) {
+	if st.kind == "dir" {
+		return n.disk, n.maxDisk
+	}
+	return st.used, st.total
+}
+
+func (s *Server) storageJSON(n *node, st *storage) map[string]any {
+	used, total := s.storageUsage(n, st)
+	active := st.enabled && n.status == "online"
+	data := map[string]any{
+		"storage": st.id,
+		"type":    st.kind,
+		"content": st.content,
+		"shared":  boolInt(st.shared()),
+		"enabled": boolInt(st.enabled),
+		"active":  boolInt(active),
+	}
+	if active {
+		data["used"] = used
+		data["total"] = total
+		data["avail"] = total - used
+		data["used_fraction"] = float64(used) / float64(total)
+	}
+	return data
+}
+
+func (s *Server) storageResource(n *node, st *storage) map[string]any {
+	used, total := s.storageUsage(n, st)
+	status := "available"
+	if n.status != "online" {
+		status = "unknown"
+	}
+	return map[string]any{
+		"id":         "storage/" + n.name + "/" + st.id,
+		"type":       "storage",
+		"storage":    st.id,
+		"node":       n.name,
+		"plugintype": st.kind,
+		"status":     status,
+		"shared":     boolInt(st.shared()),
+		"content":    st.content,
+		"disk":       used,
+		"maxdisk":    total,
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (s *Server) handleNodeStorage(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.findNode(params[0])
+	if n == nil {
+		writeError(w, http.StatusInternalServerError, "no such node")
+		return
+	}
+
+	list := []map[string]any{}
+	for _, st := range s.nodeStorage(n) {
+		list = append(list, s.storageJSON(n, st))
+	}
+	writeData(w, list)
+}
@@ -0,0 +1,294 @@
+package fakepve
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type task struct {
+	upid     string
+	node     string
+	kind     string
+	id       string
+	user     string
+	start    time.Time
+	duration time.Duration
+	status   string
+	exit     string
+	log      []string
+	progress []string
+	finish   func() string
+}
+
+func (s *Server) newTask(nodeName, kind, id string, duration time.Duration, finish func() string) *task {
+	t := s.addTask(nodeName, kind, id, s.opts.Username, time.Now(), duration)
+	t.finish = finish
+	return t
+}
+
+func (s *Server) addTask(nodeName, kind, id, user string, start time.Time, duration time.Duration) *task {
+	s.taskSeq++
+	t := &task{
+		node:     nodeName,
+		kind:     kind,
+		id:       id,
+		user:     user,
+		start:    start,
+		duration: duration,
+		status:   "running",
+	}
+	t.upid = fmt.Sprintf("UPID:%s:%08X:%08X:%08X:%s:%s:%s:", nodeName, 10000+s.taskSeq, s.taskSeq*100, start.Unix(), kind, id, t.user)
+	t.log = append(t.log, fmt.Sprintf("starting task %s", t.upid))
+	s.tasks = append(s.tasks, t)
+	return t
+}
+
+func (s *Server) populateTasks() {
+	now := time.Now()
+	users := []string{s.opts.Username, "backup@pve", "ops@pve"}
+	for i, n := range s.nodes {
+		var guests []*guest
+		for _, g := range s.guests {
+			if g.node == n.name {
+				guests = append(guests, g)
+			}
+		}
+		if len(guests) == 0 {
+			continue
+		}
+
+		for j := 0; j < 6; j++ {
+			g := guests[s.rng.Intn(len(guests))]
+			start := now.Add(-time.Duration(60+s.rng.Intn(48*3600)) * time.Second)
+			id := strconv.Itoa(g.vmid)
+			var t *task
+			failure := "job errors"
+			switch j % 3 {
+			case 0:
+				t = s.addTask(n.name, "vzdump", id, users[1], start, time.Duration(60+s.rng.Intn(1200))*time.Second)
+				t.progress = backupLog(g, 5)
+			case 1:
+				prefix := "qm"
+				if g.kind == "lxc" {
+					prefix = "vz"
+				}
+				t = s.addTask(n.name, prefix+"start", id, users[s.rng.Intn(2)*2], start, time.Duration(1+s.rng.Intn(5))*time.Second)
+				failure = "start failed: QEMU exited with code 1"
+			default:
+				target := s.nodes[(i+1)%len(s.nodes)].name
+				t = s.addTask(n.name, "qmigrate", id, users[2], start, time.Duration(20+s.rng.Intn(200))*time.Second)
+				failure = "migration aborted"
+				t.progress = []string{
+					fmt.Sprintf("starting migration of VM %d to node '%s'", g.vmid, target),
+					"starting online/live migration",
+					"migration active, transferred 1.2 GiB of 4.0 GiB VM-state",
+					"migration status: completed",
+				}
+			}
+			t.finish = func() string { return "OK" }
+			if s.rng.Float64() < 0.15 {
+				t.finish = func() string { return failure }
+			}
+		}
+
+		if i == 0 {
+			g := guests[0]
+			t := s.addTask(n.name, "vzdump", strconv.Itoa(g.vmid), users[1], now.Add(-3*time.Minute), 20*time.Minute)
+			t.progress = backupLog(g, 40)
+		}
+	}
+	s.advanceTasks(now)
+}
+
+func backupLog(g *guest, steps int) []string {
+	lines := []string{
+		fmt.Sprintf("INFO: Starting Backup of VM %d (%s)", g.vmid, g.kind),
+		fmt.Sprintf("INFO: VM Name: %s", g.name),
+		"INFO: backup mode: snapshot",
+		"INFO: creating Proxmox Backup Server archive",
+	}
+	for i := 1; i <= steps; i++ {
+		pct := i * 100 / steps
+		done := g.maxDisk / 100 * int64(pct)
+		lines = append(lines, fmt.Sprintf("INFO: %3d%% (%s of %s) in %ds, read: %d MiB/s, write: %d MiB/s",
+			pct, formatGiB(done), formatGiB(g.maxDisk), i*30, 80+i%7*10, 40+i%5*8))
+	}
+	return append(lines, fmt.Sprintf("INFO: Finished Backup of VM %d", g.vmid))
+}
+
+func formatGiB(b int64) string {
+	return fmt.Sprintf("%.1f GiB", float64(b)/float64(gib))
+}
+
+func (t *task) lines(now time.Time) []string {
+	visible := len(t.progress)
+	if t.status == "running" && t.duration > 0 {
+		elapsed := now.Sub(t.start)
+		visible = int(int64(len(t.progress)) * int64(elapsed) / int64(t.duration))
+		if visible > len(t.progress) {
+			visible = len(t.progress)
+		}
+	}
+	lines := append([]string{}, t.log[0])
+	lines = append(lines, t.progress[:visible]...)
+	return append(lines, t.log[1:]...)
+}
+
+func (s *Server) advanceTasks(now time.Time) {
+	for _, t := range s.tasks {
+		if t.status != "running" || now.Sub(t.start) < t.duration {
+			continue
+		}
+		t.status = "stopped"
+		t.exit = "OK"
+		if t.finish != nil {
+			t.exit = t.finish()
+		}
+		if t.exit == "OK" {
+			t.log = append(t.log, "TASK OK")
+		} else {
+			t.log = append(t.log, "TASK ERROR: "+t.exit)
+		}
+	}
+}
+
+func (s *Server) findTask(upid string) *task {
+	for _, t := range s.tasks {
+		if t.upid == upid {
+			return t
+		}
+	}
+	return nil
+}
+
+func (t *task) json() map[string]any {
+	data := map[string]any{
+		"upid":      t.upid,
+		"node":      t.node,
+		"type":      t.kind,
+		"id":        t.id,
+		"user":      t.user,
+		"starttime": t.start.Unix(),
+		"status":    t.status,
+		"pid":       0,
+	}
+	if t.status != "running" {
+		data["exitstatus"] = t.exit
+		data["endtime"] = t.start.Add(t.duration).Unix()
+	}
+	return data
+}
+
+func (t *task) listJSON() map[string]any {
+	data := map[string]any{
+		"upid":      t.upid,
+		"node":      t.node,
+		"type":      t.kind,
+		"id":        t.id,
+		"user":      t.user,
+		"starttime": t.start.Unix(),
+		"pid":       0,
+	}
+	if t.status != "running" {
+		data["status"] = t.exit
+		data["endtime"] = t.start.Add(t.duration).Unix()
+	}
+	return data
+}
+
+func (s *Server) taskList(nodeName string, activeOnly bool) []map[string]any {
+	var tasks []*task
+	for _, t := range s.tasks {
+		if nodeName != "" && t.node != nodeName {
+			continue
+		}
+		if activeOnly && t.status != "running" {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].start.After(tasks[j].start)
+	})
+
+	data := make([]map[string]any, len(tasks))
+	for i, t := range tasks {
+		data[i] = t.listJSON()
+	}
+	return data
+}
+
+func (s *Server) handleClusterTasks(w http.ResponseWriter, r *http.Request, _ []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeData(w, s.taskList("", false))
+}
+
+func (s *Server) handleNodeTasks(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findNode(params[0]) == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("hostname lookup '%s' failed", params[0]))
+		return
+	}
+
+	query := r.URL.Query()
+	tasks := s.taskList(params[0], query.Get("source") == "active")
+	start, _ := strconv.Atoi(query.Get("start"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if start > len(tasks) {
+		start = len(tasks)
+	}
+	if start+limit < len(tasks) {
+		tasks = tasks[:start+limit]
+	}
+	writeData(w, tasks[start:])
+}
+
+func (s *Server) handleTaskLog(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	upid, _ := url.PathUnescape(params[1])
+	t := s.findTask(upid)
+	if t == nil {
+		writeError(w, http.StatusBadRequest, "no such task")
+		return
+	}
+
+	query := r.URL.Query()
+	start, _ := strconv.Atoi(query.Get("start"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	lines := t.lines(time.Now())
+	data := []map[string]any{}
+	for i := start; i < len(lines) && i < start+limit; i++ {
+		data = append(data, map[string]any{"n": i + 1, "t": lines[i]})
+	}
+	writeData(w, data)
+}
+
+func (s *Server) handleTaskStatus(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	upid, _ := url.PathUnescape(params[1])
+	t := s.findTask(upid)
+	if t == nil {
+		writeError(w, http.StatusBadRequest, "no such task")
+		return
+	}
+	writeData(w, t.json())
+}
@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type Sample struct {
+	Time      time.Time
+	CPU       float64
+	Mem       int64
+	MaxMem    int64
+	DiskRead  int64
+	DiskWrite int64
+	NetIn     int64
+	NetOut    int64
+}
+
+type Ring struct {
+	samples []Sample
+	start   int
+	count   int
+	maxAge  time.Duration
+}
+
+func NewRing(capacity int) *Ring {
+	if capacity < 2 {
+		capacity = 2
+	}
+	return &Ring{samples: make([]Sample, capacity)}
+}
+
+func NewWindow(capacity int, maxAge time.Duration) *Ring {
+	r := NewRing(capacity)
+	r.maxAge = maxAge
+	return r
+}
+
+func (r *Ring) Push(s Sample) {
+	// A window keeps every sample younger than maxAge, however often
+	// they arrive, and drops the rest.
+	if r.maxAge > 0 {
+		for r.count > 0 && s.Time.Sub(r.At(0).Time) > r.maxAge {
+			r.start = (r.start + 1) % len(r.samples)
+			r.count--
+		}
+		if r.count == len(r.samples) {
+			samples := make([]Sample, 2*len(r.samples))
+			copy(samples, r.Samples())
+			r.samples, r.start = samples, 0
+		}
+	}
+	if r.count < len(r.samples) {
+		r.samples[(r.start+r.count)%len(r.samples)] = s
+		r.count++
+		return
+	}
+	r.samples[r.start] = s
+	r.start = (r.start + 1) % len(r.samples)
+}
+
+func (r *Ring) Len() int {
+	return r.count
+}
+
+func (r *Ring) At(i int) Sample {
+	return r.samples[(r.start+i)%len(r.samples)]
+}
+
+func (r *Ring) Samples() []Sample {
+	out := make([]Sample, r.count)
+	for i := range out {
+		out[i] = r.At(i)
+	}
+	return out
+}
+
+type Rate struct {
+	Disk float64
+	Net  float64
+}
+
+func counterDelta(prev, next int64) int64 {
+	if next < prev {
+		return 0
+	}
+	return next - prev
+}
+
+func rateBetween(prev, next Sample) Rate {
+	dt := next.Time.Sub(prev.Time).Seconds()
+	if dt <= 0 {
+		return Rate{}
+	}
+	disk := counterDelta(prev.DiskRead, next.DiskRead) + counterDelta(prev.DiskWrite, next.DiskWrite)
+	net := counterDelta(prev.NetIn, next.NetIn) + counterDelta(prev.NetOut, next.NetOut)
+	return Rate{Disk: float64(disk) / dt, Net: float64(net) / dt}
+}
+
+func (r *Ring) Rate(window time.Duration) Rate {
+	if r.count < 2 {
+		return Rate{}
+	}
+	last := r.At(r.count - 1)
+	first := r.count - 2
+	for first > 0 && last.Time.Sub(r.At(first-1).Time) <= window {
+		first--
+	}
+
+	var disk, net int64
+	for i := first; i < r.count-1; i++ {
+		prev, next := r.At(i), r.At(i+1)
+		disk += counterDelta(prev.DiskRead, next.DiskRead) + counterDelta(prev.DiskWrite, next.DiskWrite)
+		net += counterDelta(prev.NetIn, next.NetIn) + counterDelta(prev.NetOut, next.NetOut)
+	}
+	elapsed := last.Time.Sub(r.At(first).Time).Seconds()
+	if elapsed <= 0 {
+		return Rate{}
+	}
+	return Rate{Disk: float64(disk) / elapsed, Net: float64(net) / elapsed}
+}
+
+func (r *Ring) Rates() []Rate {
+	if r.count < 2 {
+		return nil
+	}
+	rates := make([]Rate, r.count-1)
+	for i := range rates {
+		rates[i] = rateBetween(r.At(i), r.At(i+1))
+	}
+	return rates
+}
+
+type History struct {
+	retention time.Duration
+	capacity  int
+	series    map[string]*Ring
+}
+
+func NewHistory(retention, interval time.Duration) *History {
+	capacity := 2
+	if interval > 0 {
+		capacity = int(retention/interval) + 1
+	}
+	return &History{
+		retention: retention,
+		capacity:  capacity,
+		series:    make(map[string]*Ring),
+	}
+}
+
+func (h *History) Retention() time.Duration {
+	return h.retention
+}
+
+func (h *History) Record(key string, s Sample) {
+	ring, ok := h.series[key]
+	if !ok {
+		ring = NewWindow(h.capacity, h.retention)
+		h.series[key] = ring
+	}
+	ring.Push(s)
+}
+
+func (h *History) Get(key string) *Ring {
+	return h.series[key]
+}
+
+func (h *History) Retain(keep map[string]bool) {
+	for key := range h.series {
+		if !keep[key] {
+			delete(h.series, key)
+		}
+	}
+}
+
+type Stats struct {
+	Min float64
+	Avg float64
+	Max float64
+	P95 float64
+}
+
+func Summarize(values []float64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return Stats{
+		Min: sorted[0],
+		Avg: sum / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[rank],
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var epoch = time.Unix(1700000000, 0)
+
+func diskSamples(step time.Duration, counters ...int64) *Ring {
+	r := NewRing(len(counters))
+	for i, c := range counters {
+		r.Push(Sample{Time: epoch.Add(time.Duration(i) * step), DiskRead: c, NetIn: c / 2})
+	}
+	return r
+}
+
+func TestRingRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		ring   *Ring
+		window time.Duration
+		disk   float64
+	}{
+		{"no samples", NewRing(4), time.Minute, 0},
+		{"one sample", diskSamples(2*time.Second, 100), time.Minute, 0},
+		{"steady", diskSamples(2*time.Second, 0, 2000, 4000, 6000), 10 * time.Second, 1000},
+		{"window", diskSamples(2*time.Second, 0, 10000, 12000, 14000), 4 * time.Second, 1000},
+		{"narrower than a step", diskSamples(2*time.Second, 0, 10000, 12000), time.Second, 1000},
+		{"counter reset", diskSamples(2*time.Second, 0, 2000, 500, 2500), 10 * time.Second, 4000.0 / 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := tt.ring.Rate(tt.window)
+			if math.Abs(rate.Disk-tt.disk) > 1e-9 || math.Abs(rate.Net-tt.disk/2) > 1e-9 {
+				t.Errorf("got %+v, want disk %.2f and net %.2f", rate, tt.disk, tt.disk/2)
+			}
+		})
+	}
+}
+
+func TestRingRatesSkipResets(t *testing.T) {
+	rates := diskSamples(time.Second, 100, 300, 50, 150).Rates()
+	want := []float64{200, 0, 100}
+	if len(rates) != len(want) {
+		t.Fatalf("got %d rates, want %d", len(rates), len(want))
+	}
+	for i, rate := range rates {
+		if rate.Disk != want[i] {
+			t.Errorf("rate %d = %.1f, want %.1f", i, rate.Disk, want[i])
+		}
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	tests := []struct {
+		name   string
+		every  time.Duration
+		count  int
+		oldest time.Duration
+		kept   int
+	}{
+		{"faster than the interval", time.Second, 31, 20 * time.Second, 11},
+		{"at the interval", 2 * time.Second, 16, 20 * time.Second, 6},
+		{"slower than the interval", 5 * time.Second, 13, 50 * time.Second, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory(10*time.Second, 2*time.Second)
+			for i := 0; i < tt.count; i++ {
+				h.Record("qemu/100", Sample{Time: epoch.Add(time.Duration(i) * tt.every)})
+			}
+			ring := h.Get("qemu/100")
+			if ring.Len() != tt.kept {
+				t.Errorf("kept %d samples, want %d", ring.Len(), tt.kept)
+			}
+			if got := ring.At(0).Time.Sub(epoch); got != tt.oldest {
+				t.Errorf("oldest sample at %s, want %s", got, tt.oldest)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	sequence := func(n int) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = float64(i + 1)
+		}
+		return values
+	}
+
+	tests := []struct {
+		name   string
+		values []float64
+		want   Stats
+	}{
+		{"empty", nil, Stats{}},
+		{"single", []float64{5}, Stats{Min: 5, Avg: 5, Max: 5, P95: 5}},
+		{"unsorted", []float64{3, 1, 2}, Stats{Min: 1, Avg: 2, Max: 3, P95: 3}},
+		{"twenty", sequence(20), Stats{Min: 1, Avg: 10.5, Max: 20, P95: 19}},
+		{"hundred", sequence(100), Stats{Min: 1, Avg: 50.5, Max: 100, P95: 95}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.values); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -32,6 +32,8 @@ type Guest struct {
 	DiskWrite int64  `json:"diskwrite"`
 	Uptime   int64   `json:"uptime"`
 	PID      int     `json:"pid,omitempty"`
+	Tags     string  `json:"tags,omitempty"`
+	Pool     string  `json:"pool,omitempty"`
 }
 
 type GuestStatus struct {
@@ -50,5 +52,296 @@ type GuestStatus struct {
 	DiskWrite int64   `json:"diskwrite"`
 	Uptime    int64   `json:"uptime"`
 	PID       int     `json:"pid,omitempty"`
+	QMPStatus string  `json:"qmpstatus"`
+	Lock      string  `json:"lock"`
+	Balloon   int64   `json:"balloon"`
+	FreeMem   int64   `json:"freemem"`
+	Swap      int64   `json:"swap"`
+	MaxSwap   int64   `json:"maxswap"`
+	HA        struct {
+		Managed int    `json:"managed"`
+		State   string `json:"state"`
+	} `json:"ha"`
+	Raw       map[string]any `json:"-"`
 	UpdatedAt time.Time
 }
+
+type Storage struct {
+	Storage string `json:"storage"`
+	Node    string `json:"node"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Shared  bool   `json:"shared"`
+	Content string `json:"content"`
+	Used    int64  `json:"used"`
+	Total   int64  `json:"total"`
+	Avail   int64  `json:"avail"`
+	Enabled bool   `json:"enabled"`
+	Active  bool   `json:"active"`
+}
+
+type ClusterResources struct {
+	Nodes   []Node    `json:"nodes"`
+	Guests  []Guest   `json:"guests"`
+	Storage []Storage `json:"storage"`
+}
+
+type TaskStatus struct {
+	UPID       string `json:"upid"`
+	Node       string `json:"node"`
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	User       string `json:"user"`
+	Status     string `json:"status"`
+	ExitStatus string `json:"exitstatus"`
+	StartTime  int64  `json:"starttime"`
+	EndTime    int64  `json:"endtime"`
+}
+
+type MigratePrecondition struct {
+	Running         bool              `json:"running"`
+	AllowedNodes    []string          `json:"allowed_nodes"`
+	NotAllowedNodes map[string]string `json:"not_allowed_nodes"`
+	LocalDisks      []string          `json:"local_disks"`
+	LocalResources  []string          `json:"local_resources"`
+}
+
+type Snapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parent      string `json:"parent"`
+	SnapTime    int64  `json:"snaptime"`
+	VMState     bool   `json:"vmstate"`
+}
+
+type Backup struct {
+	VolID   string `json:"volid"`
+	Storage string `json:"storage"`
+	Node    string `json:"node"`
+	VMID    int    `json:"vmid"`
+	CTime   int64  `json:"ctime"`
+	Size    int64  `json:"size"`
+	Format  string `json:"format"`
+}
+
+type BackupOptions struct {
+	Storage  string
+	Mode     string
+	Compress string
+}
+
+type TaskLogLine struct {
+	N int    `json:"n"`
+	T string `json:"t"`
+}
+
+type GuestDisk struct {
+	Key     string `json:"key"`
+	Volume  string `json:"volume"`
+	Size    string `json:"size"`
+	Media   string `json:"media"`
+	Mount   string `json:"mount"`
+	Options string `json:"options"`
+}
+
+type GuestNIC struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Model    string `json:"model"`
+	MAC      string `json:"mac"`
+	Bridge   string `json:"bridge"`
+	VLAN     string `json:"vlan"`
+	IP       string `json:"ip"`
+	Firewall bool   `json:"firewall"`
+}
+
+type GuestConfig struct {
+	Name        string            `json:"name"`
+	OSType      string            `json:"ostype"`
+	CPUType     string            `json:"cpu"`
+	Sockets     int               `json:"sockets"`
+	Cores       int               `json:"cores"`
+	Memory      int64             `json:"memory"`
+	Balloon     int64             `json:"balloon"`
+	Swap        int64             `json:"swap"`
+	Boot        string            `json:"boot"`
+	OnBoot      bool              `json:"onboot"`
+	Tags        []string          `json:"tags"`
+	Description string            `json:"description"`
+	Disks       []GuestDisk       `json:"disks"`
+	NICs        []GuestNIC        `json:"nics"`
+	Raw         map[string]string `json:"raw"`
+}
+
+type RRDPoint struct {
+	Time      int64   `json:"time"`
+	CPU       float64 `json:"cpu"`
+	MaxCPU    float64 `json:"maxcpu"`
+	Mem       float64 `json:"mem"`
+	MaxMem    float64 `json:"maxmem"`
+	DiskRead  float64 `json:"diskread"`
+	DiskWrite float64 `json:"diskwrite"`
+	NetIn     float64 `json:"netin"`
+	NetOut    float64 `json:"netout"`
+}
+
+type CephHealthCheck struct {
+	Name     string   `json:"name"`
+	Severity string   `json:"severity"`
+	Message  string   `json:"message"`
+	Details  []string `json:"details"`
+	Muted    bool     `json:"muted"`
+}
+
+type CephPGState struct {
+	State string `json:"state"`
+	Count int    `json:"count"`
+}
+
+type CephStatus struct {
+	Health               string            `json:"health"`
+	Checks               []CephHealthCheck `json:"checks"`
+	NumOSDs              int               `json:"num_osds"`
+	UpOSDs               int               `json:"up_osds"`
+	InOSDs               int               `json:"in_osds"`
+	NumPGs               int               `json:"num_pgs"`
+	PGStates             []CephPGState     `json:"pg_states"`
+	BytesUsed            int64             `json:"bytes_used"`
+	BytesAvail           int64             `json:"bytes_avail"`
+	BytesTotal           int64             `json:"bytes_total"`
+	ReadBytesSec         int64             `json:"read_bytes_sec"`
+	WriteBytesSec        int64             `json:"write_bytes_sec"`
+	ReadOpsSec           int64             `json:"read_op_per_sec"`
+	WriteOpsSec          int64             `json:"write_op_per_sec"`
+	RecoveringBytesSec   int64             `json:"recovering_bytes_per_sec"`
+	RecoveringObjectsSec int64             `json:"recovering_objects_per_sec"`
+	DegradedRatio        float64           `json:"degraded_ratio"`
+	MisplacedRatio       float64           `json:"misplaced_ratio"`
+}
+
+type CephOSD struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Host        string `json:"host"`
+	Status      string `json:"status"`
+	In          bool   `json:"in"`
+	DeviceClass string `json:"device_class"`
+	Used        int64  `json:"bytes_used"`
+	Total       int64  `json:"total_space"`
+}
+
+type CephPool struct {
+	Name        string  `json:"pool_name"`
+	Size        int     `json:"size"`
+	MinSize     int     `json:"min_size"`
+	PGs         int     `json:"pg_num"`
+	Used        int64   `json:"bytes_used"`
+	PercentUsed float64 `json:"percent_used"`
+	CrushRule   string  `json:"crush_rule_name"`
+}
+
+type Ceph struct {
+	Status CephStatus `json:"status"`
+	OSDs   []CephOSD  `json:"osds"`
+	Pools  []CephPool `json:"pools"`
+}
+
+type HALRM struct {
+	Node      string `json:"node"`
+	State     string `json:"state"`
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type HAResource struct {
+	SID         string `json:"sid"`
+	Type        string `json:"type"`
+	VMID        int    `json:"vmid"`
+	Node        string `json:"node"`
+	Requested   string `json:"requested"`
+	State       string `json:"state"`
+	Group       string `json:"group,omitempty"`
+	MaxRestart  int    `json:"max_restart"`
+	MaxRelocate int    `json:"max_relocate"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+type HAStatus struct {
+	Quorate         bool         `json:"quorate"`
+	QuorumNode      string       `json:"quorum_node"`
+	Master          string       `json:"master"`
+	MasterStatus    string       `json:"master_status"`
+	MasterTimestamp int64        `json:"master_timestamp"`
+	LRMs            []HALRM      `json:"lrms"`
+	Resources       []HAResource `json:"resources"`
+}
+
+type ClusterNode struct {
+	Name   string `json:"name"`
+	NodeID int    `json:"nodeid"`
+	IP     string `json:"ip"`
+	Online bool   `json:"online"`
+	Local  bool   `json:"local"`
+	Votes  int    `json:"votes"`
+}
+
+type ClusterStatus struct {
+	Name          string        `json:"name"`
+	Quorate       bool          `json:"quorate"`
+	Version       int           `json:"version"`
+	ExpectedVotes int           `json:"expected_votes"`
+	TotalVotes    int           `json:"total_votes"`
+	Nodes         []ClusterNode `json:"nodes"`
+}
+
+type ReplicationJob struct {
+	ID        string  `json:"id"`
+	Guest     int     `json:"guest"`
+	JobNum    int     `json:"jobnum"`
+	GuestType string  `json:"vmtype"`
+	Source    string  `json:"source"`
+	Target    string  `json:"target"`
+	Schedule  string  `json:"schedule"`
+	Comment   string  `json:"comment,omitempty"`
+	Disabled  bool    `json:"disabled"`
+	LastSync  int64   `json:"last_sync"`
+	LastTry   int64   `json:"last_try"`
+	NextSync  int64   `json:"next_sync"`
+	Duration  float64 `json:"duration"`
+	FailCount int     `json:"fail_count"`
+	Error     string  `json:"error,omitempty"`
+	Running   bool    `json:"running"`
+}
+
+type NodePressure struct {
+	CPUSome    float64 `json:"cpu_some"`
+	IOSome     float64 `json:"io_some"`
+	IOFull     float64 `json:"io_full"`
+	MemorySome float64 `json:"memory_some"`
+	MemoryFull float64 `json:"memory_full"`
+}
+
+type NodeStatus struct {
+	Node       string        `json:"node"`
+	CPUModel   string        `json:"cpu_model"`
+	CPUMHz     float64       `json:"cpu_mhz"`
+	Sockets    int           `json:"sockets"`
+	Cores      int           `json:"cores"`
+	CPUs       int           `json:"cpus"`
+	Kernel     string        `json:"kernel"`
+	PVEVersion string        `json:"pveversion"`
+	BootMode   string        `json:"boot_mode"`
+	SecureBoot bool          `json:"secureboot"`
+	CPU        float64       `json:"cpu"`
+	IOWait     float64       `json:"wait"`
+	LoadAvg    [3]float64    `json:"loadavg"`
+	MemUsed    int64         `json:"mem_used"`
+	MemTotal   int64         `json:"mem_total"`
+	SwapUsed   int64         `json:"swap_used"`
+	SwapTotal  int64         `json:"swap_total"`
+	RootUsed   int64         `json:"rootfs_used"`
+	RootTotal  int64         `json:"rootfs_total"`
+	KSMShared  int64         `json:"ksm_shared"`
+	Uptime     int64         `json:"uptime"`
+	Pressure   *NodePressure `json:"pressure,omitempty"`
+}
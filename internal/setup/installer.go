@@ -1,6 +1,7 @@
 package setup
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,6 +19,8 @@ type installState int
 
 const (
 	stateForm installState = iota
+	stateFetchingCert
+	stateTrust
 	stateConnecting
 	stateCreatingToken
 	stateSaving
@@ -25,6 +28,8 @@ const (
 	stateError
 )
 
+const setupTimeout = 15 * time.Second
+
 const (
 	focusHost = iota
 	focusPort
@@ -49,6 +54,7 @@ type installerModel struct {
 	client        *api.Client
 	progress      float64
 	errorMsg      string
+	caFile        string
 }
 
 type progressMsg struct {
@@ -56,9 +62,10 @@ type progressMsg struct {
 	message string
 	error   error
 	token   string
+	fingerprint string
 }
 
-func NewInstallerModel() installerModel {
+func NewInstallerModel(caFile string) installerModel {
 	hostInput := textinput.New()
 	hostInput.Placeholder = ""
 	hostInput.Focus()
@@ -95,6 +102,7 @@ func NewInstallerModel() installerModel {
 		realmInput:   realmInput,
 		focusedInput: 0,
 		statusMsg:    "",
+		caFile:       caFile,
 	}
 }
 
@@ -111,6 +119,22 @@ func (m installerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case tea.KeyMsg:
+		if m.state == stateTrust {
+			switch msg.String() {
+			case "enter", "y":
+				m.state = stateConnecting
+				m.statusMsg = "Certificate trusted. Testing connection to " + m.config.Host
+				return m, m.testConnection()
+			case "n", "esc":
+				m.state = stateForm
+				m.statusMsg = "Certificate rejected"
+				return m, nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
 		if m.state != stateForm {
 			switch msg.String() {
 			case "ctrl+c", "q":
@@ -191,9 +215,14 @@ func (m installerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.token != "" {
 				m.config.Token = msg.token
 			}
+			if msg.fingerprint != "" {
+				m.config.Fingerprint = msg.fingerprint
+			}
 		}
 
 		switch msg.state {
+		case stateFetchingCert:
+			return m, m.fetchCertificate()
 		case stateConnecting:
 			return m, m.testConnection()
 		case stateCreatingToken:
@@ -292,23 +321,76 @@ func (m installerModel) startInstallation() (installerModel, tea.Cmd) {
 		Host:     host,
 		Port:	  port,
 		Username: username,
+		CAFile:   m.caFile,
 	}
 
 	m.statusMsg = "Starting Proxmox VE setup..."
-	m.state = stateConnecting
 
+	if m.caFile != "" {
+		m.state = stateConnecting
+		return m, func() tea.Msg {
+			return progressMsg{
+				state:   stateConnecting,
+				message: "Testing connection to " + host + " using CA bundle " + m.caFile,
+			}
+		}
+	}
+
+	m.state = stateFetchingCert
 	return m, func() tea.Msg {
 		return progressMsg{
-			state:   stateConnecting,
-			message: "Testing connection to " + host,
+			state:   stateFetchingCert,
+			message: "Fetching server certificate from " + host,
+		}
+	}
+}
+
+func (m installerModel) fetchCertificate() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+		defer cancel()
+
+		fingerprint, err := api.FetchFingerprint(ctx, m.config.Host, m.config.Port)
+		if err != nil {
+			return progressMsg{
+				state:   stateError,
+				message: "Failed to fetch server certificate",
+				error:   err,
+			}
+		}
+
+		half := len(fingerprint) / 2
+		return progressMsg{
+			state:       stateTrust,
+			message:     "Server certificate SHA-256 fingerprint:\n" + fingerprint[:half] + "\n" + fingerprint[half+1:] + "\n\nCompare it with the node's certificate in the web UI before trusting it.",
+			fingerprint: fingerprint,
 		}
 	}
 }
 
+func (m installerModel) newClient() (*api.Client, error) {
+	client := api.NewClient(m.config.Host, m.config.Port)
+	err := client.ConfigureTLS(api.TLSConfig{
+		Fingerprint: m.config.Fingerprint,
+		CAFile:      m.config.CAFile,
+	})
+	return client, err
+}
+
 func (m installerModel) testConnection() tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewClient(m.config.Host, m.config.Port)
-		if err := client.Login(m.config.Username, m.passInput.Value()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+		defer cancel()
+
+		client, err := m.newClient()
+		if err != nil {
+			return progressMsg{
+				state:   stateError,
+				message: "Invalid TLS settings",
+				error:   err,
+			}
+		}
+		if err := client.Login(ctx, m.config.Username, m.passInput.Value()); err != nil {
 			return progressMsg{
 				state:   stateError,
 				message: "Connection failed",
@@ -316,7 +398,7 @@ func (m installerModel) testConnection() tea.Cmd {
 			}
 		}
 
-		nodes, err := client.GetNodes()
+		nodes, err := client.GetNodes(ctx)
 		if err != nil {
 			return progressMsg{
 				state:   stateError,
@@ -334,8 +416,18 @@ func (m installerModel) testConnection() tea.Cmd {
 
 func (m installerModel) createToken() tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewClient(m.config.Host, m.config.Port)
-		if err := client.Login(m.config.Username, m.passInput.Value()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+		defer cancel()
+
+		client, err := m.newClient()
+		if err != nil {
+			return progressMsg{
+				state:   stateError,
+				message: "Invalid TLS settings",
+				error:   err,
+			}
+		}
+		if err := client.Login(ctx, m.config.Username, m.passInput.Value()); err != nil {
 			return progressMsg{
 				state:   stateError,
 				message: "Failed to authenticate for token creation",
@@ -345,7 +437,7 @@ func (m installerModel) createToken() tea.Cmd {
 
 		tokenID := fmt.Sprintf("pvetop-%d", time.Now().Unix())
 		
-		token, err := client.CreateAPIToken(m.config.Username, tokenID)
+		token, err := client.CreateAPIToken(ctx, m.config.Username, tokenID)
 		if err != nil {
 			return progressMsg{
 				state:   stateError,
@@ -538,6 +630,8 @@ func (m installerModel) View() string {
 		status = m.statusMsg
 		if m.state == stateError {
 			statusColor = theme.Catppuccin.Red
+		} else if m.state == stateTrust {
+			statusColor = theme.Catppuccin.Yellow
 		} else {
 			statusColor = theme.Catppuccin.Blue
 		}
@@ -588,6 +682,8 @@ func (m installerModel) View() string {
 		} else {
 			help = "Tab/Enter: Navigate • ↑↓: Navigate • Ctrl+C: Quit"
 		}
+	} else if m.state == stateTrust {
+		help = "Enter/y: Trust certificate • n/Esc: Back • Ctrl+C: Quit"
 	} else if m.state == stateComplete || m.state == stateError {
 		help = "Enter: Continue • Ctrl+C: Quit"
 	} else {
@@ -0,0 +1,107 @@
+package setup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/config"
+	"github.com/berocorpdotnet/pvetop/internal/fakepve"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestInstaller(s *fakepve.Server, password string) installerModel {
+	host, port := s.HostPort()
+	m := NewInstallerModel("")
+	m.hostInput.SetValue(host)
+	m.portInput.SetValue(port)
+	m.userInput.SetValue("root")
+	m.passInput.SetValue(password)
+	return m
+}
+
+func runInstaller(m installerModel, cmd tea.Cmd) installerModel {
+	for cmd != nil {
+		msg, ok := cmd().(progressMsg)
+		if !ok {
+			break
+		}
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(installerModel)
+	}
+	return m
+}
+
+func pressKey(m installerModel, key rune) (installerModel, tea.Cmd) {
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+	return next.(installerModel), cmd
+}
+
+func TestInstallerTokenAndFingerprint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s := fakepve.New(fakepve.Options{Nodes: 2, GuestsPerNode: 1, Seed: 1})
+	defer s.Close()
+
+	m, cmd := newTestInstaller(s, "demo").startInstallation()
+	m = runInstaller(m, cmd)
+	if m.state != stateTrust {
+		t.Fatalf("state %d, want the trust prompt: %s", m.state, m.statusMsg)
+	}
+	if m.config.Fingerprint != s.Fingerprint() {
+		t.Fatalf("offered fingerprint %s, want %s", m.config.Fingerprint, s.Fingerprint())
+	}
+
+	m, cmd = pressKey(m, 'y')
+	m = runInstaller(m, cmd)
+	if m.state != stateComplete {
+		t.Fatalf("state %d, want complete: %s", m.state, m.statusMsg)
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Fingerprint != s.Fingerprint() || saved.Username != "root@pam" {
+		t.Errorf("saved config = %+v", saved)
+	}
+
+	host, port := s.HostPort()
+	client := api.NewClientWithToken(host, port, saved.Token)
+	if err := client.ConfigureTLS(api.TLSConfig{Fingerprint: saved.Fingerprint}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetNodes(context.Background()); err != nil {
+		t.Errorf("saved token does not work: %v", err)
+	}
+}
+
+func TestInstallerRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		key      rune
+		want     installState
+	}{
+		{"certificate rejected", "demo", 'n', stateForm},
+		{"wrong password", "wrong", 'y', stateError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			s := fakepve.New(fakepve.Options{Nodes: 1, GuestsPerNode: 1, Seed: 1})
+			defer s.Close()
+
+			m, cmd := newTestInstaller(s, tt.password).startInstallation()
+			m = runInstaller(m, cmd)
+			m, cmd = pressKey(m, tt.key)
+			m = runInstaller(m, cmd)
+			if m.state != tt.want {
+				t.Errorf("state %d, want %d: %s", m.state, tt.want, m.statusMsg)
+			}
+			if m.config.Token != "" {
+				t.Errorf("created a token: %s", m.config.Token)
+			}
+		})
+	}
+}
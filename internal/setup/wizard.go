@@ -12,8 +12,8 @@ import (
 	"github.com/berocorpdotnet/pvetop/internal/config"
 )
 
-func RunSetupWizard() (*config.Config, error) {
-	model := NewInstallerModel()
+func RunSetupWizard(caFile string) (*config.Config, error) {
+	model := NewInstallerModel(caFile)
 	
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	actionTimeout  = 30 * time.Second
+	noticeDuration = 5 * time.Second
+	taskLinger     = 10 * time.Second
+)
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+type confirmDialog struct {
+	prompt string
+	onYes  tea.Cmd
+}
+
+type trackedTask struct {
+	node       string
+	upid       string
+	label      string
+	started    time.Time
+	finished   time.Time
+	status     string
+	exitStatus string
+	err        error
+	tailLog    bool
+	logLines   int
+	progress   string
+}
+
+type actionStartedMsg struct {
+	node    string
+	upid    string
+	label   string
+	tailLog bool
+	err     error
+}
+
+type taskStatusMsg struct {
+	upid   string
+	status *models.TaskStatus
+	err    error
+}
+
+type taskProgressMsg struct {
+	upid  string
+	start int
+	lines []models.TaskLogLine
+}
+
+var actionLabels = map[string]string{
+	api.ActionStart:    "Start",
+	api.ActionShutdown: "Shut down",
+	api.ActionStop:     "Stop",
+	api.ActionReboot:   "Reboot",
+	api.ActionSuspend:  "Suspend",
+	api.ActionResume:   "Resume",
+}
+
+func guestLabel(guest models.Guest) string {
+	kind := "VM"
+	if guest.Type == "lxc" {
+		kind = "CT"
+	}
+	return fmt.Sprintf("%s %d (%s)", kind, guest.VMID, guest.Name)
+}
+
+func (m *Model) setNotice(format string, args ...any) {
+	m.notice = fmt.Sprintf(format, args...)
+	m.noticeAt = time.Now()
+}
+
+func (m Model) selectedGuest() (models.Guest, bool) {
+	if m.viewMode == viewGuestDetail && m.detail != nil {
+		return m.detail.guest, true
+	}
+	row, ok := m.selectedGuestRow()
+	if !ok || row.header {
+		return models.Guest{}, false
+	}
+	return row.guest, true
+}
+
+func guestActionBlocked(guest models.Guest, action string) string {
+	running := guest.Status == "running"
+	if action == api.ActionStart && running {
+		return "already running"
+	}
+	if action != api.ActionStart && action != api.ActionResume && !running {
+		return "not running"
+	}
+	return ""
+}
+
+func (m Model) requestGuestAction(action string) (Model, tea.Cmd) {
+	if m.hasMarks() {
+		return m.requestBulkAction(action)
+	}
+	guest, ok := m.selectedGuest()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return m, nil
+	}
+
+	if reason := guestActionBlocked(guest, action); reason != "" {
+		m.setNotice("%s is %s", guestLabel(guest), reason)
+		return m, nil
+	}
+
+	label := fmt.Sprintf("%s %s", actionLabels[action], guestLabel(guest))
+	prompt := fmt.Sprintf("%s on %s", label, guest.Node)
+	if warning := m.haRestartWarning(guest, action); warning != "" {
+		prompt += " (" + warning + ")"
+	}
+	m.confirm = &confirmDialog{
+		prompt: prompt + "?",
+		onYes:  m.runGuestAction(guest, action, label),
+	}
+	return m, nil
+}
+
+func (m Model) runGuestAction(guest models.Guest, action, label string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		upid, err := client.GuestAction(ctx, guest.Node, guest.Type, guest.VMID, action)
+		return actionStartedMsg{node: guest.Node, upid: upid, label: label, err: err}
+	}
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		cmd := m.confirm.onYes
+		m.confirm = nil
+		return m, cmd
+	case "n", "N", "esc", "enter", "q":
+		m.confirm = nil
+		m.setNotice("Cancelled")
+	case "ctrl+c":
+		m.confirm = nil
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) pollTasks() tea.Cmd {
+	var cmds []tea.Cmd
+	client := m.client
+	for _, task := range m.tasks {
+		if !task.finished.IsZero() {
+			continue
+		}
+		node, upid := task.node, task.upid
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+			defer cancel()
+
+			status, err := client.GetTaskStatus(ctx, node, upid)
+			return taskStatusMsg{upid: upid, status: status, err: err}
+		})
+		if task.tailLog {
+			start := task.logLines
+			cmds = append(cmds, func() tea.Msg {
+				ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+				defer cancel()
+
+				lines, _ := client.GetTaskLog(ctx, node, upid, start)
+				return taskProgressMsg{upid: upid, start: start, lines: lines}
+			})
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) pruneTasks() {
+	var active []trackedTask
+	for _, task := range m.tasks {
+		if task.finished.IsZero() || time.Since(task.finished) < taskLinger {
+			active = append(active, task)
+		}
+	}
+	m.tasks = active
+}
+
+func (m *Model) trackTask(msg actionStartedMsg) {
+	if msg.err != nil {
+		m.setNotice("%s failed: %v", msg.label, msg.err)
+		return
+	}
+	m.tasks = append(m.tasks, trackedTask{
+		node:    msg.node,
+		upid:    msg.upid,
+		label:   msg.label,
+		started: time.Now(),
+		status:  "running",
+		tailLog: msg.tailLog,
+	})
+}
+
+func (m *Model) updateTaskProgress(msg taskProgressMsg) {
+	for i := range m.tasks {
+		task := &m.tasks[i]
+		if task.upid != msg.upid || task.logLines != msg.start {
+			continue
+		}
+		task.logLines += len(msg.lines)
+		for _, line := range msg.lines {
+			if text := strings.TrimSpace(line.T); text != "" && !strings.HasPrefix(text, "TASK ") && !strings.HasPrefix(text, "starting task ") {
+				task.progress = text
+			}
+		}
+		return
+	}
+}
+
+func (m *Model) updateTask(msg taskStatusMsg) {
+	for i := range m.tasks {
+		task := &m.tasks[i]
+		if task.upid != msg.upid || !task.finished.IsZero() {
+			continue
+		}
+		if msg.err != nil {
+			task.err = msg.err
+			task.finished = time.Now()
+			return
+		}
+		task.status = msg.status.Status
+		if msg.status.Status != "running" {
+			task.exitStatus = msg.status.ExitStatus
+			task.finished = time.Now()
+			if isBackupTask(task.upid) {
+				m.backupsAt = time.Time{}
+			}
+		}
+		return
+	}
+}
+
+func (t trackedTask) summary() (string, lipgloss.Color) {
+	switch {
+	case t.err != nil:
+		return fmt.Sprintf("✗ %s: %v", t.label, t.err), theme.Catppuccin.Red
+	case t.finished.IsZero() && t.progress != "":
+		return fmt.Sprintf("⟳ %s (%ds): %s", t.label, int(time.Since(t.started).Seconds()), t.progress), theme.Catppuccin.Yellow
+	case t.finished.IsZero():
+		return fmt.Sprintf("⟳ %s (%ds)", t.label, int(time.Since(t.started).Seconds())), theme.Catppuccin.Yellow
+	case t.exitStatus == "OK":
+		return fmt.Sprintf("✓ %s", t.label), theme.Catppuccin.Green
+	default:
+		return fmt.Sprintf("✗ %s: %s", t.label, t.exitStatus), theme.Catppuccin.Red
+	}
+}
+
+func (m Model) renderStatusBar() string {
+	if m.confirm != nil {
+		confirmStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Catppuccin.Base).
+			Background(theme.Catppuccin.Red).
+			Width(m.width)
+		return confirmStyle.Render(truncate(" "+m.confirm.prompt+" [y/N]", m.width))
+	}
+
+	if m.filterPrompt != nil {
+		return m.renderFilterPrompt()
+	}
+
+	if m.notice != "" && time.Since(m.noticeAt) < noticeDuration {
+		noticeStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Peach)
+		return noticeStyle.Render(truncate(" "+m.notice, m.width))
+	}
+
+	var parts []string
+	remaining := m.width - 1
+	if m.bulk != nil && (m.bulk.finished.IsZero() || time.Since(m.bulk.finished) < taskLinger) {
+		text, color := m.bulk.summary()
+		text = truncate(text, remaining)
+		parts = append(parts, lipgloss.NewStyle().Foreground(color).Render(text))
+		remaining -= len([]rune(text)) + 3
+	}
+	for i := len(m.tasks) - 1; i >= 0 && remaining > 0; i-- {
+		text, color := m.tasks[i].summary()
+		text = truncate(text, remaining)
+		parts = append(parts, lipgloss.NewStyle().Foreground(color).Render(text))
+		remaining -= len([]rune(text)) + 3
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " | ")
+}
+
+func highlightRow(row string, width int) string {
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface0).
+		Width(width)
+	return selectedStyle.Render(ansiPattern.ReplaceAllString(row, ""))
+}
+
+func (m Model) viewHelp() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue).Bold(true).Width(12)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	s := titleStyle.Render(" pvetop - keyboard shortcuts") + "\n\n"
+	for _, binding := range m.keys.helpBindings() {
+		help := binding.Help()
+		s += " " + keyStyle.Render(help.Key) + descStyle.Render(help.Desc) + "\n"
+	}
+	s += "\n" + descStyle.Render(" Press any key to close")
+	return s
+}
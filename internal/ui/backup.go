@@ -0,0 +1,423 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	backupRefresh       = time.Minute
+	defaultBackupMaxAge = 36 * time.Hour
+)
+
+var backupModes = []string{api.BackupModeSnapshot, api.BackupModeSuspend, api.BackupModeStop}
+
+var backupCompressions = []string{"zstd", "lzo", "gzip", "0"}
+
+var (
+	vzdumpStarted  = regexp.MustCompile(`Starting Backup of VM (\d+)\b`)
+	vzdumpFinished = regexp.MustCompile(`Finished Backup of VM (\d+)\b`)
+	vzdumpFailed   = regexp.MustCompile(`ERROR: Backup of VM (\d+) failed - (.*)$`)
+)
+
+type backupForm struct {
+	guests   []models.Guest
+	field    int
+	storage  int
+	mode     int
+	compress int
+}
+
+type backupsMsg struct {
+	backups []models.Backup
+	failed  []api.StorageError
+	err     error
+}
+
+func (m *Model) SetBackupMaxAge(age time.Duration) {
+	m.backupMaxAge = age
+}
+
+func (m Model) backupsDue() bool {
+	return !m.backupsLoading && time.Since(m.backupsAt) >= backupRefresh
+}
+
+func (m Model) fetchBackups() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		result, err := client.GetBackups(ctx)
+		if result == nil {
+			return backupsMsg{err: err}
+		}
+		return backupsMsg{backups: result.Backups, failed: result.StorageErrors, err: err}
+	}
+}
+
+func (m *Model) updateBackups(msg backupsMsg) {
+	m.backupsLoading = false
+	m.backupsAt = time.Now()
+	m.backupsErr = msg.err
+	m.backupsFailed = msg.failed
+	if msg.err != nil {
+		return
+	}
+
+	latest := make(map[int]int64)
+	for _, b := range msg.backups {
+		if b.CTime > latest[b.VMID] {
+			latest[b.VMID] = b.CTime
+		}
+	}
+	for _, guest := range m.guests {
+		if prev := m.lastBackups[guest.VMID]; prev > latest[guest.VMID] && m.backupStatusError(guest) != nil {
+			latest[guest.VMID] = prev
+		}
+	}
+	m.lastBackups = latest
+}
+
+func (m Model) backupStatusError(guest models.Guest) error {
+	if m.backupsErr != nil {
+		return m.backupsErr
+	}
+	for _, failed := range m.backupsFailed {
+		if failed.Storage.Shared || failed.Storage.Node == guest.Node {
+			return fmt.Errorf("%s on %s: %w", failed.Storage.Storage, failed.Storage.Node, failed.Err)
+		}
+	}
+	return nil
+}
+
+func (m Model) backupSummary(guest models.Guest) string {
+	if age, ok := m.backupAge(guest); ok {
+		return formatAge(age) + " ago"
+	}
+	if err := m.backupStatusError(guest); err != nil {
+		return fmt.Sprintf("backup status unavailable (%v)", err)
+	}
+	if m.lastBackups == nil {
+		return ""
+	}
+	return "never"
+}
+
+func (m Model) backupAge(guest models.Guest) (time.Duration, bool) {
+	ctime, ok := m.lastBackups[guest.VMID]
+	if !ok {
+		return 0, false
+	}
+	return time.Since(time.Unix(ctime, 0)), true
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func (m Model) formatBackupAge(guest models.Guest) string {
+	age, ok := m.backupAge(guest)
+	text, color := "never", theme.Catppuccin.Red
+	switch {
+	case ok:
+		text = formatAge(age)
+		if age <= m.backupMaxAge {
+			color = theme.Catppuccin.Green
+		}
+	case m.backupStatusError(guest) != nil:
+		text, color = "unknown", theme.Catppuccin.Yellow
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("%8s", text))
+}
+
+func (m Model) requestBackup() (Model, tea.Cmd) {
+	guests, ok := m.bulkTargets()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return m, nil
+	}
+	if len(guests) > 1 && m.bulkBusy() {
+		m.setNotice("Wait for the running bulk action to finish")
+		return m, nil
+	}
+
+	m.backup = &backupForm{guests: guests}
+	return m, nil
+}
+
+func (m Model) backupStorages(guests []models.Guest) []models.Storage {
+	nodes := make(map[string]bool)
+	for _, guest := range guests {
+		nodes[guest.Node] = true
+	}
+
+	available := make(map[string]int)
+	byName := make(map[string]models.Storage)
+	for _, st := range m.storage {
+		if !st.Enabled || !st.Active || !strings.Contains(st.Content, "backup") || !nodes[st.Node] {
+			continue
+		}
+		available[st.Storage]++
+		byName[st.Storage] = st
+	}
+
+	var storages []models.Storage
+	for name, count := range available {
+		if count == len(nodes) {
+			storages = append(storages, byName[name])
+		}
+	}
+	sort.Slice(storages, func(i, j int) bool {
+		if (storages[i].Type == "pbs") != (storages[j].Type == "pbs") {
+			return storages[i].Type == "pbs"
+		}
+		return storages[i].Storage < storages[j].Storage
+	})
+	return storages
+}
+
+func (m Model) updateBackupKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	form := *m.backup
+	storages := m.backupStorages(form.guests)
+	cycle := func(delta int) {
+		switch form.field {
+		case 0:
+			if len(storages) > 0 {
+				form.storage = (form.storage + delta + len(storages)) % len(storages)
+			}
+		case 1:
+			form.mode = (form.mode + delta + len(backupModes)) % len(backupModes)
+		case 2:
+			form.compress = (form.compress + delta + len(backupCompressions)) % len(backupCompressions)
+		}
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		m.backup = nil
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), msg.String() == "q":
+		m.backup = nil
+		m.setNotice("Cancelled")
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		form.field = (form.field + 2) % 3
+	case key.Matches(msg, m.keys.Down), msg.String() == "tab":
+		form.field = (form.field + 1) % 3
+	case msg.String() == "left", msg.String() == "h":
+		cycle(-1)
+	case msg.String() == "right", msg.String() == "l", msg.String() == " ":
+		cycle(1)
+	case key.Matches(msg, m.keys.Open):
+		m.backup = &form
+		return m.confirmBackup()
+	}
+	m.backup = &form
+	return m, nil
+}
+
+func (m Model) backupOptions(form *backupForm) (models.BackupOptions, bool) {
+	storages := m.backupStorages(form.guests)
+	if len(storages) == 0 {
+		return models.BackupOptions{}, false
+	}
+	st := storages[form.storage%len(storages)]
+	opts := models.BackupOptions{Storage: st.Storage, Mode: backupModes[form.mode]}
+	if st.Type != "pbs" {
+		opts.Compress = backupCompressions[form.compress]
+	}
+	return opts, true
+}
+
+func (m Model) confirmBackup() (Model, tea.Cmd) {
+	form := m.backup
+	opts, ok := m.backupOptions(form)
+	if !ok {
+		m.setNotice("No backup storage is available on the guests' nodes")
+		return m, nil
+	}
+
+	details := opts.Mode
+	if opts.Compress != "" {
+		details += ", " + compressionLabel(opts.Compress)
+	}
+	m.backup = nil
+
+	if len(form.guests) == 1 {
+		guest := form.guests[0]
+		m.confirm = &confirmDialog{
+			prompt: fmt.Sprintf("Back up %s to %s (%s)?", guestLabel(guest), opts.Storage, details),
+			onYes:  m.runBackup(guest, opts),
+		}
+		return m, nil
+	}
+
+	label := "Backup " + pluralGuests(len(form.guests))
+	m.confirm = &confirmDialog{
+		prompt: fmt.Sprintf("%s on %s to %s (%s), one vzdump task per node?", label, countNodes(form.guests), opts.Storage, details),
+		onYes: func() tea.Msg {
+			return bulkStartMsg{label: label, backup: true, guests: form.guests, nodeStep: func(ctx context.Context, client api.DataSource, node string, guests []models.Guest) (string, error) {
+				vmids := make([]int, len(guests))
+				for i, guest := range guests {
+					vmids[i] = guest.VMID
+				}
+				return client.StartBackup(ctx, node, vmids, opts)
+			}, nodeOutcome: vzdumpOutcome}
+		},
+	}
+	return m, nil
+}
+
+func (m Model) runBackup(guest models.Guest, opts models.BackupOptions) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		upid, err := client.StartBackup(ctx, guest.Node, []int{guest.VMID}, opts)
+		return actionStartedMsg{node: guest.Node, upid: upid, label: "Backup " + guestLabel(guest), tailLog: true, err: err}
+	}
+}
+
+type vzdumpGuest struct {
+	finished bool
+	err      string
+	warnings int
+}
+
+func vzdumpOutcome(log []string, status *models.TaskStatus, guests []models.Guest) []bulkOutcome {
+	results := make(map[int]*vzdumpGuest)
+	result := func(match string) *vzdumpGuest {
+		vmid, _ := strconv.Atoi(match)
+		if results[vmid] == nil {
+			results[vmid] = &vzdumpGuest{}
+		}
+		return results[vmid]
+	}
+
+	var current *vzdumpGuest
+	for _, line := range log {
+		if match := vzdumpFailed.FindStringSubmatch(line); match != nil {
+			result(match[1]).err = match[2]
+			current = nil
+		} else if match := vzdumpFinished.FindStringSubmatch(line); match != nil {
+			result(match[1]).finished = true
+			current = nil
+		} else if match := vzdumpStarted.FindStringSubmatch(line); match != nil {
+			current = result(match[1])
+		} else if current != nil && strings.Contains(line, "WARN:") {
+			current.warnings++
+		}
+	}
+
+	outcomes := make([]bulkOutcome, len(guests))
+	for i, guest := range guests {
+		r := results[guest.VMID]
+		switch {
+		case r != nil && r.err != "":
+			outcomes[i].err = errors.New(r.err)
+		case r != nil && r.finished && r.warnings == 1:
+			outcomes[i].warning = "1 warning, see the task log"
+		case r != nil && r.finished && r.warnings > 1:
+			outcomes[i].warning = fmt.Sprintf("%d warnings, see the task log", r.warnings)
+		case r != nil && r.finished:
+		default:
+			outcomes[i] = taskOutcome(status)
+			if outcomes[i].err != nil {
+				outcomes[i].err = fmt.Errorf("not backed up: %w", outcomes[i].err)
+			}
+		}
+	}
+	return outcomes
+}
+
+func compressionLabel(compress string) string {
+	if compress == "0" {
+		return "uncompressed"
+	}
+	return compress
+}
+
+func (m Model) viewBackup() string {
+	form := m.backup
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(14)
+	focusStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue).Bold(true).Width(14)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Text)
+	greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	target := guestLabel(form.guests[0])
+	if len(form.guests) > 1 {
+		target = pluralGuests(len(form.guests))
+	}
+	s := titleStyle.Render(truncate(fmt.Sprintf(" pvetop - back up %s ", target), m.width)) + "\n\n"
+
+	row := func(field int, label, value string) string {
+		style := labelStyle
+		if form.field == field {
+			style = focusStyle
+			value = "‹ " + value + " ›"
+		}
+		return " " + style.Render(label) + valueStyle.Render(value) + "\n"
+	}
+
+	storages := m.backupStorages(form.guests)
+	opts, ok := m.backupOptions(form)
+	switch {
+	case ok:
+		st := storages[form.storage%len(storages)]
+		s += row(0, "Storage", fmt.Sprintf("%s (%s)", st.Storage, st.Type))
+	case m.storage == nil:
+		s += " " + labelStyle.Render("Storage") + greyStyle.Render("loading...") + "\n"
+	default:
+		s += " " + labelStyle.Render("Storage") + lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render("no backup storage available") + "\n"
+	}
+	s += row(1, "Mode", backupModes[form.mode])
+	if ok && opts.Compress == "" {
+		s += " " + labelStyle.Render("Compression") + greyStyle.Render("done by Proxmox Backup Server") + "\n"
+	} else {
+		s += row(2, "Compression", compressionLabel(backupCompressions[form.compress]))
+	}
+
+	if len(form.guests) > 1 {
+		s += "\n"
+		var names []string
+		for _, guest := range form.guests {
+			names = append(names, fmt.Sprintf("%d", guest.VMID))
+		}
+		s += descStyle.Render(truncate(" Guests: "+strings.Join(names, ", "), m.width)) + "\n"
+	}
+
+	s += "\n" + descStyle.Render(" ↑↓:select option | ←→:change | enter:start backup | esc:cancel")
+	return s
+}
+
+func isBackupTask(upid string) bool {
+	return strings.Contains(upid, ":vzdump:")
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+func TestBackupStorageErrors(t *testing.T) {
+	m := newTestModel(t, testResources(4), 160, 30)
+	old := time.Now().Add(-3 * time.Hour).Unix()
+	fresh := time.Now().Add(-time.Hour).Unix()
+
+	m.updateBackups(backupsMsg{backups: []models.Backup{
+		{Storage: "local", Node: "pve1", VMID: 100, CTime: old},
+		{Storage: "local", Node: "pve2", VMID: 101, CTime: old},
+	}})
+	m.updateBackups(backupsMsg{
+		backups: []models.Backup{{Storage: "local", Node: "pve1", VMID: 100, CTime: fresh}},
+		failed:  []api.StorageError{{Storage: models.Storage{Storage: "local", Node: "pve2"}, Err: errors.New("storage is not online")}},
+	})
+
+	tests := []struct {
+		vmid   int
+		column string
+		detail string
+	}{
+		{100, "1h", "1h ago"},
+		{101, "3h", "3h ago"},
+		{102, "never", "never"},
+		{103, "unknown", "backup status unavailable (local on pve2: storage is not online)"},
+	}
+	for _, tt := range tests {
+		var guest models.Guest
+		for _, g := range m.guests {
+			if g.VMID == tt.vmid {
+				guest = g
+			}
+		}
+		if got := strings.TrimSpace(ansiEscape.ReplaceAllString(m.formatBackupAge(guest), "")); got != tt.column {
+			t.Errorf("guest %d: column %q, want %q", tt.vmid, got, tt.column)
+		}
+		if got := m.backupSummary(guest); got != tt.detail {
+			t.Errorf("guest %d: detail %q, want %q", tt.vmid, got, tt.detail)
+		}
+	}
+}
+
+func TestRecordingHasNoBackupStatus(t *testing.T) {
+	m := newTestModel(t, testResources(2), 160, 30)
+	m.updateBackups(m.fetchBackups()().(backupsMsg))
+
+	guest := m.guests[0]
+	if got := m.backupSummary(guest); got != "backup status unavailable (not part of the recording)" {
+		t.Errorf("detail %q, want the status to be unavailable", got)
+	}
+	if got := ansiEscape.ReplaceAllString(m.formatBackupAge(guest), ""); strings.Contains(got, "never") {
+		t.Errorf("column %q claims the guest was never backed up", got)
+	}
+}
@@ -0,0 +1,851 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	bulkWorkers      = 4
+	bulkPollInterval = time.Second
+	bulkTaskTimeout  = 15 * time.Minute
+)
+
+const (
+	bulkSnapshot = "snapshot"
+	bulkTags     = "tags"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9_][a-z0-9_\-+.]*$`)
+
+type bulkState int
+
+const (
+	bulkQueued bulkState = iota
+	bulkRunning
+	bulkDone
+)
+
+type bulkStep func(ctx context.Context, client api.DataSource, guest models.Guest) (string, error)
+
+type bulkNodeStep func(ctx context.Context, client api.DataSource, node string, guests []models.Guest) (string, error)
+
+type bulkNodeOutcome func(log []string, status *models.TaskStatus, guests []models.Guest) []bulkOutcome
+
+type bulkResult struct {
+	guest   models.Guest
+	state   bulkState
+	err     error
+	warning string
+}
+
+type bulkOutcome struct {
+	err     error
+	warning string
+}
+
+type bulkOperation struct {
+	id       int
+	label    string
+	backup   bool
+	results  []bulkResult
+	started  time.Time
+	finished time.Time
+	updates  chan bulkUpdateMsg
+}
+
+type bulkStartMsg struct {
+	label       string
+	backup      bool
+	guests      []models.Guest
+	step        bulkStep
+	nodeStep    bulkNodeStep
+	nodeOutcome bulkNodeOutcome
+}
+
+type bulkUnit struct {
+	node    string
+	indexes []int
+	timeout time.Duration
+	start   func(ctx context.Context) (string, error)
+	outcome func(upid string, status *models.TaskStatus) []bulkOutcome
+}
+
+type bulkUpdateMsg struct {
+	id      int
+	index   int
+	state   bulkState
+	err     error
+	warning string
+}
+
+type bulkDoneMsg struct {
+	id int
+}
+
+type bulkForm struct {
+	kind    string
+	guests  []models.Guest
+	inputs  []textinput.Model
+	vmstate bool
+	focus   int
+}
+
+func (m Model) markedGuests() []models.Guest {
+	var guests []models.Guest
+	for _, guest := range m.guests {
+		if m.marked[guest.VMID] {
+			guests = append(guests, guest)
+		}
+	}
+	return guests
+}
+
+func (m Model) hasMarks() bool {
+	return m.viewMode == viewGuests && len(m.markedGuests()) > 0
+}
+
+func (m *Model) toggleMark() {
+	if group, ok := m.selectedGroup(); ok {
+		m.toggleGroupMarks(group)
+		return
+	}
+	guest, ok := m.selectedGuest()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return
+	}
+	if m.marked[guest.VMID] {
+		delete(m.marked, guest.VMID)
+	} else {
+		m.marked[guest.VMID] = true
+	}
+	m.moveCursor(1)
+}
+
+func (m *Model) toggleMarkAll() {
+	displayGuests := m.getDisplayGuests()
+	all := len(displayGuests) > 0
+	for _, guest := range displayGuests {
+		if !m.marked[guest.VMID] {
+			all = false
+			break
+		}
+	}
+
+	if all {
+		m.clearMarks()
+		return
+	}
+	for _, guest := range displayGuests {
+		m.marked[guest.VMID] = true
+	}
+	m.setNotice("Marked %d guests", len(m.markedGuests()))
+}
+
+func (m *Model) clearMarks() {
+	m.marked = make(map[int]bool)
+	m.setNotice("Marks cleared")
+}
+
+func (m Model) bulkBusy() bool {
+	return m.bulk != nil && m.bulk.finished.IsZero()
+}
+
+func (m Model) bulkTargets() ([]models.Guest, bool) {
+	if m.hasMarks() {
+		return m.markedGuests(), true
+	}
+	guest, ok := m.selectedGuest()
+	if !ok {
+		return nil, false
+	}
+	return []models.Guest{guest}, true
+}
+
+func countNodes(guests []models.Guest) string {
+	nodes := make(map[string]bool)
+	for _, guest := range guests {
+		nodes[guest.Node] = true
+	}
+	if len(nodes) == 1 {
+		return "1 node"
+	}
+	return fmt.Sprintf("%d nodes", len(nodes))
+}
+
+func pluralGuests(n int) string {
+	if n == 1 {
+		return "1 guest"
+	}
+	return fmt.Sprintf("%d guests", n)
+}
+
+func (m Model) requestBulkAction(action string) (Model, tea.Cmd) {
+	if m.bulkBusy() {
+		m.setNotice("Wait for the running bulk action to finish")
+		return m, nil
+	}
+
+	var targets []models.Guest
+	skipped := make(map[string]int)
+	managed := 0
+	for _, guest := range m.markedGuests() {
+		if reason := guestActionBlocked(guest, action); reason != "" {
+			skipped[reason]++
+			continue
+		}
+		if m.haRestartWarning(guest, action) != "" {
+			managed++
+		}
+		targets = append(targets, guest)
+	}
+	if len(targets) == 0 {
+		m.setNotice("None of the marked guests can be %s", actionPastTense(action))
+		return m, nil
+	}
+
+	label := fmt.Sprintf("%s %s", actionLabels[action], pluralGuests(len(targets)))
+	prompt := fmt.Sprintf("%s on %s", label, countNodes(targets))
+	var reasons []string
+	for reason, n := range skipped {
+		reasons = append(reasons, fmt.Sprintf("%d %s", n, reason))
+	}
+	sort.Strings(reasons)
+	if len(reasons) > 0 {
+		prompt += fmt.Sprintf(", skipping %s", strings.Join(reasons, ", "))
+	}
+	if managed > 0 {
+		prompt += fmt.Sprintf(" (%d HA-managed, HA may start them again)", managed)
+	}
+
+	m.confirm = &confirmDialog{
+		prompt: prompt + "?",
+		onYes: bulkStart(label, targets, func(ctx context.Context, client api.DataSource, guest models.Guest) (string, error) {
+			return client.GuestAction(ctx, guest.Node, guest.Type, guest.VMID, action)
+		}),
+	}
+	return m, nil
+}
+
+func actionPastTense(action string) string {
+	switch action {
+	case api.ActionStart:
+		return "started"
+	case api.ActionShutdown:
+		return "shut down"
+	case api.ActionStop:
+		return "stopped"
+	case api.ActionReboot:
+		return "rebooted"
+	case api.ActionSuspend:
+		return "suspended"
+	default:
+		return "resumed"
+	}
+}
+
+func bulkStart(label string, guests []models.Guest, step bulkStep) tea.Cmd {
+	return func() tea.Msg {
+		return bulkStartMsg{label: label, guests: guests, step: step}
+	}
+}
+
+func (m Model) startBulk(msg bulkStartMsg) (Model, tea.Cmd) {
+	if m.bulkBusy() {
+		m.setNotice("Wait for the running bulk action to finish")
+		return m, nil
+	}
+
+	id := 1
+	if m.bulk != nil {
+		id = m.bulk.id + 1
+	}
+	op := &bulkOperation{
+		id:      id,
+		label:   msg.label,
+		backup:  msg.backup,
+		results: make([]bulkResult, len(msg.guests)),
+		started: time.Now(),
+		updates: make(chan bulkUpdateMsg, 2*len(msg.guests)),
+	}
+	for i, guest := range msg.guests {
+		op.results[i] = bulkResult{guest: guest}
+	}
+	m.bulk = op
+	m.showBulk = true
+
+	go runBulk(m.client, op.id, bulkUnits(m.client, msg), op.updates)
+	return m, waitBulk(op.id, op.updates)
+}
+
+func bulkUnits(client api.DataSource, msg bulkStartMsg) []bulkUnit {
+	if msg.nodeStep == nil {
+		units := make([]bulkUnit, len(msg.guests))
+		for i, guest := range msg.guests {
+			guest := guest
+			units[i] = bulkUnit{node: guest.Node, indexes: []int{i}, timeout: bulkTaskTimeout, start: func(ctx context.Context) (string, error) {
+				return msg.step(ctx, client, guest)
+			}}
+		}
+		return units
+	}
+
+	byNode := make(map[string]int)
+	var units []bulkUnit
+	var guests [][]models.Guest
+	for i, guest := range msg.guests {
+		n, ok := byNode[guest.Node]
+		if !ok {
+			n = len(units)
+			byNode[guest.Node] = n
+			units = append(units, bulkUnit{node: guest.Node})
+			guests = append(guests, nil)
+		}
+		units[n].indexes = append(units[n].indexes, i)
+		guests[n] = append(guests[n], guest)
+	}
+	// A node's task works through all of its guests, so it runs without a
+	// deadline and is polled until it ends.
+	for n := range units {
+		node, group := units[n].node, guests[n]
+		units[n].start = func(ctx context.Context) (string, error) {
+			return msg.nodeStep(ctx, client, node, group)
+		}
+		if msg.nodeOutcome == nil {
+			continue
+		}
+		units[n].outcome = func(upid string, status *models.TaskStatus) []bulkOutcome {
+			ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+			defer cancel()
+
+			log, err := readTaskLog(ctx, client, node, upid)
+			if err != nil {
+				outcome := taskOutcome(status)
+				if outcome.err != nil {
+					outcome.err = fmt.Errorf("task on %s: %w", node, outcome.err)
+				} else if outcome.warning != "" {
+					outcome.warning = fmt.Sprintf("task on %s: %s", node, outcome.warning)
+				}
+				return repeatOutcome(outcome, len(group))
+			}
+			return msg.nodeOutcome(log, status, group)
+		}
+	}
+	return units
+}
+
+func readTaskLog(ctx context.Context, client api.DataSource, node, upid string) ([]string, error) {
+	var log []string
+	start := 0
+	for {
+		lines, err := client.GetTaskLog(ctx, node, upid, start)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 || lines[len(lines)-1].N <= start {
+			return log, nil
+		}
+		for _, line := range lines {
+			log = append(log, line.T)
+		}
+		start = lines[len(lines)-1].N
+	}
+}
+
+func repeatOutcome(outcome bulkOutcome, n int) []bulkOutcome {
+	outcomes := make([]bulkOutcome, n)
+	for i := range outcomes {
+		outcomes[i] = outcome
+	}
+	return outcomes
+}
+
+func taskOutcome(status *models.TaskStatus) bulkOutcome {
+	switch {
+	case status.ExitStatus == "OK":
+		return bulkOutcome{}
+	case strings.HasPrefix(status.ExitStatus, "WARNINGS"):
+		return bulkOutcome{warning: status.ExitStatus}
+	}
+	return bulkOutcome{err: errors.New(status.ExitStatus)}
+}
+
+func runBulk(client api.DataSource, id int, units []bulkUnit, updates chan<- bulkUpdateMsg) {
+	defer close(updates)
+
+	sem := make(chan struct{}, bulkWorkers)
+	var wg sync.WaitGroup
+	for _, unit := range units {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(unit bulkUnit) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for _, i := range unit.indexes {
+				updates <- bulkUpdateMsg{id: id, index: i, state: bulkRunning}
+			}
+			outcomes := runBulkStep(client, unit)
+			for n, i := range unit.indexes {
+				updates <- bulkUpdateMsg{id: id, index: i, state: bulkDone, err: outcomes[n].err, warning: outcomes[n].warning}
+			}
+		}(unit)
+	}
+	wg.Wait()
+}
+
+func runBulkStep(client api.DataSource, unit bulkUnit) []bulkOutcome {
+	upid, status, err := waitBulkTask(client, unit)
+	switch {
+	case err != nil || status == nil:
+		return repeatOutcome(bulkOutcome{err: err}, len(unit.indexes))
+	case unit.outcome != nil:
+		return unit.outcome(upid, status)
+	}
+	return repeatOutcome(taskOutcome(status), len(unit.indexes))
+}
+
+func waitBulkTask(client api.DataSource, unit bulkUnit) (string, *models.TaskStatus, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if unit.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, unit.timeout)
+	}
+	defer cancel()
+
+	upid, err := unit.start(ctx)
+	if err != nil || upid == "" {
+		return upid, nil, err
+	}
+
+	ticker := time.NewTicker(bulkPollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	var failingSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return upid, nil, fmt.Errorf("task status unavailable for %s: %w", unit.timeout, lastErr)
+			}
+			return upid, nil, fmt.Errorf("task did not finish within %s", unit.timeout)
+		case <-ticker.C:
+		}
+		pollCtx, cancelPoll := context.WithTimeout(ctx, actionTimeout)
+		status, err := client.GetTaskStatus(pollCtx, unit.node, upid)
+		cancelPoll()
+		if err != nil {
+			// Connection problems are retried for a while; an answer
+			// from the API (gone, forbidden, ...) will not change.
+			var apiErr *api.Error
+			if errors.As(err, &apiErr) {
+				return upid, nil, err
+			}
+			if lastErr == nil {
+				failingSince = time.Now()
+			}
+			lastErr = err
+			if time.Since(failingSince) >= bulkTaskTimeout {
+				return upid, nil, fmt.Errorf("task status unavailable for %s: %w", bulkTaskTimeout, err)
+			}
+			continue
+		}
+		lastErr = nil
+		if status.Status == "running" {
+			continue
+		}
+		return upid, status, nil
+	}
+}
+
+func waitBulk(id int, updates <-chan bulkUpdateMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return bulkDoneMsg{id: id}
+		}
+		return msg
+	}
+}
+
+func (m Model) updateBulk(msg bulkUpdateMsg) (Model, tea.Cmd) {
+	if m.bulk == nil || m.bulk.id != msg.id {
+		return m, nil
+	}
+	op := *m.bulk
+	op.results = append([]bulkResult(nil), op.results...)
+	op.results[msg.index].state = msg.state
+	op.results[msg.index].err = msg.err
+	op.results[msg.index].warning = msg.warning
+	m.bulk = &op
+	return m, waitBulk(op.id, op.updates)
+}
+
+func (m Model) finishBulk(msg bulkDoneMsg) Model {
+	if m.bulk == nil || m.bulk.id != msg.id {
+		return m
+	}
+	op := *m.bulk
+	op.finished = time.Now()
+	m.bulk = &op
+	if op.backup {
+		m.backupsAt = time.Time{}
+	}
+	return m
+}
+
+func (op *bulkOperation) counts() (done, failed, warned int) {
+	for _, result := range op.results {
+		if result.state == bulkDone {
+			done++
+			if result.err != nil {
+				failed++
+			} else if result.warning != "" {
+				warned++
+			}
+		}
+	}
+	return done, failed, warned
+}
+
+func (op *bulkOperation) summary() (string, lipgloss.Color) {
+	done, failed, warned := op.counts()
+	total := len(op.results)
+	switch {
+	case op.finished.IsZero():
+		text := fmt.Sprintf("⟳ %s: %d/%d done", op.label, done, total)
+		if failed > 0 {
+			text += fmt.Sprintf(", %d failed", failed)
+		}
+		return text, theme.Catppuccin.Yellow
+	case failed == 0 && warned > 0:
+		return fmt.Sprintf("✓ %s: %d with warnings (L:report)", op.label, warned), theme.Catppuccin.Peach
+	case failed == 0:
+		return fmt.Sprintf("✓ %s", op.label), theme.Catppuccin.Green
+	default:
+		return fmt.Sprintf("✗ %s: %d of %d failed (L:report)", op.label, failed, total), theme.Catppuccin.Red
+	}
+}
+
+func (m Model) openBulkForm(kind string) (Model, tea.Cmd) {
+	guests, ok := m.bulkTargets()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return m, nil
+	}
+	if m.bulkBusy() {
+		m.setNotice("Wait for the running bulk action to finish")
+		return m, nil
+	}
+
+	form := &bulkForm{kind: kind, guests: guests}
+	if kind == bulkSnapshot {
+		name := textinput.New()
+		name.Placeholder = "pre_patch_" + time.Now().Format("20060102")
+		name.CharLimit = 40
+		description := textinput.New()
+		description.CharLimit = 200
+		form.inputs = []textinput.Model{name, description}
+	} else {
+		tags := textinput.New()
+		tags.Placeholder = "+patched -staging"
+		tags.CharLimit = 200
+		form.inputs = []textinput.Model{tags}
+	}
+	form.inputs[0].Focus()
+	m.bulkForm = form
+	return m, textinput.Blink
+}
+
+func (f *bulkForm) withRAM() bool {
+	if f.kind != bulkSnapshot {
+		return false
+	}
+	for _, guest := range f.guests {
+		if guest.Type == "qemu" && guest.Status == "running" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Model) updateBulkFormKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	form := *m.bulkForm
+	form.inputs = append([]textinput.Model(nil), form.inputs...)
+	fields := len(form.inputs)
+	if form.withRAM() {
+		fields++
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.bulkForm = nil
+		m.setNotice("Cancelled")
+		return m, nil
+	case "enter":
+		return m.confirmBulkForm()
+	case "tab", "down":
+		form.focus = (form.focus + 1) % fields
+	case "shift+tab", "up":
+		form.focus = (form.focus + fields - 1) % fields
+	case " ":
+		if form.focus == len(form.inputs) {
+			form.vmstate = !form.vmstate
+			m.bulkForm = &form
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	for i := range form.inputs {
+		form.inputs[i].Blur()
+	}
+	if form.focus < len(form.inputs) {
+		form.inputs[form.focus].Focus()
+		form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+	}
+	m.bulkForm = &form
+	return m, cmd
+}
+
+func (m Model) confirmBulkForm() (Model, tea.Cmd) {
+	form := m.bulkForm
+	target := pluralGuests(len(form.guests))
+	if len(form.guests) == 1 {
+		target = guestLabel(form.guests[0])
+	}
+
+	if form.kind == bulkSnapshot {
+		name := strings.TrimSpace(form.inputs[0].Value())
+		if name == "" {
+			m.setNotice("Enter a snapshot name")
+			return m, nil
+		}
+		description := strings.TrimSpace(form.inputs[1].Value())
+		vmstate := form.vmstate
+		label := fmt.Sprintf("Snapshot %s of %s", name, target)
+		prompt := label
+		if vmstate {
+			prompt += " including RAM of running VMs"
+		}
+		m.bulkForm = nil
+		m.confirm = &confirmDialog{
+			prompt: prompt + "?",
+			onYes: bulkStart(label, form.guests, func(ctx context.Context, client api.DataSource, guest models.Guest) (string, error) {
+				return client.CreateSnapshot(ctx, guest.Node, guest.Type, guest.VMID, name, description, vmstate && guest.Status == "running")
+			}),
+		}
+		return m, nil
+	}
+
+	add, remove, err := parseTagChange(form.inputs[0].Value())
+	if err != nil {
+		m.setNotice("%v", err)
+		return m, nil
+	}
+	var changes []string
+	for _, tag := range add {
+		changes = append(changes, "+"+tag)
+	}
+	for _, tag := range remove {
+		changes = append(changes, "-"+tag)
+	}
+	label := fmt.Sprintf("Tag %s %s", target, strings.Join(changes, " "))
+	m.bulkForm = nil
+	m.confirm = &confirmDialog{
+		prompt: label + "?",
+		onYes: bulkStart(label, form.guests, func(ctx context.Context, client api.DataSource, guest models.Guest) (string, error) {
+			cfg, err := client.GetGuestConfig(ctx, guest.Node, guest.Type, guest.VMID)
+			if err != nil {
+				return "", err
+			}
+			tags, changed := applyTagChange(cfg.Tags, add, remove)
+			if !changed {
+				return "", nil
+			}
+			return "", client.SetGuestTags(ctx, guest.Node, guest.Type, guest.VMID, tags, cfg.Raw["digest"])
+		}),
+	}
+	return m, nil
+}
+
+func parseTagChange(input string) (add, remove []string, err error) {
+	for _, token := range api.ParseTags(input) {
+		target := &add
+		switch token[0] {
+		case '-':
+			target, token = &remove, token[1:]
+		case '+':
+			token = token[1:]
+		}
+		token = strings.ToLower(token)
+		if !tagPattern.MatchString(token) {
+			return nil, nil, fmt.Errorf("invalid tag %q", token)
+		}
+		*target = append(*target, token)
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, nil, errors.New("enter tags to add (+tag) or remove (-tag)")
+	}
+	return add, remove, nil
+}
+
+func applyTagChange(current, add, remove []string) ([]string, bool) {
+	drop := make(map[string]bool)
+	for _, tag := range remove {
+		drop[tag] = true
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range append(append([]string(nil), current...), add...) {
+		if drop[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	changed := len(tags) != len(current)
+	for i := 0; !changed && i < len(tags); i++ {
+		changed = tags[i] != current[i]
+	}
+	return tags, changed
+}
+
+func (m Model) viewBulkForm() string {
+	form := m.bulkForm
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(14)
+	focusStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue).Bold(true).Width(14)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	label := func(text string, focus int) string {
+		if form.focus == focus {
+			return focusStyle.Render(text)
+		}
+		return labelStyle.Render(text)
+	}
+
+	target := pluralGuests(len(form.guests))
+	if len(form.guests) == 1 {
+		target = guestLabel(form.guests[0])
+	}
+
+	var s, help string
+	if form.kind == bulkSnapshot {
+		s = titleStyle.Render(truncate(fmt.Sprintf(" pvetop - new snapshot of %s ", target), m.width)) + "\n\n"
+		s += " " + label("Name", 0) + form.inputs[0].View() + "\n"
+		s += " " + label("Description", 1) + form.inputs[1].View() + "\n"
+		help = " tab:next field | enter:create | esc:cancel"
+		if form.withRAM() {
+			check := "[ ]"
+			if form.vmstate {
+				check = "[x]"
+			}
+			s += " " + label("Include RAM", 2) + check + descStyle.Render(" (running VMs only)") + "\n"
+			help = " tab:next field | space:toggle RAM | enter:create | esc:cancel"
+		}
+	} else {
+		s = titleStyle.Render(truncate(fmt.Sprintf(" pvetop - change tags of %s ", target), m.width)) + "\n\n"
+		s += " " + label("Tags", 0) + form.inputs[0].View() + "\n\n"
+		s += descStyle.Render(" +tag or tag adds a tag, -tag removes it; other tags are kept") + "\n"
+		help = " enter:apply | esc:cancel"
+	}
+
+	if len(form.guests) > 1 {
+		var ids []string
+		for _, guest := range form.guests {
+			ids = append(ids, fmt.Sprint(guest.VMID))
+		}
+		s += "\n" + descStyle.Render(truncate(" Guests: "+strings.Join(ids, ", "), m.width)) + "\n"
+	}
+
+	s += "\n" + descStyle.Render(help)
+	return s
+}
+
+func (m Model) viewBulkReport() string {
+	op := m.bulk
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	done, failed, _ := op.counts()
+	status := "running"
+	if !op.finished.IsZero() {
+		status = fmt.Sprintf("finished in %ds", int(op.finished.Sub(op.started).Seconds()))
+	}
+	s := titleStyle.Render(truncate(fmt.Sprintf(" pvetop - %s: %d/%d done, %d failed, %s ", op.label, done, len(op.results), failed, status), m.width)) + "\n\n"
+
+	results := append([]bulkResult(nil), op.results...)
+	rank := func(r bulkResult) int {
+		switch {
+		case r.err != nil:
+			return 0
+		case r.warning != "":
+			return 1
+		case r.state == bulkRunning:
+			return 2
+		case r.state == bulkQueued:
+			return 3
+		default:
+			return 4
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return rank(results[i]) < rank(results[j])
+	})
+
+	lines := m.height - 5
+	for i, result := range results {
+		if i == lines-1 && len(results) > lines {
+			s += descStyle.Render(fmt.Sprintf(" ... and %d more", len(results)-i)) + "\n"
+			break
+		}
+		var text string
+		var color lipgloss.Color
+		switch {
+		case result.err != nil:
+			text, color = "✗ "+result.err.Error(), theme.Catppuccin.Red
+		case result.warning != "":
+			text, color = "⚠ "+result.warning, theme.Catppuccin.Peach
+		case result.state == bulkRunning:
+			text, color = "⟳ running", theme.Catppuccin.Yellow
+		case result.state == bulkQueued:
+			text, color = "· queued", theme.Catppuccin.Overlay0
+		default:
+			text, color = "✓ OK", theme.Catppuccin.Green
+		}
+		line := fmt.Sprintf(" %-32s %-8s ", truncate(guestLabel(result.guest), 32), truncate(result.guest.Node, 8))
+		if remaining := m.width - len([]rune(line)); remaining > 0 {
+			line += lipgloss.NewStyle().Foreground(color).Render(truncate(text, remaining))
+		}
+		s += line + "\n"
+	}
+
+	s += "\n" + descStyle.Render(" Press any key to close; the action keeps running in the background")
+	return s
+}
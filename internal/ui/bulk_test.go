@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type taskSource struct {
+	*api.StaticSource
+	exit     string
+	failures []error
+	logs     map[string][]string
+
+	mu      sync.Mutex
+	backups map[string][]int
+}
+
+func (s *taskSource) StartBackup(ctx context.Context, node string, vmids []int, opts models.BackupOptions) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backups[node] = append(s.backups[node], vmids...)
+	return "UPID:" + node + ":vzdump", nil
+}
+
+func (s *taskSource) GetTaskStatus(ctx context.Context, node, upid string) (*models.TaskStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.failures) > 0 {
+		err := s.failures[0]
+		s.failures = s.failures[1:]
+		return nil, err
+	}
+	return &models.TaskStatus{UPID: upid, Node: node, Status: "stopped", ExitStatus: s.exit}, nil
+}
+
+func (s *taskSource) GetTaskLog(ctx context.Context, node, upid string, start int) ([]models.TaskLogLine, error) {
+	log, ok := s.logs[node]
+	if !ok {
+		return nil, &api.Error{StatusCode: http.StatusInternalServerError, Method: "GET", Path: "/nodes/" + node + "/tasks/log"}
+	}
+	var lines []models.TaskLogLine
+	for i := start; i < len(log); i++ {
+		lines = append(lines, models.TaskLogLine{N: i + 1, T: log[i]})
+	}
+	return lines, nil
+}
+
+func runTestBulk(client api.DataSource, msg bulkStartMsg) []bulkUpdateMsg {
+	updates := make(chan bulkUpdateMsg, 2*len(msg.guests))
+	go runBulk(client, 1, bulkUnits(client, msg), updates)
+
+	done := make([]bulkUpdateMsg, len(msg.guests))
+	for update := range updates {
+		if update.state == bulkDone {
+			done[update.index] = update
+		}
+	}
+	return done
+}
+
+func TestBulkBackupRunsOneTaskPerNode(t *testing.T) {
+	t.Parallel()
+	guests := testResources(5).Guests
+	src := &taskSource{StaticSource: api.NewStaticSource(testResources(5)), exit: "job errors", backups: make(map[string][]int), logs: map[string][]string{
+		"pve1": {
+			"INFO: starting new backup job: vzdump 100 102 104 --storage local",
+			"INFO: Starting Backup of VM 100 (qemu)",
+			"ERROR: Backup of VM 100 failed - unable to open file '/var/lib/vz/dump/vzdump-qemu-100.tmp'",
+			"INFO: Starting Backup of VM 102 (qemu)",
+			"INFO: Finished Backup of VM 102 (00:00:12)",
+			"INFO: Starting Backup of VM 104 (qemu)",
+			"WARN: guest agent not running, skipping fs-freeze",
+			"INFO: Finished Backup of VM 104 (00:00:31)",
+			"INFO: Backup job finished with errors",
+			"TASK ERROR: job errors",
+		},
+	}}
+
+	m := newTestModel(t, testResources(5), 120, 30)
+	m.backup = &backupForm{guests: guests}
+	m.storage = []models.Storage{
+		{Storage: "local", Node: "pve1", Type: "dir", Content: "backup,iso", Enabled: true, Active: true},
+		{Storage: "local", Node: "pve2", Type: "dir", Content: "backup,iso", Enabled: true, Active: true},
+	}
+	m, _ = m.confirmBackup()
+	if m.confirm == nil {
+		t.Fatalf("no confirmation: %s", m.notice)
+	}
+	msg := m.confirm.onYes().(bulkStartMsg)
+
+	for _, unit := range bulkUnits(src, msg) {
+		if unit.timeout != 0 {
+			t.Errorf("the vzdump task on %s has a %s deadline", unit.node, unit.timeout)
+		}
+	}
+
+	done := runTestBulk(src, msg)
+	want := map[string][]int{"pve1": {100, 102, 104}, "pve2": {101, 103}}
+	if !reflect.DeepEqual(src.backups, want) {
+		t.Errorf("vzdump calls = %v, want %v", src.backups, want)
+	}
+
+	results := map[int]string{
+		100: "unable to open file '/var/lib/vz/dump/vzdump-qemu-100.tmp'",
+		102: "",
+		104: "1 warning, see the task log",
+		101: "task on pve2: job errors",
+		103: "task on pve2: job errors",
+	}
+	for i, update := range done {
+		got := update.warning
+		if update.err != nil {
+			got = update.err.Error()
+		}
+		if got != results[guests[i].VMID] {
+			t.Errorf("guest %d: got %q, want %q", guests[i].VMID, got, results[guests[i].VMID])
+		}
+	}
+	if done[4].err != nil {
+		t.Errorf("guest 104 failed although vzdump only warned: %v", done[4].err)
+	}
+}
+
+func TestTaskOutcome(t *testing.T) {
+	tests := []struct {
+		exit    string
+		failed  bool
+		warning string
+	}{
+		{"OK", false, ""},
+		{"WARNINGS: 2", false, "WARNINGS: 2"},
+		{"job errors", true, ""},
+	}
+	for _, tt := range tests {
+		outcome := taskOutcome(&models.TaskStatus{Status: "stopped", ExitStatus: tt.exit})
+		if (outcome.err != nil) != tt.failed || outcome.warning != tt.warning {
+			t.Errorf("%q: got %+v", tt.exit, outcome)
+		}
+	}
+}
+
+func TestBulkTaskStatusErrors(t *testing.T) {
+	gone := &api.Error{StatusCode: http.StatusNotFound, Method: "GET", Path: "/nodes/pve1/tasks/x/status"}
+	tests := []struct {
+		name     string
+		failures []error
+		want     error
+	}{
+		{"transient errors are retried", []error{errors.New("connection reset")}, nil},
+		{"API errors are returned", []error{gone}, gone},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			src := &taskSource{StaticSource: api.NewStaticSource(testResources(1)), exit: "OK", failures: tt.failures}
+			msg := bulkStartMsg{guests: testResources(1).Guests, step: func(ctx context.Context, client api.DataSource, guest models.Guest) (string, error) {
+				return "UPID:" + guest.Node + ":qmstart", nil
+			}}
+
+			done := runTestBulk(src, msg)
+			if !errors.Is(done[0].err, tt.want) {
+				t.Errorf("got %v, want %v", done[0].err, tt.want)
+			}
+		})
+	}
+}
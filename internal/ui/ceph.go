@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const cephDetailLimit = 5
+
+type cephMsg struct {
+	ceph *models.Ceph
+	err  error
+}
+
+type cephHost struct {
+	name  string
+	osds  int
+	up    int
+	in    int
+	used  int64
+	total int64
+}
+
+func (m Model) fetchCeph(ctx context.Context) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		ceph, err := client.GetCeph(ctx)
+		return cephMsg{ceph: ceph, err: err}
+	}
+}
+
+func (m *Model) updateCeph(msg cephMsg) {
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			m.cephErr = msg.err
+			m.cephMissing = api.IsCephUnavailable(msg.err)
+		}
+		return
+	}
+	m.cephErr = nil
+	m.cephMissing = msg.ceph.Status.NumOSDs == 0 && len(msg.ceph.OSDs) == 0
+	m.ceph = msg.ceph
+}
+
+func (m Model) updateCephKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	scroll, handled := m.scrollLines(msg, m.cephScroll, len(m.cephLines()))
+	if !handled {
+		return m, nil, false
+	}
+	m.cephScroll = scroll
+	return m, nil, true
+}
+
+func cephHealthColor(health string) lipgloss.Color {
+	switch health {
+	case "HEALTH_OK":
+		return theme.Catppuccin.Green
+	case "HEALTH_WARN":
+		return theme.Catppuccin.Yellow
+	}
+	return theme.Catppuccin.Red
+}
+
+func cephPGColor(state string) lipgloss.Color {
+	switch {
+	case state == "active+clean":
+		return theme.Catppuccin.Green
+	case !strings.HasPrefix(state, "active"), strings.Contains(state, "incomplete"), strings.Contains(state, "stale"), strings.Contains(state, "down"):
+		return theme.Catppuccin.Red
+	}
+	return theme.Catppuccin.Yellow
+}
+
+func cephHosts(osds []models.CephOSD) []cephHost {
+	byName := make(map[string]*cephHost)
+	var hosts []*cephHost
+	for _, osd := range osds {
+		host, ok := byName[osd.Host]
+		if !ok {
+			host = &cephHost{name: osd.Host}
+			byName[osd.Host] = host
+			hosts = append(hosts, host)
+		}
+		host.osds++
+		if osd.Status == "up" {
+			host.up++
+		}
+		if osd.In {
+			host.in++
+		}
+		host.used += osd.Used
+		host.total += osd.Total
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].name < hosts[j].name
+	})
+	result := make([]cephHost, len(hosts))
+	for i, host := range hosts {
+		result[i] = *host
+	}
+	return result
+}
+
+func (m Model) cephLines() []string {
+	if m.ceph == nil {
+		return nil
+	}
+	st := m.ceph.Status
+
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Mauve)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(16)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Text)
+	greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+	colHeaderStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Subtext1)
+	valueWidth := m.width - 18
+	if valueWidth < 10 {
+		valueWidth = 10
+	}
+
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+sectionStyle.Render(title))
+	}
+	field := func(label, value string) {
+		lines = append(lines, " "+labelStyle.Render(label)+" "+valueStyle.Render(truncate(value, valueWidth)))
+	}
+	row := func(style lipgloss.Style, text string) {
+		if m.width > 3 {
+			lines = append(lines, "   "+style.Render(truncate(text, m.width-3)))
+		}
+	}
+
+	section("Health")
+	lines = append(lines, " "+labelStyle.Render("Status")+" "+lipgloss.NewStyle().Bold(true).Foreground(cephHealthColor(st.Health)).Render(st.Health))
+	for _, check := range st.Checks {
+		text := fmt.Sprintf("[%s] %s: %s", strings.TrimPrefix(check.Severity, "HEALTH_"), check.Name, check.Message)
+		style := lipgloss.NewStyle().Foreground(cephHealthColor(check.Severity))
+		if check.Muted {
+			text += " (muted)"
+			style = greyStyle
+		}
+		row(style, text)
+		for i, detail := range check.Details {
+			if i == cephDetailLimit {
+				row(greyStyle, fmt.Sprintf("  ... and %d more", len(check.Details)-i))
+				break
+			}
+			row(greyStyle, "  "+detail)
+		}
+	}
+	if st.BytesTotal > 0 {
+		field("Raw usage", fmt.Sprintf("%s / %s (%.1f%%)", formatBytesShort(st.BytesUsed), formatBytesShort(st.BytesTotal), float64(st.BytesUsed)/float64(st.BytesTotal)*100))
+	}
+
+	section("OSDs")
+	field("Total", fmt.Sprintf("%d OSDs, %d up, %d in", st.NumOSDs, st.UpOSDs, st.InOSDs))
+	row(colHeaderStyle, fmt.Sprintf("%-12s %5s %5s %5s %21s %6s", "NODE", "OSDS", "UP", "IN", "USED / TOTAL", "USED%"))
+	for _, host := range cephHosts(m.ceph.OSDs) {
+		color := theme.Catppuccin.Text
+		if host.up < host.osds {
+			color = theme.Catppuccin.Red
+		} else if host.in < host.osds {
+			color = theme.Catppuccin.Yellow
+		}
+		usage := "—"
+		if host.total > 0 {
+			usage = fmt.Sprintf("%.1f", float64(host.used)/float64(host.total)*100)
+		}
+		row(lipgloss.NewStyle().Foreground(color), fmt.Sprintf("%-12s %5d %5d %5d %21s %6s",
+			truncate(host.name, 12), host.osds, host.up, host.in,
+			formatBytesShort(host.used)+" / "+formatBytesShort(host.total), usage))
+	}
+	for _, osd := range m.ceph.OSDs {
+		if osd.Status == "up" && osd.In {
+			continue
+		}
+		state := []string{osd.Status}
+		if !osd.In {
+			state = append(state, "out")
+		}
+		row(lipgloss.NewStyle().Foreground(theme.Catppuccin.Red), fmt.Sprintf("%s on %s is %s", osd.Name, osd.Host, strings.Join(state, ", ")))
+	}
+
+	section("Placement groups")
+	field("Total", fmt.Sprintf("%d PGs", st.NumPGs))
+	var backfilling, backfillWait int
+	for _, pg := range st.PGStates {
+		row(lipgloss.NewStyle().Foreground(cephPGColor(pg.State)), fmt.Sprintf("%-40s %6d", truncate(pg.State, 40), pg.Count))
+		if strings.Contains(pg.State, "backfilling") {
+			backfilling += pg.Count
+		}
+		if strings.Contains(pg.State, "backfill_wait") {
+			backfillWait += pg.Count
+		}
+	}
+	if st.DegradedRatio > 0 {
+		field("Degraded", fmt.Sprintf("%.2f%% of objects", st.DegradedRatio*100))
+	}
+	if st.MisplacedRatio > 0 {
+		field("Misplaced", fmt.Sprintf("%.2f%% of objects", st.MisplacedRatio*100))
+	}
+
+	section("I/O")
+	field("Client", fmt.Sprintf("read %s/s, write %s/s, %d op/s read, %d op/s write",
+		formatBytesShort(st.ReadBytesSec), formatBytesShort(st.WriteBytesSec), st.ReadOpsSec, st.WriteOpsSec))
+	if st.RecoveringBytesSec > 0 || st.RecoveringObjectsSec > 0 {
+		field("Recovery", fmt.Sprintf("%s/s, %d objects/s", formatBytesShort(st.RecoveringBytesSec), st.RecoveringObjectsSec))
+	} else {
+		field("Recovery", "idle")
+	}
+	if backfilling > 0 || backfillWait > 0 {
+		field("Backfill", fmt.Sprintf("%d PGs backfilling, %d waiting", backfilling, backfillWait))
+	}
+
+	section("Pools")
+	row(colHeaderStyle, fmt.Sprintf("%-20s %7s %5s %12s %6s %s", "POOL", "SIZE", "PGS", "USED", "USED%", "RULE"))
+	for _, pool := range m.ceph.Pools {
+		usage := pool.PercentUsed * 100
+		color := theme.Catppuccin.Text
+		if usage >= storageCritPercent {
+			color = theme.Catppuccin.Red
+		} else if usage >= storageWarnPercent {
+			color = theme.Catppuccin.Yellow
+		}
+		row(lipgloss.NewStyle().Foreground(color), fmt.Sprintf("%-20s %7s %5d %12s %6.1f %s",
+			truncate(pool.Name, 20), fmt.Sprintf("%d/%d", pool.Size, pool.MinSize), pool.PGs, formatBytesShort(pool.Used), usage, pool.CrushRule))
+	}
+
+	return lines
+}
+
+func (m Model) viewCeph() string {
+	title := " pvetop - ceph - refresh: 2s "
+	if m.ceph != nil {
+		st := m.ceph.Status
+		title = fmt.Sprintf(" pvetop - ceph %s (%d/%d OSDs up, %d PGs) - refresh: 2s ", st.Health, st.UpOSDs, st.NumOSDs, st.NumPGs)
+	}
+
+	lines := m.cephLines()
+	switch {
+	case m.cephMissing:
+		lines = []string{" " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render(truncate("Ceph is not installed or not configured on this cluster.", m.width-1))}
+	case m.cephErr != nil:
+		problem := " " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(queryProblem("ceph", m.cephErr), m.width-1))
+		lines = append([]string{problem, ""}, lines...)
+	case m.ceph == nil:
+		lines = []string{" " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render("loading ceph status...")}
+	}
+
+	help := "q:quit | ?:help | ↑↓/PgUp/PgDn:scroll | tab:next view | n:guests"
+	if m.width < widthMedium {
+		help = "q:quit | tab:next view"
+	}
+	return m.renderLines(title, lines, m.cephScroll, help)
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var brailleDots = [2][4]rune{
+	{0x40, 0x04, 0x02, 0x01},
+	{0x80, 0x20, 0x10, 0x08},
+}
+
+func resample(values []float64, n int) []float64 {
+	if n <= 0 || len(values) == 0 {
+		return nil
+	}
+	if len(values) == n {
+		return values
+	}
+
+	out := make([]float64, n)
+	for i := range out {
+		lo := i * len(values) / n
+		hi := (i + 1) * len(values) / n
+		if hi <= lo {
+			out[i] = values[lo]
+			continue
+		}
+		var sum float64
+		for _, v := range values[lo:hi] {
+			sum += v
+		}
+		out[i] = sum / float64(hi-lo)
+	}
+	return out
+}
+
+func seriesMax(values []float64) float64 {
+	var max float64
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+func scaleLevel(v, max float64, levels int) int {
+	if max <= 0 || v <= 0 {
+		return 0
+	}
+	level := int(math.Round(v / max * float64(levels)))
+	if level > levels {
+		level = levels
+	}
+	return level
+}
+
+func sparkline(values []float64, width int, max float64) string {
+	if len(values) > width {
+		values = resample(values, width)
+	}
+	if max <= 0 {
+		max = seriesMax(values)
+	}
+
+	var b strings.Builder
+	for i := len(values); i < width; i++ {
+		b.WriteRune(' ')
+	}
+	for _, v := range values {
+		level := scaleLevel(v, max, len(sparkBlocks)-1)
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+func brailleChart(values []float64, width, height int, max float64) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	values = resample(values, width*2)
+	if max <= 0 {
+		max = seriesMax(values)
+	}
+
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = make([]rune, width)
+		for col := range grid[row] {
+			grid[row][col] = 0x2800
+		}
+	}
+
+	dots := height * 4
+	for x, v := range values {
+		level := scaleLevel(v, max, dots)
+		if level == 0 && v > 0 {
+			level = 1
+		}
+		for y := 0; y < level; y++ {
+			row := height - 1 - y/4
+			grid[row][x/2] |= brailleDots[x%2][y%4]
+		}
+	}
+
+	lines := make([]string, height)
+	for row := range grid {
+		lines[row] = string(grid[row])
+	}
+	return lines
+}
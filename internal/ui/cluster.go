@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type clusterStatusMsg struct {
+	status *models.ClusterStatus
+	err    error
+}
+
+func (m Model) fetchClusterStatus(ctx context.Context) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		status, err := client.GetClusterStatus(ctx)
+		return clusterStatusMsg{status: status, err: err}
+	}
+}
+
+func (m *Model) updateClusterStatus(msg clusterStatusMsg) {
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			m.clusterErr = msg.err
+		}
+		return
+	}
+	m.clusterErr = nil
+	m.cluster = msg.status
+	m.isCluster = m.detectCluster()
+}
+
+func (m Model) detectCluster() bool {
+	if m.cluster != nil {
+		return m.cluster.Name != ""
+	}
+	return len(m.nodes) > 1
+}
+
+func (m Model) clusterName() string {
+	if m.cluster == nil {
+		return "proxmox"
+	}
+	if m.cluster.Name != "" {
+		return m.cluster.Name
+	}
+	for _, node := range m.cluster.Nodes {
+		if node.Local {
+			return node.Name
+		}
+	}
+	return "proxmox"
+}
+
+func (m Model) renderClusterBanner() string {
+	cluster := m.cluster
+	stale := m.clusterErr != nil
+	if cluster == nil && !stale {
+		return m.renderNodeErrors()
+	}
+
+	// A failed refresh says nothing about quorum, so the last known
+	// state is not shown as if it were current.
+	if !stale && !cluster.Quorate {
+		needed := cluster.ExpectedVotes/2 + 1
+		lostStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Catppuccin.Base).
+			Background(theme.Catppuccin.Red).
+			Width(m.width)
+		if m.width < widthSmall {
+			return lostStyle.Render(truncate(fmt.Sprintf(" NO QUORUM %d/%d votes", cluster.TotalVotes, cluster.ExpectedVotes), m.width))
+		}
+
+		var online, offline []string
+		for _, node := range cluster.Nodes {
+			if node.Online {
+				online = append(online, node.Name)
+			} else {
+				offline = append(offline, node.Name)
+			}
+		}
+		text := fmt.Sprintf(" NO QUORUM - %s has %d/%d votes, %d needed - guests cannot be started, migrated or changed", cluster.Name, cluster.TotalVotes, cluster.ExpectedVotes, needed)
+		if len(offline) > 0 {
+			text += " | offline: " + strings.Join(offline, ", ")
+		}
+		if len(online) > 0 {
+			text += " | online: " + strings.Join(online, ", ")
+		}
+		return lostStyle.Render(truncate(text, m.width))
+	}
+	unknownStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Yellow)
+	if m.width < widthSmall {
+		if stale {
+			return unknownStyle.Render(truncate(" quorum unknown", m.width))
+		}
+		return m.renderNodeErrors()
+	}
+
+	textStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+	var s, plain string
+	add := func(style lipgloss.Style, text string) bool {
+		if len([]rune(plain+text)) > m.width {
+			return false
+		}
+		s += style.Render(text)
+		plain += text
+		return true
+	}
+
+	var nodes []models.ClusterNode
+	switch {
+	case stale:
+		add(textStyle, fmt.Sprintf(" %s: ", m.clusterName()))
+		add(unknownStyle, "quorum unknown, cluster status unavailable")
+		if cluster != nil {
+			nodes = cluster.Nodes
+		}
+	case cluster.Name == "":
+		add(textStyle, " standalone node")
+		nodes = cluster.Nodes
+	default:
+		add(textStyle, fmt.Sprintf(" %s: ", cluster.Name))
+		add(lipgloss.NewStyle().Foreground(theme.Catppuccin.Green), "quorate")
+		add(textStyle, fmt.Sprintf(", %d/%d votes (%d needed)", cluster.TotalVotes, cluster.ExpectedVotes, cluster.ExpectedVotes/2+1))
+		nodes = cluster.Nodes
+	}
+	sep := " |"
+	for _, node := range nodes {
+		dot, color := "●", theme.Catppuccin.Green
+		if !node.Online {
+			dot, color = "○", theme.Catppuccin.Red
+		}
+		if stale {
+			color = theme.Catppuccin.Overlay0
+		}
+		label := " " + node.Name
+		if node.IP != "" {
+			label += " " + node.IP
+		}
+		if node.Local {
+			label += " (local)"
+		}
+		if len([]rune(plain+sep+" "+dot+label)) > m.width {
+			break
+		}
+		add(textStyle, sep)
+		sep = ""
+		add(lipgloss.NewStyle().Foreground(color), " "+dot)
+		add(textStyle, label)
+	}
+
+	if errs := m.renderNodeErrors(); errs != "" {
+		if room := m.width - len([]rune(plain)) - 2; room > 0 {
+			s += textStyle.Render(" |") + lipgloss.NewStyle().Foreground(theme.Catppuccin.Yellow).Render(truncate(ansiPattern.ReplaceAllString(errs, ""), room))
+		}
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestClusterBannerWidths(t *testing.T) {
+	lost := &models.ClusterStatus{
+		Name:          "lab",
+		ExpectedVotes: 3,
+		TotalVotes:    1,
+		Nodes: []models.ClusterNode{
+			{Name: "pve1", Online: true, Votes: 1},
+			{Name: "pve2", Votes: 1},
+			{Name: "pve3", Votes: 1},
+		},
+	}
+	quorate := &models.ClusterStatus{Name: "lab", Quorate: true, ExpectedVotes: 3, TotalVotes: 3, Nodes: lost.Nodes}
+	stale := errors.New("context deadline exceeded")
+
+	tests := []struct {
+		name    string
+		cluster *models.ClusterStatus
+		err     error
+		width   int
+		want    string
+		hidden  string
+	}{
+		{"lost, wide", lost, nil, 120, "NO QUORUM - lab has 1/3 votes, 2 needed", ""},
+		{"lost, medium", lost, nil, widthMedium, "NO QUORUM - lab", ""},
+		{"lost, narrow", lost, nil, widthTiny, "NO QUORUM 1/3 votes", "offline"},
+		{"lost, minimum", lost, nil, minTerminalWidth, "NO QUORUM", ""},
+		{"quorate, wide", quorate, nil, 120, "lab: quorate, 3/3 votes", ""},
+		{"quorate, narrow", quorate, nil, widthTiny, "", "quorate"},
+		{"stale, wide", quorate, stale, 120, "lab: quorum unknown, cluster status unavailable | ● pve1", "quorate"},
+		{"stale, medium", quorate, stale, widthMedium, "lab: quorum unknown", "quorate"},
+		{"stale, narrow", lost, stale, widthTiny, "quorum unknown", "NO QUORUM"},
+		{"stale, never loaded", nil, stale, 120, "quorum unknown", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, testResources(2), tt.width, 30)
+			m.cluster = tt.cluster
+			m.clusterErr = tt.err
+
+			banner := ansiEscape.ReplaceAllString(m.renderClusterBanner(), "")
+			if !strings.Contains(banner, tt.want) {
+				t.Errorf("banner %q does not contain %q", banner, tt.want)
+			}
+			if tt.hidden != "" && strings.Contains(banner, tt.hidden) {
+				t.Errorf("banner %q should not contain %q", banner, tt.hidden)
+			}
+			if len([]rune(banner)) > tt.width {
+				t.Errorf("banner is %d wide, terminal is %d", len([]rune(banner)), tt.width)
+			}
+		})
+	}
+}
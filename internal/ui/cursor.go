@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m Model) pageSize() int {
+	contentHeight := m.height - 5
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+	return contentHeight
+}
+
+func (m Model) rowCount() int {
+	switch m.viewMode {
+	case viewNodes, viewNodeDetail:
+		return len(m.getDisplayNodes())
+	case viewGuests, viewGuestDetail:
+		return len(m.guestRows())
+	}
+	return len(m.rowKeys())
+}
+
+func (m Model) rowKeys() []string {
+	switch m.viewMode {
+	case viewStorage:
+		return m.storageKeys()
+	case viewTasks:
+		return m.taskKeys()
+	case viewSnapshots:
+		return m.snapshotKeys()
+	case viewReplication:
+		return m.replicationKeys()
+	}
+	return nil
+}
+
+func (m *Model) moveCursor(delta int) {
+	row := m.selectedRow + delta
+	if m.selectedRow < 0 {
+		row = 0
+	}
+	m.setCursor(row)
+}
+
+func (m *Model) setCursor(row int) {
+	count := m.rowCount()
+	if count == 0 {
+		m.selectedRow = -1
+		m.scrollOffset = 0
+		return
+	}
+	if row >= count {
+		row = count - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	m.selectedRow = row
+	switch m.viewMode {
+	case viewNodes, viewNodeDetail:
+		m.selectedNode = m.getDisplayNodes()[row].Node
+	case viewGuests, viewGuestDetail:
+		guestRow := m.guestRows()[row]
+		m.selectedKeys[viewGuests] = guestRow.key
+		m.selectedVMID = 0
+		if !guestRow.header {
+			m.selectedVMID = guestRow.guest.VMID
+		}
+	default:
+		m.selectedKeys[m.viewMode] = m.rowKeys()[row]
+	}
+	m.ensureCursorVisible()
+}
+
+func (m *Model) syncCursor() {
+	row := -1
+	switch m.viewMode {
+	case viewNodes, viewNodeDetail:
+		for i, node := range m.getDisplayNodes() {
+			if node.Node == m.selectedNode {
+				row = i
+				break
+			}
+		}
+		if row < 0 && m.selectedNode != "" {
+			row = m.selectedRow
+		}
+	case viewGuests, viewGuestDetail:
+		rows := m.guestRows()
+		selected, ok := m.selectedKeys[viewGuests]
+		for i, guestRow := range rows {
+			if guestRow.key == selected {
+				row = i
+				break
+			}
+		}
+		if row < 0 && m.selectedVMID != 0 {
+			for i, guestRow := range rows {
+				if !guestRow.header && guestRow.guest.VMID == m.selectedVMID {
+					row = i
+					break
+				}
+			}
+		}
+		if row < 0 && ok {
+			row = m.selectedRow
+		}
+	default:
+		selected, ok := m.selectedKeys[m.viewMode]
+		for i, key := range m.rowKeys() {
+			if key == selected {
+				row = i
+				break
+			}
+		}
+		if row < 0 && ok {
+			row = m.selectedRow
+		}
+	}
+
+	if row < 0 {
+		m.selectedRow = -1
+		m.ensureCursorVisible()
+		return
+	}
+	m.setCursor(row)
+}
+
+func (m *Model) ensureCursorVisible() {
+	page := m.pageSize()
+	if m.selectedRow >= 0 {
+		if m.selectedRow < m.scrollOffset {
+			m.scrollOffset = m.selectedRow
+		}
+		if m.selectedRow >= m.scrollOffset+page {
+			m.scrollOffset = m.selectedRow - page + 1
+		}
+	}
+
+	maxScroll := m.rowCount() - page
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.scrollOffset > maxScroll {
+		m.scrollOffset = maxScroll
+	}
+	if m.scrollOffset < 0 {
+		m.scrollOffset = 0
+	}
+}
+
+func (m Model) scrollLines(msg tea.KeyMsg, scroll, lines int) (int, bool) {
+	page := m.height - 5
+	if page < 1 {
+		page = 1
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		scroll--
+	case key.Matches(msg, m.keys.Down):
+		scroll++
+	case key.Matches(msg, m.keys.PageUp):
+		scroll -= page
+	case key.Matches(msg, m.keys.PageDown):
+		scroll += page
+	case key.Matches(msg, m.keys.Top):
+		scroll = 0
+	case key.Matches(msg, m.keys.Bottom):
+		scroll = lines
+	default:
+		return scroll, false
+	}
+
+	if scroll > lines-page {
+		scroll = lines - page
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll, true
+}
@@ -0,0 +1,405 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/metrics"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const chartHeight = 4
+
+type guestDetail struct {
+	guest     models.Guest
+	config    *models.GuestConfig
+	status    *models.GuestStatus
+	err       error
+	scroll    int
+	timeframe string
+	history   []models.RRDPoint
+	historyAt time.Time
+}
+
+type guestDetailMsg struct {
+	vmid   int
+	config *models.GuestConfig
+	status *models.GuestStatus
+	err    error
+}
+
+type detailHistoryMsg struct {
+	vmid      int
+	timeframe string
+	points    []models.RRDPoint
+	err       error
+}
+
+func (m Model) openGuestDetail() (Model, tea.Cmd) {
+	guest, ok := m.selectedGuest()
+	if !ok {
+		return m, nil
+	}
+
+	m.detail = &guestDetail{guest: guest, timeframe: api.TimeframeHour}
+	m.viewMode = viewGuestDetail
+	return m, tea.Batch(
+		m.fetchGuestDetail(context.Background(), guest),
+		m.fetchDetailHistory(guest, api.TimeframeHour),
+	)
+}
+
+func (m Model) fetchDetailHistory(guest models.Guest, timeframe string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		points, err := client.GetGuestRRD(ctx, guest.Node, guest.Type, guest.VMID, timeframe)
+		return detailHistoryMsg{vmid: guest.VMID, timeframe: timeframe, points: points, err: err}
+	}
+}
+
+func (m *Model) updateDetailHistory(msg detailHistoryMsg) {
+	if m.detail == nil || m.detail.guest.VMID != msg.vmid || m.detail.timeframe != msg.timeframe {
+		return
+	}
+	m.detail.historyAt = time.Now()
+	if msg.err == nil {
+		m.detail.history = msg.points
+	}
+}
+
+func (m Model) detailHistoryDue() bool {
+	return m.detail != nil && !m.detail.historyAt.IsZero() && time.Since(m.detail.historyAt) >= historyRefresh
+}
+
+func (m Model) fetchGuestDetail(ctx context.Context, guest models.Guest) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		var status *models.GuestStatus
+		var statusErr error
+		if guest.Type == "lxc" {
+			status, statusErr = client.GetContainerStatus(ctx, guest.Node, guest.VMID)
+		} else {
+			status, statusErr = client.GetVMStatus(ctx, guest.Node, guest.VMID)
+		}
+
+		config, err := client.GetGuestConfig(ctx, guest.Node, guest.Type, guest.VMID)
+		if err == nil {
+			err = statusErr
+		}
+		return guestDetailMsg{vmid: guest.VMID, config: config, status: status, err: err}
+	}
+}
+
+func (m *Model) updateGuestDetail(msg guestDetailMsg) {
+	if m.detail == nil || m.detail.guest.VMID != msg.vmid {
+		return
+	}
+	if errors.Is(msg.err, context.Canceled) {
+		return
+	}
+	m.detail.err = msg.err
+	if msg.config != nil {
+		m.detail.config = msg.config
+	}
+	if msg.status != nil {
+		m.detail.status = msg.status
+	}
+}
+
+func (m *Model) refreshGuestDetail() {
+	if m.detail == nil {
+		return
+	}
+	for _, guest := range m.guests {
+		if guest.VMID == m.detail.guest.VMID {
+			m.detail.guest = guest
+			return
+		}
+	}
+}
+
+func (m Model) updateDetailKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Open):
+		m.viewMode = viewGuests
+		m.detail = nil
+		m.syncCursor()
+		return m, nil, true
+	case key.Matches(msg, m.keys.Timeframe):
+		detail := *m.detail
+		for i, tf := range timeframes {
+			if tf == detail.timeframe {
+				detail.timeframe = timeframes[(i+1)%len(timeframes)]
+				break
+			}
+		}
+		detail.history = nil
+		detail.historyAt = time.Time{}
+		m.detail = &detail
+		return m, m.fetchDetailHistory(detail.guest, detail.timeframe), true
+	}
+
+	scroll, handled := m.scrollLines(msg, m.detail.scroll, len(m.guestDetailLines()))
+	if !handled {
+		return m, nil, false
+	}
+	detail := *m.detail
+	detail.scroll = scroll
+	m.detail = &detail
+	return m, nil, true
+}
+
+func (m Model) guestDetailLines() []string {
+	d := m.detail
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Mauve)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(16)
+	valueStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Text)
+	chartStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue)
+	valueWidth := m.width - 18
+	if valueWidth < 10 {
+		valueWidth = 10
+	}
+
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+sectionStyle.Render(title))
+	}
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		lines = append(lines, " "+labelStyle.Render(label)+" "+valueStyle.Render(truncate(value, valueWidth)))
+	}
+
+	rawStatus := func() []string {
+		if d.status == nil || len(d.status.Raw) == 0 {
+			return nil
+		}
+		raw := []string{"", " " + sectionStyle.Render("Full status")}
+		var keys []string
+		for k := range d.status.Raw {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			raw = append(raw, " "+labelStyle.Render(k)+" "+valueStyle.Render(truncate(formatRawValue(d.status.Raw[k]), valueWidth)))
+		}
+		return raw
+	}
+
+	g := d.guest
+	section("Status")
+	field("Status", g.Status)
+	if d.status != nil {
+		if d.status.QMPStatus != "" && d.status.QMPStatus != g.Status {
+			field("QEMU status", d.status.QMPStatus)
+		}
+		field("Lock", d.status.Lock)
+		if d.status.HA.Managed == 1 {
+			field("HA", "managed ("+d.status.HA.State+")")
+		}
+	}
+	field("Node", g.Node)
+	if g.Status == "running" {
+		field("Uptime", formatUptime(g.Uptime))
+		field("CPU", fmt.Sprintf("%.1f%% of %d vCPU", g.CPU*100, g.CPUs))
+		field("Memory", fmt.Sprintf("%s / %s", formatBytes(g.Mem), formatBytes(g.MaxMem)))
+		if d.status != nil && d.status.FreeMem > 0 {
+			field("Guest free mem", formatBytes(d.status.FreeMem))
+		}
+		if d.status != nil && d.status.MaxSwap > 0 {
+			field("Swap", fmt.Sprintf("%s / %s", formatBytes(d.status.Swap), formatBytes(d.status.MaxSwap)))
+		}
+		field("Disk I/O", fmt.Sprintf("read %s, written %s (%s KiB/s)", formatBytes(g.DiskRead), formatBytes(g.DiskWrite), strings.TrimSpace(m.getDiskRate(g))))
+		field("Network", fmt.Sprintf("in %s, out %s (%s KiB/s)", formatBytes(g.NetIn), formatBytes(g.NetOut), strings.TrimSpace(m.getNetRate(g))))
+	}
+	if g.MaxDisk > 0 {
+		field("Disk usage", fmt.Sprintf("%s / %s", formatBytes(g.Disk), formatBytes(g.MaxDisk)))
+	}
+	field("Last backup", m.backupSummary(g))
+
+	section(fmt.Sprintf("History (%s)", d.timeframe))
+	switch {
+	case len(d.history) > 0:
+		chartWidth := m.width - 4
+		for _, which := range []metric{metricCPU, metricMem, metricDisk, metricNet} {
+			values := metricValues(d.history, which)
+			lines = append(lines, " "+labelStyle.Render(metricLabels[which])+" "+valueStyle.Render(truncate(chartSummary(values, which), valueWidth)))
+			for _, row := range brailleChart(values, chartWidth, chartHeight, metricScale(which)) {
+				lines = append(lines, "  "+chartStyle.Render(row))
+			}
+		}
+	case d.historyAt.IsZero():
+		lines = append(lines, " loading...")
+	default:
+		lines = append(lines, " no data")
+	}
+
+	key := guestHistoryKey(g)
+	if ring := m.samples.Get(key); ring != nil && ring.Len() >= 2 {
+		section(fmt.Sprintf("Last %s (%d samples)", formatRetention(m.samples.Retention()), ring.Len()))
+		for _, which := range []metric{metricCPU, metricMem, metricDisk, metricNet} {
+			stats, _ := m.windowStats(key, which)
+			field(metricLabels[which], statsSummary(stats, which))
+		}
+	}
+
+	if errors.Is(d.err, api.ErrNotRecorded) {
+		section("Configuration")
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render(truncate(queryProblem("configuration", d.err), m.width-2)))
+	} else if d.err != nil {
+		section("Error")
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(d.err.Error(), m.width-2)))
+	}
+	if d.config == nil {
+		if d.err == nil {
+			section("Configuration")
+			lines = append(lines, " loading...")
+		}
+		return append(lines, rawStatus()...)
+	}
+
+	c := d.config
+	section("Hardware")
+	field("CPU type", c.CPUType)
+	field("Topology", fmt.Sprintf("%d socket(s) x %d core(s)", c.Sockets, c.Cores))
+	if c.Memory > 0 {
+		field("Memory", fmt.Sprintf("%d MiB", c.Memory))
+	}
+	switch {
+	case g.Type == "lxc":
+		field("Swap", fmt.Sprintf("%d MiB", c.Swap))
+	case c.Balloon == 0:
+		field("Ballooning", "disabled")
+	case c.Balloon > 0:
+		field("Ballooning", fmt.Sprintf("min %d MiB", c.Balloon))
+	default:
+		field("Ballooning", "enabled")
+	}
+	field("Boot order", c.Boot)
+	field("OS type", c.OSType)
+	if c.OnBoot {
+		field("Start on boot", "yes")
+	}
+
+	if len(c.Disks) > 0 {
+		section("Disks")
+		for _, disk := range c.Disks {
+			value := disk.Volume
+			if disk.Size != "" {
+				value += "  size=" + disk.Size
+			}
+			if disk.Mount != "" {
+				value += "  mp=" + disk.Mount
+			}
+			if disk.Media != "" {
+				value += "  media=" + disk.Media
+			}
+			if disk.Options != "" {
+				value += "  " + disk.Options
+			}
+			field(disk.Key, value)
+		}
+	}
+
+	if len(c.NICs) > 0 {
+		section("Network")
+		for _, nic := range c.NICs {
+			parts := []string{nic.Model}
+			if nic.Name != "" {
+				parts = append(parts, "name="+nic.Name)
+			}
+			parts = append(parts, "mac="+nic.MAC, "bridge="+nic.Bridge)
+			if nic.VLAN != "" {
+				parts = append(parts, "vlan="+nic.VLAN)
+			}
+			if nic.IP != "" {
+				parts = append(parts, "ip="+nic.IP)
+			}
+			if nic.Firewall {
+				parts = append(parts, "firewall")
+			}
+			field(nic.Key, strings.Join(parts, "  "))
+		}
+	}
+
+	if len(c.Tags) > 0 || c.Description != "" {
+		section("Notes")
+		field("Tags", strings.Join(c.Tags, ", "))
+		for i, line := range strings.Split(strings.TrimSpace(c.Description), "\n") {
+			if line == "" && i == 0 {
+				break
+			}
+			label := ""
+			if i == 0 {
+				label = "Description"
+			}
+			lines = append(lines, " "+labelStyle.Render(label)+" "+valueStyle.Render(truncate(line, valueWidth)))
+		}
+	}
+
+	return append(lines, rawStatus()...)
+}
+
+func formatMetric(v float64, which metric) string {
+	if which == metricCPU || which == metricMem {
+		return fmt.Sprintf("%.1f%%", v)
+	}
+	return formatBytes(int64(v)) + "/s"
+}
+
+func statsSummary(stats metrics.Stats, which metric) string {
+	return fmt.Sprintf("min %s  avg %s  max %s  p95 %s",
+		formatMetric(stats.Min, which), formatMetric(stats.Avg, which),
+		formatMetric(stats.Max, which), formatMetric(stats.P95, which))
+}
+
+func chartSummary(values []float64, which metric) string {
+	last := 0.0
+	if len(values) > 0 {
+		last = values[len(values)-1]
+	}
+	return fmt.Sprintf("now %s  peak %s", formatMetric(last, which), formatMetric(seriesMax(values), which))
+}
+
+func formatRawValue(value any) string {
+	switch value.(type) {
+	case map[string]any, []any:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func (m Model) viewGuestDetail() string {
+	var helpText string
+	if m.width >= widthMedium {
+		helpText = "esc:back | ↑↓/PgUp/PgDn:scroll | t:timeframe | S/D/X/R/P/U:power | M:migrate | s:snapshots | b:backup | q:quit"
+	} else {
+		helpText = "esc:back | ↑↓:scroll | q:quit"
+	}
+
+	title := fmt.Sprintf(" pvetop - %s on %s ", guestLabel(m.detail.guest), m.detail.guest.Node)
+	return m.renderLines(title, m.guestDetailLines(), m.detail.scroll, helpText)
+}
@@ -0,0 +1,432 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	filterTermPattern = regexp.MustCompile(`^([a-z]+)(!=|>=|<=|=|>|<|~)(.*)$`)
+	filterNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+var filterFields = map[string]bool{
+	"id": true, "name": true, "node": true, "type": true, "status": true, "tag": true, "pool": true, "cpu": true, "mem": true,
+}
+
+var numericFilterFields = map[string]bool{"id": true, "cpu": true, "mem": true}
+
+type filterTerm struct {
+	field string
+	op    string
+	value string
+	num   float64
+}
+
+type guestFilter struct {
+	text  string
+	terms []filterTerm
+}
+
+type filterPrompt struct {
+	input    textinput.Model
+	name     textinput.Model
+	saving   bool
+	previous *guestFilter
+	err      error
+}
+
+func parseFilter(text string, saved map[string]string) (*guestFilter, error) {
+	f := &guestFilter{text: strings.TrimSpace(text)}
+	for _, token := range strings.Fields(text) {
+		if name, ok := strings.CutPrefix(token, "@"); ok {
+			expr, ok := saved[name]
+			if !ok {
+				return nil, fmt.Errorf("no saved filter named %q", name)
+			}
+			sub, err := parseFilter(expr, nil)
+			if err != nil {
+				return nil, fmt.Errorf("saved filter %q: %w", name, err)
+			}
+			f.terms = append(f.terms, sub.terms...)
+			continue
+		}
+
+		match := filterTermPattern.FindStringSubmatch(strings.ToLower(token))
+		if match == nil {
+			f.terms = append(f.terms, filterTerm{field: "name", op: "~~", value: strings.ToLower(token)})
+			continue
+		}
+
+		term := filterTerm{field: match[1], op: match[2], value: match[3]}
+		if !filterFields[term.field] {
+			return nil, fmt.Errorf("unknown field %q (use id, name, node, type, status, tag, pool, cpu or mem)", term.field)
+		}
+		if term.value == "" {
+			return nil, fmt.Errorf("%s%s needs a value", term.field, term.op)
+		}
+		if numericFilterFields[term.field] {
+			if term.op == "~" {
+				return nil, fmt.Errorf("%s cannot be matched with ~", term.field)
+			}
+			num, err := strconv.ParseFloat(strings.TrimSuffix(term.value, "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s needs a number, not %q", term.field, term.value)
+			}
+			term.num = num
+		} else if term.op != "=" && term.op != "!=" && term.op != "~" {
+			return nil, fmt.Errorf("%s can only be compared with =, != or ~", term.field)
+		} else if term.op != "~" {
+			if _, err := path.Match(term.value, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q in %s%s", term.value, term.field, term.op)
+			}
+		}
+		f.terms = append(f.terms, term)
+	}
+	return f, nil
+}
+
+func (f *guestFilter) hasField(field string) bool {
+	if f == nil {
+		return false
+	}
+	for _, t := range f.terms {
+		if t.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func fuzzyMatch(text, pattern string) bool {
+	text = strings.ToLower(text)
+	for _, r := range pattern {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+func (t filterTerm) matchString(values ...string) bool {
+	found := false
+	for _, value := range values {
+		value = strings.ToLower(value)
+		switch t.op {
+		case "~~":
+			found = fuzzyMatch(value, t.value)
+		case "~":
+			found = strings.Contains(value, t.value)
+		default:
+			found, _ = path.Match(t.value, value)
+		}
+		if found {
+			break
+		}
+	}
+	if t.op == "!=" {
+		return !found
+	}
+	return found
+}
+
+func (t filterTerm) matchNumber(value float64) bool {
+	switch t.op {
+	case "=":
+		return value == t.num
+	case "!=":
+		return value != t.num
+	case ">":
+		return value > t.num
+	case ">=":
+		return value >= t.num
+	case "<":
+		return value < t.num
+	default:
+		return value <= t.num
+	}
+}
+
+func (f *guestFilter) matchGuest(guest models.Guest) bool {
+	for _, t := range f.terms {
+		var ok bool
+		switch t.field {
+		case "id":
+			ok = t.matchNumber(float64(guest.VMID))
+		case "name":
+			ok = t.matchString(guest.Name) || (t.op == "~~" && strings.HasPrefix(strconv.Itoa(guest.VMID), t.value))
+		case "node":
+			ok = t.matchString(guest.Node)
+		case "type":
+			ok = t.matchString(guest.Type)
+		case "status":
+			ok = t.matchString(guest.Status)
+		case "tag":
+			ok = t.matchString(api.ParseTags(guest.Tags)...)
+		case "pool":
+			ok = t.matchString(guest.Pool)
+		case "cpu":
+			ok = t.matchNumber(guest.CPU * 100)
+		case "mem":
+			ok = guest.MaxMem > 0 && t.matchNumber(float64(guest.Mem)/float64(guest.MaxMem)*100)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *guestFilter) matchNode(node models.Node, guests []models.Guest) bool {
+	hosted := &guestFilter{}
+	for _, t := range f.terms {
+		ok := true
+		switch t.field {
+		case "name", "node":
+			ok = t.matchString(node.Node)
+		case "status":
+			ok = t.matchString(node.Status)
+		case "cpu":
+			ok = t.matchNumber(node.CPU * 100)
+		case "mem":
+			ok = node.MaxMem > 0 && t.matchNumber(float64(node.Mem)/float64(node.MaxMem)*100)
+		default:
+			hosted.terms = append(hosted.terms, t)
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(hosted.terms) == 0 {
+		return true
+	}
+
+	// Guest-only fields keep the nodes that host at least one match.
+	for _, guest := range guests {
+		if guest.Node == node.Node && hosted.matchGuest(guest) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Model) getDisplayNodes() []models.Node {
+	if m.filter == nil {
+		return m.nodes
+	}
+	var nodes []models.Node
+	for _, node := range m.nodes {
+		if m.filter.matchNode(node, m.guests) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (m *Model) SetSavedFilters(filters map[string]string, save func(map[string]string) error) {
+	m.savedFilters = filters
+	m.saveFilters = save
+}
+
+func (m Model) filterLabel() string {
+	if m.filter == nil || m.filter.text == "" {
+		return ""
+	}
+	return m.filter.text
+}
+
+func (m Model) filterHeader(shown int) string {
+	if m.filter == nil {
+		return ""
+	}
+	return fmt.Sprintf(" - filter: %s (%d shown)", m.filter.text, shown)
+}
+
+func (m Model) openFilterPrompt() (Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "name or node=pve2 cpu>50 type=lxc tag=prod status!=running mem>80%"
+	input.CharLimit = 200
+	input.SetValue(m.filterLabel())
+	input.CursorEnd()
+	input.Focus()
+
+	m.filterPrompt = &filterPrompt{input: input, previous: m.filter}
+	return m, textinput.Blink
+}
+
+func (m *Model) applyFilter(f *guestFilter) {
+	if f != nil && len(f.terms) == 0 {
+		f = nil
+	}
+	m.filter = f
+	m.scrollOffset = 0
+	m.syncCursor()
+}
+
+func (m Model) updateFilterKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	prompt := *m.filterPrompt
+	if prompt.saving {
+		return m.updateFilterSaveKeys(msg, prompt)
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.applyFilter(prompt.previous)
+		m.filterPrompt = nil
+		return m, nil
+	case "enter":
+		if prompt.err != nil {
+			return m, nil
+		}
+		m.filterPrompt = nil
+		return m, nil
+	case "ctrl+s":
+		switch {
+		case prompt.err != nil:
+			return m, nil
+		case m.filter == nil:
+			prompt.err = errors.New("enter a filter before saving it")
+			m.filterPrompt = &prompt
+			return m, nil
+		case strings.Contains(m.filter.text, "@"):
+			prompt.err = errors.New("saved filters cannot refer to other saved filters")
+			m.filterPrompt = &prompt
+			return m, nil
+		}
+		name := textinput.New()
+		name.Prompt = "save filter as: "
+		name.CharLimit = 40
+		name.Focus()
+		prompt.name = name
+		prompt.saving = true
+		m.filterPrompt = &prompt
+		return m, textinput.Blink
+	}
+
+	var cmd tea.Cmd
+	if msg.String() == "tab" {
+		prompt.input.SetValue(m.completeFilterName(prompt.input.Value()))
+		prompt.input.CursorEnd()
+	} else {
+		prompt.input, cmd = prompt.input.Update(msg)
+	}
+	f, err := parseFilter(prompt.input.Value(), m.savedFilters)
+	prompt.err = err
+	if err == nil {
+		m.applyFilter(f)
+	}
+	m.filterPrompt = &prompt
+	return m, cmd
+}
+
+func (m Model) updateFilterSaveKeys(msg tea.KeyMsg, prompt filterPrompt) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		prompt.saving = false
+		prompt.err = nil
+		m.filterPrompt = &prompt
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(prompt.name.Value())
+		if !filterNamePattern.MatchString(name) {
+			prompt.err = errors.New("filter names may only contain letters, digits, - and _")
+			m.filterPrompt = &prompt
+			return m, nil
+		}
+		saved := make(map[string]string, len(m.savedFilters)+1)
+		for k, v := range m.savedFilters {
+			saved[k] = v
+		}
+		saved[name] = m.filter.text
+		if m.saveFilters != nil {
+			if err := m.saveFilters(saved); err != nil {
+				prompt.err = err
+				m.filterPrompt = &prompt
+				return m, nil
+			}
+		}
+		m.savedFilters = saved
+		m.filterPrompt = nil
+		m.setNotice("Saved filter @%s", name)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	prompt.name, cmd = prompt.name.Update(msg)
+	prompt.err = nil
+	m.filterPrompt = &prompt
+	return m, cmd
+}
+
+func (m Model) savedFilterNames() []string {
+	names := make([]string, 0, len(m.savedFilters))
+	for name := range m.savedFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m Model) completeFilterName(value string) string {
+	i := strings.LastIndex(value, " ") + 1
+	partial, ok := strings.CutPrefix(value[i:], "@")
+	if !ok {
+		return value
+	}
+	for _, name := range m.savedFilterNames() {
+		if strings.HasPrefix(name, partial) {
+			return value[:i] + "@" + name + " "
+		}
+	}
+	return value
+}
+
+func (m Model) renderFilterPrompt() string {
+	prompt := m.filterPrompt
+	if prompt.saving {
+		line := " " + prompt.name.View()
+		if remaining := m.width - lipgloss.Width(line) - 3; remaining > 0 && prompt.err != nil {
+			line += "   " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(prompt.err.Error(), remaining))
+		}
+		return line
+	}
+
+	var hint string
+	var hintColor lipgloss.Color
+	switch {
+	case prompt.err != nil:
+		hint, hintColor = prompt.err.Error(), theme.Catppuccin.Red
+	case m.viewMode == viewNodes:
+		hint, hintColor = fmt.Sprintf("%d/%d nodes", len(m.getDisplayNodes()), len(m.nodes)), theme.Catppuccin.Subtext1
+	default:
+		hint, hintColor = fmt.Sprintf("%d/%d guests", len(m.getDisplayGuests()), len(m.guests)), theme.Catppuccin.Subtext1
+	}
+	if names := m.savedFilterNames(); len(names) > 0 && prompt.err == nil {
+		hint += " | saved: @" + strings.Join(names, " @")
+	}
+
+	line := " " + prompt.input.View()
+	if remaining := m.width - lipgloss.Width(line) - 3; remaining > 0 {
+		line += "   " + lipgloss.NewStyle().Foreground(hintColor).Render(truncate(hint, remaining))
+	}
+	return line
+}
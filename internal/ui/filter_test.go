@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+func TestParseFilterErrors(t *testing.T) {
+	saved := map[string]string{"prod": "tag=prod"}
+	tests := []struct {
+		text string
+		err  string
+	}{
+		{"web node=pve2 cpu>50 mem>80%", ""},
+		{"name=web* tag!=test @prod", ""},
+		{"name=[web", `invalid pattern "[web"`},
+		{"tag!=prod\\", "invalid pattern"},
+		{"color=red", "unknown field"},
+		{"cpu~5", "cannot be matched with ~"},
+		{"mem>lots", "needs a number"},
+		{"node>pve1", "can only be compared"},
+		{"@missing", "no saved filter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := parseFilter(tt.text, saved)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("got %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestFilterNodes(t *testing.T) {
+	res := testResources(4)
+	res.Nodes = append(res.Nodes, models.Node{Node: "pve3", Status: "offline"})
+	res.Guests[1].Type = "lxc"
+	res.Guests[1].Tags = "prod"
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"node=pve*", []string{"pve1", "pve2", "pve3"}},
+		{"status!=online", []string{"pve3"}},
+		{"type=lxc", []string{"pve2"}},
+		{"tag=prod", []string{"pve2"}},
+		{"id=102", []string{"pve1"}},
+		{"node=pve1 type=lxc", nil},
+		{"id>200", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			m := newTestModel(t, res, 120, 30)
+			f, err := parseFilter(tt.text, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.applyFilter(f)
+
+			var got []string
+			for _, node := range m.getDisplayNodes() {
+				got = append(got, node.Node)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	tagsWidth     = 16
+	noPoolGroup   = "(no pool)"
+	untaggedGroup = "(untagged)"
+)
+
+type groupMode int
+
+const (
+	groupNone groupMode = iota
+	groupByNode
+	groupByPool
+	groupByTag
+	groupByType
+)
+
+var groupModeNames = []string{"none", "node", "pool", "tag", "type"}
+
+func (g groupMode) String() string {
+	return groupModeNames[g]
+}
+
+var tagChipColors = []lipgloss.Color{
+	theme.Catppuccin.Blue,
+	theme.Catppuccin.Green,
+	theme.Catppuccin.Yellow,
+	theme.Catppuccin.Peach,
+	theme.Catppuccin.Mauve,
+	theme.Catppuccin.Red,
+}
+
+type guestGroup struct {
+	name   string
+	guests []models.Guest
+}
+
+type guestRow struct {
+	key    string
+	group  *guestGroup
+	header bool
+	guest  models.Guest
+}
+
+func (m Model) guestGroupNames(guest models.Guest) []string {
+	switch m.grouping {
+	case groupByNode:
+		return []string{guest.Node}
+	case groupByPool:
+		if guest.Pool == "" {
+			return []string{noPoolGroup}
+		}
+		return []string{guest.Pool}
+	case groupByTag:
+		tags := api.ParseTags(guest.Tags)
+		if len(tags) == 0 {
+			return []string{untaggedGroup}
+		}
+		return tags
+	case groupByType:
+		if guest.Type == "lxc" {
+			return []string{"containers"}
+		}
+		return []string{"VMs"}
+	}
+	return nil
+}
+
+func (m Model) guestGroups() []*guestGroup {
+	var groups []*guestGroup
+	byName := make(map[string]*guestGroup)
+	for _, guest := range m.getDisplayGuests() {
+		for _, name := range m.guestGroupNames(guest) {
+			group, ok := byName[name]
+			if !ok {
+				group = &guestGroup{name: name}
+				byName[name] = group
+				groups = append(groups, group)
+			}
+			if n := len(group.guests); n == 0 || group.guests[n-1].VMID != guest.VMID {
+				group.guests = append(group.guests, guest)
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		iOther, jOther := strings.HasPrefix(groups[i].name, "("), strings.HasPrefix(groups[j].name, "(")
+		if iOther != jOther {
+			return jOther
+		}
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
+
+func (m Model) groupKey(name string) string {
+	return m.grouping.String() + ":" + name
+}
+
+func (m Model) guestRows() []guestRow {
+	if m.grouping == groupNone {
+		guests := m.getDisplayGuests()
+		rows := make([]guestRow, len(guests))
+		for i, guest := range guests {
+			rows[i] = guestRow{key: strconv.Itoa(guest.VMID), guest: guest}
+		}
+		return rows
+	}
+
+	var rows []guestRow
+	for _, group := range m.guestGroups() {
+		key := m.groupKey(group.name)
+		rows = append(rows, guestRow{key: key, group: group, header: true})
+		if m.collapsed[key] {
+			continue
+		}
+		for _, guest := range group.guests {
+			rows = append(rows, guestRow{key: group.name + "/" + strconv.Itoa(guest.VMID), group: group, guest: guest})
+		}
+	}
+	return rows
+}
+
+func (m Model) selectedGuestRow() (guestRow, bool) {
+	rows := m.guestRows()
+	if m.selectedRow < 0 || m.selectedRow >= len(rows) {
+		return guestRow{}, false
+	}
+	return rows[m.selectedRow], true
+}
+
+func (m Model) selectedGroup() (*guestGroup, bool) {
+	if m.viewMode != viewGuests {
+		return nil, false
+	}
+	row, ok := m.selectedGuestRow()
+	if !ok || !row.header {
+		return nil, false
+	}
+	return row.group, true
+}
+
+func (m *Model) cycleGrouping() {
+	m.grouping = (m.grouping + 1) % groupMode(len(groupModeNames))
+	m.scrollOffset = 0
+	m.syncCursor()
+	if m.grouping == groupNone {
+		m.setNotice("Grouping off")
+		return
+	}
+	m.setNotice("Grouped by %s", m.grouping)
+}
+
+func (m *Model) toggleGroup() {
+	row, ok := m.selectedGuestRow()
+	if !ok || row.group == nil {
+		m.setNotice("Press z to group the guests first")
+		return
+	}
+	key := m.groupKey(row.group.name)
+	if m.collapsed[key] {
+		delete(m.collapsed, key)
+	} else {
+		m.collapsed[key] = true
+	}
+	m.selectedKeys[viewGuests] = key
+	m.syncCursor()
+}
+
+func (m *Model) toggleAllGroups() {
+	if m.grouping == groupNone {
+		m.setNotice("Press z to group the guests first")
+		return
+	}
+	row, selected := m.selectedGuestRow()
+	groups := m.guestGroups()
+	collapse := false
+	for _, group := range groups {
+		if !m.collapsed[m.groupKey(group.name)] {
+			collapse = true
+			break
+		}
+	}
+	for _, group := range groups {
+		if collapse {
+			m.collapsed[m.groupKey(group.name)] = true
+		} else {
+			delete(m.collapsed, m.groupKey(group.name))
+		}
+	}
+	if selected && collapse && row.group != nil {
+		m.selectedKeys[viewGuests] = m.groupKey(row.group.name)
+	}
+	m.syncCursor()
+}
+
+func (m *Model) toggleGroupMarks(group *guestGroup) {
+	all := true
+	for _, guest := range group.guests {
+		if !m.marked[guest.VMID] {
+			all = false
+			break
+		}
+	}
+	for _, guest := range group.guests {
+		if all {
+			delete(m.marked, guest.VMID)
+		} else {
+			m.marked[guest.VMID] = true
+		}
+	}
+	if all {
+		m.setNotice("Unmarked %s", pluralGuests(len(group.guests)))
+		return
+	}
+	m.setNotice("Marked %s in %s", pluralGuests(len(group.guests)), group.name)
+}
+
+func (m Model) hasTags() bool {
+	for _, guest := range m.guests {
+		if guest.Tags != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func tagChipStyle(tag string) lipgloss.Style {
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	color := tagChipColors[h.Sum32()%uint32(len(tagChipColors))]
+	return lipgloss.NewStyle().Foreground(theme.Catppuccin.Base).Background(color)
+}
+
+func renderTagChips(raw string, width int) string {
+	tags := api.ParseTags(raw)
+	moreStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+
+	var s string
+	used := 0
+	for i, tag := range tags {
+		sep := ""
+		if i > 0 {
+			sep = " "
+		}
+		reserve := 0
+		if rest := len(tags) - i - 1; rest > 0 {
+			reserve = len(fmt.Sprintf(" +%d", rest))
+		}
+		chip := " " + tag + " "
+		if used+len(sep)+len([]rune(chip))+reserve <= width {
+			s += sep + tagChipStyle(tag).Render(chip)
+			used += len(sep) + len([]rune(chip))
+			continue
+		}
+
+		shown := i
+		if i == 0 && width-reserve-2 > 0 {
+			chip = " " + truncate(tag, width-reserve-2) + " "
+			s += tagChipStyle(tag).Render(chip)
+			used += len([]rune(chip))
+			shown, sep = 1, " "
+		}
+		if more := fmt.Sprintf("%s+%d", sep, len(tags)-shown); shown < len(tags) && used+len(more) <= width {
+			s += moreStyle.Render(more)
+			used += len(more)
+		}
+		break
+	}
+	if used < width {
+		s += strings.Repeat(" ", width-used)
+	}
+	return s
+}
+
+func (m Model) formatGroupRow(group *guestGroup, visible map[column]bool) string {
+	var running, cpus int
+	var cores float64
+	var mem, maxMem, diskRate, netRate int64
+	nodes := make(map[string]bool)
+	for _, guest := range group.guests {
+		nodes[guest.Node] = true
+		if guest.Status != "running" {
+			continue
+		}
+		running++
+		cpus += guest.CPUs
+		cores += guest.CPU * float64(guest.CPUs)
+		mem += guest.Mem
+		maxMem += guest.MaxMem
+		diskRate += m.getDiskRateNumeric(guest)
+		netRate += m.getNetRateNumeric(guest)
+	}
+
+	arrow := "▾"
+	if m.collapsed[m.groupKey(group.name)] {
+		arrow = "▸"
+	}
+
+	var parts []string
+	for _, col := range guestColumnOrder {
+		if !visible[col] {
+			continue
+		}
+		switch col {
+		case colName:
+			parts = append(parts, fmt.Sprintf("%-20s", truncate(fmt.Sprintf("%s %s (%d)", arrow, group.name, len(group.guests)), 20)))
+		case colStatus:
+			parts = append(parts, fmt.Sprintf("%-8s", truncate(fmt.Sprintf("%d/%d", running, len(group.guests)), 8)))
+		case colCPU:
+			if cpus == 0 {
+				parts = append(parts, fmt.Sprintf("%6s", "—"))
+			} else {
+				parts = append(parts, fmt.Sprintf("%6.1f", cores/float64(cpus)*100))
+			}
+		case colMem:
+			if maxMem == 0 {
+				parts = append(parts, fmt.Sprintf("%6s", "—"))
+			} else {
+				parts = append(parts, fmt.Sprintf("%6.1f", float64(mem)/float64(maxMem)*100))
+			}
+		case colMemGiB:
+			memGiBText := fmt.Sprintf("%5.1f / %-5.1f", float64(mem)/(1024*1024*1024), float64(maxMem)/(1024*1024*1024))
+			parts = append(parts, fmt.Sprintf("%15s", memGiBText))
+		case colDiskIO:
+			parts = append(parts, fmt.Sprintf("%13s", formatBytesPerSec(diskRate)))
+		case colNetIO:
+			parts = append(parts, fmt.Sprintf("%13s", formatBytesPerSec(netRate)))
+		case colNode:
+			nodeText := fmt.Sprintf("%d nodes", len(nodes))
+			if len(nodes) == 1 {
+				for name := range nodes {
+					nodeText = name
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%-8s", truncate(nodeText, 8)))
+		case colID, colType:
+			parts = append(parts, fmt.Sprintf("%-6s", ""))
+		case colBackup:
+			parts = append(parts, fmt.Sprintf("%8s", ""))
+		case colTags:
+			parts = append(parts, fmt.Sprintf("%-*s", tagsWidth, ""))
+		case colHA:
+			parts = append(parts, fmt.Sprintf("%-8s", ""))
+		case colCPUTrend, colMemTrend, colDiskTrend, colNetTrend:
+			parts = append(parts, strings.Repeat(" ", sparkWidth))
+		case colCPUMin, colCPUAvg, colCPUMax, colCPUP95:
+			parts = append(parts, fmt.Sprintf("%6s", ""))
+		}
+	}
+
+	groupStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Mauve).Bold(true)
+	return groupStyle.Render(strings.Join(parts, " "))
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	haRefresh  = 10 * time.Second
+	haStaleLRM = time.Minute
+)
+
+type haMsg struct {
+	status *models.HAStatus
+	err    error
+}
+
+func (m Model) haDue() bool {
+	return !m.haLoading && time.Since(m.haAt) >= haRefresh
+}
+
+func (m Model) fetchHA() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		status, err := client.GetHAStatus(ctx)
+		return haMsg{status: status, err: err}
+	}
+}
+
+func (m *Model) updateHA(msg haMsg) {
+	m.haLoading = false
+	m.haAt = time.Now()
+	if msg.err != nil {
+		m.haErr = msg.err
+		return
+	}
+	m.haErr = nil
+	m.ha = msg.status
+}
+
+func (m Model) haUnused() bool {
+	return m.ha != nil && len(m.ha.Resources) == 0
+}
+
+func (m Model) hasHA() bool {
+	return m.ha != nil && len(m.ha.Resources) > 0
+}
+
+func (m Model) haResource(vmid int) (models.HAResource, bool) {
+	if m.ha == nil {
+		return models.HAResource{}, false
+	}
+	for _, res := range m.ha.Resources {
+		if res.VMID == vmid {
+			return res, true
+		}
+	}
+	return models.HAResource{}, false
+}
+
+func (m Model) haRestartWarning(guest models.Guest, action string) string {
+	if action != api.ActionStop && action != api.ActionShutdown && action != api.ActionSuspend {
+		return ""
+	}
+	if res, ok := m.haResource(guest.VMID); ok && res.Requested == "started" {
+		return "HA-managed, HA may start it again"
+	}
+	return ""
+}
+
+func haStateColor(state string) lipgloss.Color {
+	switch state {
+	case "started", "active":
+		return theme.Catppuccin.Green
+	case "stopped", "disabled", "ignored", "idle":
+		return theme.Catppuccin.Overlay0
+	case "error", "fence":
+		return theme.Catppuccin.Red
+	}
+	return theme.Catppuccin.Yellow
+}
+
+func (m Model) formatHAState(guest models.Guest) string {
+	res, ok := m.haResource(guest.VMID)
+	if !ok {
+		return fmt.Sprintf("%-8s", "")
+	}
+	state := res.State
+	if state == "" {
+		state = res.Requested
+	}
+	return lipgloss.NewStyle().Foreground(haStateColor(state)).Render(fmt.Sprintf("%-8s", truncate(state, 8)))
+}
+
+func formatSince(ts int64) string {
+	if ts == 0 {
+		return "—"
+	}
+	d := time.Since(time.Unix(ts, 0))
+	if d < time.Minute {
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	}
+	return formatAge(d) + " ago"
+}
+
+func (m Model) updateHAKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	scroll, handled := m.scrollLines(msg, m.haScroll, len(m.haLines()))
+	if !handled {
+		return m, nil, false
+	}
+	m.haScroll = scroll
+	return m, nil, true
+}
+
+func (m Model) haLines() []string {
+	if m.ha == nil {
+		return nil
+	}
+	ha := m.ha
+
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Mauve)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(16)
+	colHeaderStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Subtext1)
+
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+sectionStyle.Render(title))
+	}
+	field := func(label, value string, color lipgloss.Color) {
+		lines = append(lines, " "+labelStyle.Render(label)+" "+lipgloss.NewStyle().Foreground(color).Render(value))
+	}
+	row := func(style lipgloss.Style, text string) {
+		if m.width > 3 {
+			lines = append(lines, "   "+style.Render(truncate(text, m.width-3)))
+		}
+	}
+
+	section("Manager")
+	if ha.Quorate {
+		field("Quorum", "OK", theme.Catppuccin.Green)
+	} else {
+		field("Quorum", "no quorum on "+ha.QuorumNode, theme.Catppuccin.Red)
+	}
+	if ha.Master == "" {
+		field("Master", "none", theme.Catppuccin.Red)
+	} else {
+		field("Master", fmt.Sprintf("%s (%s, %s)", ha.Master, ha.MasterStatus, formatSince(ha.MasterTimestamp)), theme.Catppuccin.Text)
+	}
+
+	section("Local resource managers")
+	row(colHeaderStyle, fmt.Sprintf("%-12s %-24s %s", "NODE", "STATE", "LAST SEEN"))
+	for _, lrm := range ha.LRMs {
+		color := haStateColor(lrm.State)
+		if lrm.Timestamp > 0 && time.Since(time.Unix(lrm.Timestamp, 0)) > haStaleLRM || strings.Contains(lrm.State, "dead") {
+			color = theme.Catppuccin.Red
+		}
+		row(lipgloss.NewStyle().Foreground(color), fmt.Sprintf("%-12s %-24s %s", truncate(lrm.Node, 12), truncate(lrm.State, 24), formatSince(lrm.Timestamp)))
+	}
+
+	names := make(map[int]string, len(m.guests))
+	for _, guest := range m.guests {
+		names[guest.VMID] = guest.Name
+	}
+
+	section("Resources")
+	row(colHeaderStyle, fmt.Sprintf("%-10s %-20s %-10s %-10s %-12s %-16s %s", "SID", "NAME", "NODE", "REQUESTED", "CURRENT", "GROUP", "RESTART/RELOCATE"))
+	for _, res := range ha.Resources {
+		current := res.State
+		if current == "" {
+			current = "—"
+		}
+		color := theme.Catppuccin.Text
+		switch {
+		case current == "error" || current == "fence":
+			color = theme.Catppuccin.Red
+		case current != res.Requested:
+			color = theme.Catppuccin.Yellow
+		case res.Requested != "started":
+			color = theme.Catppuccin.Overlay0
+		}
+		row(lipgloss.NewStyle().Foreground(color), fmt.Sprintf("%-10s %-20s %-10s %-10s %-12s %-16s %d/%d",
+			res.SID, truncate(names[res.VMID], 20), truncate(res.Node, 10), res.Requested, truncate(current, 12),
+			truncate(res.Group, 16), res.MaxRestart, res.MaxRelocate))
+	}
+
+	return lines
+}
+
+func (m Model) viewHA() string {
+	title := " pvetop - HA - refresh: 2s "
+	if m.ha != nil {
+		quorum := "quorate"
+		if !m.ha.Quorate {
+			quorum = "NO QUORUM"
+		}
+		title = fmt.Sprintf(" pvetop - HA %s, master %s (%d resources) - refresh: 2s ", quorum, m.ha.Master, len(m.ha.Resources))
+	}
+
+	lines := m.haLines()
+	switch {
+	case m.haErr != nil:
+		problem := " " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(queryProblem("HA", m.haErr), m.width-1))
+		lines = append([]string{problem, ""}, lines...)
+	case m.ha == nil:
+		lines = []string{" " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render("loading HA status...")}
+	case len(m.ha.Resources) == 0:
+		lines = append(lines, "", " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render("No HA resources are configured."))
+	}
+
+	help := "q:quit | ?:help | ↑↓/PgUp/PgDn:scroll | tab:next view | n:guests"
+	if m.width < widthMedium {
+		help = "q:quit | tab:next view"
+	}
+	return m.renderLines(title, lines, m.haScroll, help)
+}
@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	historyRefresh = time.Minute
+	historyTimeout = 10 * time.Second
+	historyWorkers = 4
+	sparkWidth     = 8
+)
+
+type metric int
+
+const (
+	metricCPU metric = iota
+	metricMem
+	metricDisk
+	metricNet
+)
+
+var metricLabels = map[metric]string{
+	metricCPU:  "CPU",
+	metricMem:  "Memory",
+	metricDisk: "Disk I/O",
+	metricNet:  "Network",
+}
+
+var trendMetrics = map[column]metric{
+	colCPUTrend:  metricCPU,
+	colMemTrend:  metricMem,
+	colDiskTrend: metricDisk,
+	colNetTrend:  metricNet,
+}
+
+var nodeTrendMetrics = map[nodeColumn]metric{
+	nodeColCPUTrend:  metricCPU,
+	nodeColMemTrend:  metricMem,
+	nodeColDiskTrend: metricDisk,
+	nodeColNetTrend:  metricNet,
+}
+
+var timeframes = []string{api.TimeframeHour, api.TimeframeDay, api.TimeframeWeek}
+
+type historyMsg struct {
+	series map[string][]models.RRDPoint
+}
+
+func guestHistoryKey(guest models.Guest) string {
+	return fmt.Sprintf("%s/%d", guest.Type, guest.VMID)
+}
+
+func nodeHistoryKey(node string) string {
+	return "node/" + node
+}
+
+func (m Model) historyDue() bool {
+	return !m.historyLoading && time.Since(m.historyAt) >= historyRefresh
+}
+
+func (m Model) fetchHistory() tea.Cmd {
+	client := m.client
+	type target struct {
+		key   string
+		fetch func(ctx context.Context) ([]models.RRDPoint, error)
+	}
+
+	var targets []target
+	for _, node := range m.nodes {
+		if node.Status != "online" || m.hasSamples(nodeHistoryKey(node.Node)) {
+			continue
+		}
+		name := node.Node
+		targets = append(targets, target{nodeHistoryKey(name), func(ctx context.Context) ([]models.RRDPoint, error) {
+			return client.GetNodeRRD(ctx, name, api.TimeframeHour)
+		}})
+	}
+	// Once enough refreshes have been recorded the trends come from the
+	// in-memory samples; RRD only fills in the rows on screen until then.
+	for _, guest := range m.visibleGuests() {
+		if guest.Status != "running" || m.isStale(guest.Node) || m.hasSamples(guestHistoryKey(guest)) {
+			continue
+		}
+		guest := guest
+		targets = append(targets, target{guestHistoryKey(guest), func(ctx context.Context) ([]models.RRDPoint, error) {
+			return client.GetGuestRRD(ctx, guest.Node, guest.Type, guest.VMID, api.TimeframeHour)
+		}})
+	}
+
+	return func() tea.Msg {
+		series := make(map[string][]models.RRDPoint)
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, historyWorkers)
+		for _, t := range targets {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(t target) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+				defer cancel()
+				points, err := t.fetch(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				series[t.key] = points
+				mu.Unlock()
+			}(t)
+		}
+		wg.Wait()
+
+		return historyMsg{series: series}
+	}
+}
+
+func (m Model) visibleGuests() []models.Guest {
+	if m.viewMode != viewGuests {
+		return nil
+	}
+	rows := m.guestRows()
+	start := m.scrollOffset
+	if start > len(rows) {
+		start = len(rows)
+	}
+	end := start + m.pageSize()
+	if end > len(rows) {
+		end = len(rows)
+	}
+	var guests []models.Guest
+	for _, row := range rows[start:end] {
+		if !row.header {
+			guests = append(guests, row.guest)
+		}
+	}
+	return guests
+}
+
+func (m *Model) updateHistory(msg historyMsg) {
+	m.historyLoading = false
+	m.historyAt = time.Now()
+
+	keep := make(map[string]bool)
+	for _, node := range m.nodes {
+		keep[nodeHistoryKey(node.Node)] = true
+	}
+	for _, guest := range m.guests {
+		keep[guestHistoryKey(guest)] = true
+	}
+
+	// Stopped guests and requests that failed this round keep their
+	// previous series instead of losing their sparklines.
+	history := make(map[string][]models.RRDPoint, len(keep))
+	for key, points := range m.history {
+		if keep[key] {
+			history[key] = points
+		}
+	}
+	for key, points := range msg.series {
+		history[key] = points
+	}
+	m.history = history
+}
+
+func metricValues(points []models.RRDPoint, which metric) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		switch which {
+		case metricCPU:
+			values[i] = p.CPU * 100
+		case metricMem:
+			if p.MaxMem > 0 {
+				values[i] = p.Mem / p.MaxMem * 100
+			}
+		case metricDisk:
+			values[i] = p.DiskRead + p.DiskWrite
+		case metricNet:
+			values[i] = p.NetIn + p.NetOut
+		}
+	}
+	return values
+}
+
+func metricScale(which metric) float64 {
+	if which == metricCPU || which == metricMem {
+		return 100
+	}
+	return 0
+}
+
+func (m Model) nodeHistory(node string) []models.RRDPoint {
+	points := m.history[nodeHistoryKey(node)]
+	if len(points) == 0 {
+		return nil
+	}
+
+	disk := make(map[int64]float64)
+	for _, guest := range m.guests {
+		if guest.Node != node {
+			continue
+		}
+		for _, p := range m.history[guestHistoryKey(guest)] {
+			disk[p.Time] += p.DiskRead + p.DiskWrite
+		}
+	}
+
+	merged := make([]models.RRDPoint, len(points))
+	for i, p := range points {
+		p.DiskRead = disk[p.Time]
+		merged[i] = p
+	}
+	return merged
+}
+
+func renderSparkline(values []float64, which metric) string {
+	if len(values) == 0 {
+		return fmt.Sprintf("%-*s", sparkWidth, "")
+	}
+	return sparkline(values, sparkWidth, metricScale(which))
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+type rrdSource struct {
+	*api.StaticSource
+
+	mu     sync.Mutex
+	guests []int
+	nodes  []string
+}
+
+func (s *rrdSource) GetGuestRRD(ctx context.Context, node, guestType string, vmid int, timeframe string) ([]models.RRDPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.guests = append(s.guests, vmid)
+	return []models.RRDPoint{{Time: 1}}, nil
+}
+
+func (s *rrdSource) GetNodeRRD(ctx context.Context, node, timeframe string) ([]models.RRDPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes = append(s.nodes, node)
+	return []models.RRDPoint{{Time: 1}}, nil
+}
+
+func TestUpdateHistoryMerges(t *testing.T) {
+	m := newTestModel(t, testResources(3), 120, 30)
+	old := []models.RRDPoint{{Time: 1, CPU: 0.1}}
+	fresh := []models.RRDPoint{{Time: 2, CPU: 0.2}}
+	m.history = map[string][]models.RRDPoint{
+		"qemu/100":  old,
+		"qemu/101":  old,
+		"qemu/999":  old,
+		"node/pve1": old,
+	}
+
+	m.updateHistory(historyMsg{series: map[string][]models.RRDPoint{
+		"qemu/100": fresh,
+		"qemu/102": fresh,
+	}})
+
+	tests := []struct {
+		key  string
+		want []models.RRDPoint
+	}{
+		{"qemu/100", fresh},
+		{"qemu/101", old},
+		{"qemu/102", fresh},
+		{"node/pve1", old},
+		{"qemu/999", nil},
+	}
+	for _, tt := range tests {
+		got := m.history[tt.key]
+		if len(got) != len(tt.want) || len(got) > 0 && got[0].Time != tt.want[0].Time {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	if m.historyLoading {
+		t.Error("still loading after the update")
+	}
+}
+
+func TestFetchHistoryOnlyVisibleRows(t *testing.T) {
+	res := testResources(40)
+	m := newTestModel(t, res, 120, 15)
+	src := &rrdSource{StaticSource: api.NewStaticSource(res)}
+	m.client = src
+
+	m = pressKeys(m, "pgdown", "pgdown")
+	want := guestIDs(m.visibleGuests())
+	if len(want) != m.pageSize() {
+		t.Fatalf("%d visible guests, want %d", len(want), m.pageSize())
+	}
+	m.fetchHistory()()
+	got := append([]int(nil), src.guests...)
+	sort.Ints(got)
+	sort.Ints(want)
+	if len(got) != len(want) {
+		t.Fatalf("fetched RRD for %v, want only the rows on screen %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("fetched RRD for %v, want only the rows on screen %v", got, want)
+		}
+	}
+	if len(src.nodes) != 2 {
+		t.Errorf("fetched node RRD for %v, want both nodes", src.nodes)
+	}
+
+	now := time.Now()
+	for i := 0; i <= sparkWidth; i++ {
+		m.recordSamples(now.Add(time.Duration(i) * refreshInterval))
+	}
+	src.guests, src.nodes = nil, nil
+	m.fetchHistory()()
+	if len(src.guests) != 0 || len(src.nodes) != 0 {
+		t.Errorf("fetched RRD for guests %v and nodes %v although samples cover them", src.guests, src.nodes)
+	}
+}
@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type migrationPicker struct {
+	guest    models.Guest
+	targets  []string
+	selected int
+	check    *models.MigratePrecondition
+	checkErr error
+	checking bool
+}
+
+type migrateCheckMsg struct {
+	vmid  int
+	check *models.MigratePrecondition
+	err   error
+}
+
+func (m Model) requestMigration() (Model, tea.Cmd) {
+	guest, ok := m.selectedGuest()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return m, nil
+	}
+	if !m.isCluster || len(m.nodes) < 2 {
+		m.setNotice("Migration needs a cluster with at least two nodes")
+		return m, nil
+	}
+
+	picker := &migrationPicker{guest: guest}
+	for _, node := range m.nodes {
+		if node.Node != guest.Node {
+			picker.targets = append(picker.targets, node.Node)
+		}
+	}
+	sort.SliceStable(picker.targets, func(i, j int) bool {
+		return m.nodeFreeMem(picker.targets[i]) > m.nodeFreeMem(picker.targets[j])
+	})
+	m.migrate = picker
+
+	if guest.Type != "qemu" {
+		return m, nil
+	}
+	picker.checking = true
+	return m, m.checkMigration(guest)
+}
+
+func (m Model) checkMigration(guest models.Guest) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		check, err := client.GetMigratePrecondition(ctx, guest.Node, guest.VMID, "")
+		return migrateCheckMsg{vmid: guest.VMID, check: check, err: err}
+	}
+}
+
+func (m *Model) updateMigrationCheck(msg migrateCheckMsg) {
+	if m.migrate == nil || m.migrate.guest.VMID != msg.vmid {
+		return
+	}
+	m.migrate.checking = false
+	m.migrate.check = msg.check
+	m.migrate.checkErr = msg.err
+}
+
+func (m Model) findNode(name string) (models.Node, bool) {
+	for _, node := range m.nodes {
+		if node.Node == name {
+			return node, true
+		}
+	}
+	return models.Node{}, false
+}
+
+func (m Model) nodeFreeMem(name string) int64 {
+	node, ok := m.findNode(name)
+	if !ok || node.Status != "online" {
+		return -1
+	}
+	return node.MaxMem - node.Mem
+}
+
+func (p *migrationPicker) live() bool {
+	return p.guest.Status == "running"
+}
+
+func (p *migrationPicker) mode() string {
+	switch {
+	case !p.live():
+		return "offline"
+	case p.guest.Type == "lxc":
+		return "restart"
+	default:
+		return "online"
+	}
+}
+
+func (p *migrationPicker) blocked() string {
+	if p.check != nil && len(p.check.LocalResources) > 0 {
+		return "uses local resources: " + strings.Join(p.check.LocalResources, ", ")
+	}
+	return ""
+}
+
+func (p *migrationPicker) checkProblem() string {
+	switch {
+	case p.guest.Type != "qemu":
+		return ""
+	case p.checkErr != nil:
+		return fmt.Sprintf("precondition check failed: %v", p.checkErr)
+	case p.check == nil:
+		return "precondition check returned nothing"
+	}
+	return ""
+}
+
+func (m Model) targetProblem(target string) string {
+	node, ok := m.findNode(target)
+	if !ok || node.Status != "online" {
+		return "node offline"
+	}
+	check := m.migrate.check
+	if check == nil {
+		return ""
+	}
+	if reason, ok := check.NotAllowedNodes[target]; ok {
+		return reason
+	}
+	for _, allowed := range check.AllowedNodes {
+		if allowed == target {
+			return ""
+		}
+	}
+	return "not allowed"
+}
+
+func (m Model) updateMigrateKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	picker := *m.migrate
+	switch {
+	case msg.String() == "ctrl+c":
+		m.migrate = nil
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), msg.String() == "q":
+		m.migrate = nil
+		m.setNotice("Cancelled")
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		if picker.selected > 0 {
+			picker.selected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if picker.selected < len(picker.targets)-1 {
+			picker.selected++
+		}
+	case key.Matches(msg, m.keys.Open):
+		return m.confirmMigration(false)
+	case msg.String() == "f":
+		return m.confirmMigration(true)
+	}
+	m.migrate = &picker
+	return m, nil
+}
+
+func (m Model) confirmMigration(force bool) (Model, tea.Cmd) {
+	picker := m.migrate
+	if picker.checking {
+		m.setNotice("Still checking migration preconditions")
+		return m, nil
+	}
+	if reason := picker.blocked(); reason != "" {
+		m.setNotice("%s cannot be migrated: %s", guestLabel(picker.guest), reason)
+		return m, nil
+	}
+	if len(picker.targets) == 0 {
+		return m, nil
+	}
+	target := picker.targets[picker.selected]
+	if problem := m.targetProblem(target); problem != "" {
+		m.setNotice("Cannot migrate to %s: %s", target, problem)
+		return m, nil
+	}
+
+	withLocalDisks := picker.check != nil && len(picker.check.LocalDisks) > 0
+	label := fmt.Sprintf("Migrate %s to %s", guestLabel(picker.guest), target)
+	prompt := fmt.Sprintf("%s (%s)?", label, picker.mode())
+	if problem := picker.checkProblem(); problem != "" {
+		if !force {
+			m.setNotice("Cannot migrate: %s; press f to migrate without it", problem)
+			return m, nil
+		}
+		prompt = fmt.Sprintf("%s (%s) anyway? %s", label, picker.mode(), problem)
+	}
+	m.migrate = nil
+	m.confirm = &confirmDialog{
+		prompt: prompt,
+		onYes:  m.runMigration(picker.guest, target, picker.live(), withLocalDisks, label),
+	}
+	return m, nil
+}
+
+func (m Model) runMigration(guest models.Guest, target string, live, withLocalDisks bool, label string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		upid, err := client.MigrateGuest(ctx, guest.Node, guest.Type, guest.VMID, target, live, withLocalDisks)
+		return actionStartedMsg{node: guest.Node, upid: upid, label: label, tailLog: true, err: err}
+	}
+}
+
+func (m Model) viewMigrate() string {
+	picker := m.migrate
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	colHeaderStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Subtext1)
+	textStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Text)
+	greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+	warnStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Yellow)
+	errStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Red)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	guest := picker.guest
+	s := titleStyle.Render(truncate(fmt.Sprintf(" pvetop - migrate %s from %s ", guestLabel(guest), guest.Node), m.width)) + "\n\n"
+
+	memory := formatBytesShort(guest.MaxMem)
+	if guest.Status == "running" {
+		memory = fmt.Sprintf("%s (%s in use)", memory, formatBytesShort(guest.Mem))
+	}
+	s += textStyle.Render(truncate(fmt.Sprintf(" %s, memory %s, mode: %s", guest.Status, memory, picker.mode()), m.width)) + "\n"
+
+	switch {
+	case picker.checking:
+		s += greyStyle.Render(" checking migration preconditions...") + "\n"
+	case picker.checkProblem() != "":
+		s += errStyle.Render(truncate(" "+picker.checkProblem(), m.width)) + "\n"
+	case picker.blocked() != "":
+		s += errStyle.Render(truncate(" cannot migrate: "+picker.blocked(), m.width)) + "\n"
+	case picker.check != nil && len(picker.check.LocalDisks) > 0:
+		s += warnStyle.Render(truncate(" local disks will be copied: "+strings.Join(picker.check.LocalDisks, ", "), m.width)) + "\n"
+	case picker.check != nil:
+		s += lipgloss.NewStyle().Foreground(theme.Catppuccin.Green).Render(" preconditions OK") + "\n"
+	default:
+		s += "\n"
+	}
+
+	s += "\n" + colHeaderStyle.Render(truncate(fmt.Sprintf(" %-12s %-8s %20s %6s  %s", "TARGET", "STATUS", "FREE / TOTAL MEM", "CPU%", "NOTE"), m.width)) + "\n"
+	for i, target := range picker.targets {
+		node, _ := m.findNode(target)
+		free := m.nodeFreeMem(target)
+		memText, cpuText := "—", "—"
+		if free >= 0 {
+			memText = formatBytesShort(free) + " / " + formatBytesShort(node.MaxMem)
+			cpuText = fmt.Sprintf("%.1f", node.CPU*100)
+		}
+
+		note, style := "", textStyle
+		if problem := m.targetProblem(target); problem != "" {
+			note, style = problem, errStyle
+		} else if free < guest.MaxMem {
+			note, style = "less free memory than the guest's maximum", warnStyle
+		}
+
+		row := fmt.Sprintf(" %-12s %-8s %20s %6s  ", truncate(target, 12), node.Status, memText, cpuText)
+		if remaining := m.width - len([]rune(row)); remaining > 0 {
+			row += style.Render(truncate(note, remaining))
+		}
+		if i == picker.selected {
+			s += highlightRow(row, m.width) + "\n"
+		} else {
+			s += row + "\n"
+		}
+	}
+
+	help := " ↑↓:select target | enter:migrate | esc:cancel"
+	if !picker.checking && picker.checkProblem() != "" {
+		help = " ↑↓:select target | f:migrate without check | esc:cancel"
+	}
+	s += "\n" + descStyle.Render(help)
+	return s
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type precheckSource struct {
+	*api.StaticSource
+	check *models.MigratePrecondition
+	err   error
+}
+
+func (s precheckSource) GetMigratePrecondition(ctx context.Context, node string, vmid int, target string) (*models.MigratePrecondition, error) {
+	return s.check, s.err
+}
+
+func TestMigrationNeedsPreconditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   *models.MigratePrecondition
+		err     error
+		blocked bool
+	}{
+		{"check passed", &models.MigratePrecondition{Running: true, AllowedNodes: []string{"pve1", "pve2"}}, nil, false},
+		{"check failed", nil, errors.New("connection reset"), true},
+		{"check returned nothing", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := precheckSource{api.NewStaticSource(testResources(4)), tt.check, tt.err}
+			m := NewModel(src)
+			var tm tea.Model = m
+			tm, _ = tm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+			tm, _ = tm.Update(m.fetchData(context.Background())())
+			m = pressKeys(tm.(Model), "v", "down")
+
+			m, cmd := m.requestMigration()
+			if cmd == nil {
+				t.Fatal("no precondition check was started")
+			}
+			tm, _ = m.Update(cmd())
+			m = tm.(Model)
+
+			confirmed := pressKeys(m, "enter")
+			if blocked := confirmed.confirm == nil; blocked != tt.blocked {
+				t.Fatalf("enter blocked = %v, want %v", blocked, tt.blocked)
+			}
+			if !tt.blocked {
+				return
+			}
+
+			forced := pressKeys(m, "f")
+			if forced.confirm == nil {
+				t.Fatal("f did not ask for confirmation")
+			}
+			if want := m.migrate.checkProblem(); !strings.Contains(forced.confirm.prompt, want) {
+				t.Errorf("prompt %q does not name %q", forced.confirm.prompt, want)
+			}
+		})
+	}
+}
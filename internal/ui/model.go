@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/metrics"
 	"github.com/berocorpdotnet/pvetop/internal/models"
 	"github.com/berocorpdotnet/pvetop/internal/theme"
 )
@@ -33,6 +36,16 @@ type viewMode int
 const (
 	viewGuests viewMode = iota
 	viewNodes
+	viewGuestDetail
+	viewStorage
+	viewTasks
+	viewTaskLog
+	viewSnapshots
+	viewCeph
+	viewHA
+	viewReplication
+	viewReplicationLog
+	viewNodeDetail
 )
 
 type column int
@@ -48,8 +61,21 @@ const (
 	colDiskIO
 	colNetIO
 	colNode
+	colCPUTrend
+	colMemTrend
+	colDiskTrend
+	colNetTrend
+	colCPUMin
+	colCPUAvg
+	colCPUMax
+	colCPUP95
+	colBackup
+	colTags
+	colHA
 )
 
+var guestColumnOrder = []column{colID, colName, colType, colStatus, colHA, colCPU, colCPUMin, colCPUAvg, colCPUMax, colCPUP95, colCPUTrend, colMem, colMemTrend, colMemGiB, colDiskIO, colDiskTrend, colNetIO, colNetTrend, colBackup, colTags, colNode}
+
 type nodeColumn int
 
 const (
@@ -62,27 +88,90 @@ const (
 	nodeColNetIO
 	nodeColVMs
 	nodeColCTs
+	nodeColCPUTrend
+	nodeColMemTrend
+	nodeColDiskTrend
+	nodeColNetTrend
+	nodeColCPUMin
+	nodeColCPUAvg
+	nodeColCPUMax
+	nodeColCPUP95
 )
 
 type Model struct {
-	client       *api.Client
-	guests       []models.Guest
-	nodes        []models.Node
-	prevGuests   []models.Guest
-	prevGuestMap map[int]models.Guest
-	sortBy       sortColumn
-	sortReverse  bool
-	showAll      bool
-	viewMode     viewMode
-	isCluster    bool
-	err          error
-	width        int
-	height       int
-	keys         keyMap
-	lastUpdate   time.Time
-	lastFetch    time.Time
-	scrollOffset int
-	selectedRow  int
+	client         api.DataSource
+	guests         []models.Guest
+	nodes          []models.Node
+	nodeErrors     map[string]error
+	sortBy         sortColumn
+	sortReverse    bool
+	showAll        bool
+	viewMode       viewMode
+	isCluster      bool
+	err            error
+	width          int
+	height         int
+	keys           keyMap
+	lastUpdate     time.Time
+	scrollOffset   int
+	selectedRow    int
+	selectedVMID   int
+	selectedNode   string
+	cancelFetch    context.CancelFunc
+	confirm        *confirmDialog
+	tasks          []trackedTask
+	notice         string
+	noticeAt       time.Time
+	showHelp       bool
+	detail         *guestDetail
+	history        map[string][]models.RRDPoint
+	historyAt      time.Time
+	historyLoading bool
+	samples        *metrics.History
+	showStats      bool
+	selectedKeys   map[viewMode]string
+	storage        []models.Storage
+	storageErr     error
+	ceph           *models.Ceph
+	cephErr        error
+	cephScroll     int
+	cephMissing    bool
+	cluster        *models.ClusterStatus
+	clusterErr     error
+	ha             *models.HAStatus
+	haErr          error
+	haAt           time.Time
+	haLoading      bool
+	haScroll       int
+	replJobs       []models.ReplicationJob
+	replErr        error
+	replNodeErrors map[string]error
+	replAt         time.Time
+	replLoading    bool
+	replLog        *replicationLog
+	nodeDetail     *nodeDetail
+	taskList       []models.TaskStatus
+	taskListErr    error
+	taskLog        *taskLog
+	migrate        *migrationPicker
+	snapshots      *snapshotPanel
+	lastBackups    map[int]int64
+	backupsErr     error
+	backupsFailed  []api.StorageError
+	backupsAt      time.Time
+	backupsLoading bool
+	backupMaxAge   time.Duration
+	backup         *backupForm
+	marked         map[int]bool
+	bulk           *bulkOperation
+	bulkForm       *bulkForm
+	showBulk       bool
+	filter         *guestFilter
+	filterPrompt   *filterPrompt
+	savedFilters   map[string]string
+	saveFilters    func(map[string]string) error
+	grouping       groupMode
+	collapsed      map[string]bool
 }
 
 type keyMap struct {
@@ -98,9 +187,47 @@ type keyMap struct {
 	ToggleView key.Binding
 	Up         key.Binding
 	Down       key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Top        key.Binding
+	Bottom     key.Binding
+	Start      key.Binding
+	Shutdown   key.Binding
+	Stop       key.Binding
+	Reboot     key.Binding
+	Suspend    key.Binding
+	Resume     key.Binding
+	Migrate    key.Binding
+	Snapshots  key.Binding
+	Backup     key.Binding
+	Mark       key.Binding
+	MarkAll    key.Binding
+	Tags       key.Binding
+	Report     key.Binding
+	Filter     key.Binding
+	Group      key.Binding
+	Fold       key.Binding
+	FoldAll    key.Binding
+	Open       key.Binding
+	Back       key.Binding
+	Timeframe  key.Binding
+	Stats      key.Binding
+	NextView   key.Binding
+	PrevView   key.Binding
+}
+
+func (k keyMap) helpBindings() []key.Binding {
+	return []key.Binding{
+		k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom, k.Open, k.Back, k.Timeframe,
+		k.SortVMID, k.SortCPU, k.SortMem, k.SortDiskIO, k.SortNetIO, k.Reverse,
+		k.Filter, k.Group, k.Fold, k.FoldAll, k.ToggleAll, k.ToggleView, k.NextView, k.PrevView, k.Stats,
+		k.Start, k.Shutdown, k.Stop, k.Reboot, k.Suspend, k.Resume, k.Migrate, k.Snapshots, k.Backup,
+		k.Mark, k.MarkAll, k.Tags, k.Report,
+		k.Help, k.Quit,
+	}
 }
 
-func NewModel(client *api.Client) Model {
+func NewModel(client api.DataSource) Model {
 	return Model{
 		client:       client,
 		sortBy:       sortByCPU,
@@ -109,6 +236,11 @@ func NewModel(client *api.Client) Model {
 		viewMode:     viewGuests,
 		selectedRow:  -1, 
 		scrollOffset: 0,
+		samples:      metrics.NewHistory(defaultRetention, refreshInterval),
+		selectedKeys: make(map[viewMode]string),
+		backupMaxAge: defaultBackupMaxAge,
+		marked:       make(map[int]bool),
+		collapsed:    make(map[string]bool),
 		keys: keyMap{
 			Quit:       key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
 			Help:       key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
@@ -120,8 +252,35 @@ func NewModel(client *api.Client) Model {
 			Reverse:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reverse sort")),
 			ToggleAll:  key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle all/active")),
 			ToggleView: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "toggle nodes/guests")),
-			Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "scroll up")),
-			Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "scroll down")),
+			Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "select previous")),
+			Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "select next")),
+			PageUp:     key.NewBinding(key.WithKeys("pgup"), key.WithHelp("PgUp", "page up")),
+			PageDown:   key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("PgDn", "page down")),
+			Top:        key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("Home/g", "first row")),
+			Bottom:     key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("End/G", "last row")),
+			Start:      key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "start guest")),
+			Shutdown:   key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "shut down guest")),
+			Stop:       key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "stop guest (hard)")),
+			Reboot:     key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "reboot guest")),
+			Suspend:    key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "suspend guest")),
+			Resume:     key.NewBinding(key.WithKeys("U"), key.WithHelp("U", "resume guest")),
+			Migrate:    key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "migrate guest to another node")),
+			Snapshots:  key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "guest snapshots (c:create r:rollback d:delete)")),
+			Backup:     key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "back up guest now (vzdump)")),
+			Mark:       key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark/unmark guest for bulk actions")),
+			MarkAll:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "mark all shown guests / clear marks")),
+			Tags:       key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "add/remove tags")),
+			Report:     key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "last bulk action report")),
+			Filter:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search/filter (node=pve2 cpu>50 tag=prod @saved)")),
+			Group:      key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "group guests by node/pool/tag/type/none")),
+			Fold:       key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "collapse/expand the current group")),
+			FoldAll:    key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "collapse/expand all groups")),
+			Open:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open guest or node details, task or replication log, or group")),
+			Back:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to list")),
+			NextView:   key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next view (guests/nodes/storage/ceph/HA/replication/tasks)")),
+			PrevView:   key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous view")),
+			Stats:      key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "toggle CPU min/avg/max/p95 columns")),
+			Timeframe:  key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "cycle history timeframe (detail)")),
 		},
 	}
 }
@@ -137,7 +296,7 @@ func (m Model) Init() tea.Cmd {
 type tickMsg time.Time
 
 func tick() tea.Cmd {
-	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -163,12 +322,52 @@ func (m Model) getVisibleColumns() map[column]bool {
 		{colNetIO, 14},    
 		{colDiskIO, 14},   
 	}
+	if m.hasTrends() {
+		columns = append(columns, []struct {
+			col   column
+			width int
+		}{
+			{colCPUTrend, sparkWidth + 1},
+			{colMemTrend, sparkWidth + 1},
+			{colDiskTrend, sparkWidth + 1},
+			{colNetTrend, sparkWidth + 1},
+		}...)
+	}
+	if m.showStats {
+		columns = append(columns, []struct {
+			col   column
+			width int
+		}{
+			{colCPUMin, 7},
+			{colCPUAvg, 7},
+			{colCPUMax, 7},
+			{colCPUP95, 7},
+		}...)
+	}
+	if m.lastBackups != nil {
+		columns = append(columns, struct {
+			col   column
+			width int
+		}{colBackup, 9})
+	}
+	if m.hasTags() {
+		columns = append(columns, struct {
+			col   column
+			width int
+		}{colTags, tagsWidth + 1})
+	}
+	if m.hasHA() {
+		columns = append(columns, struct {
+			col   column
+			width int
+		}{colHA, 9})
+	}
 	
 	for _, col := range columns {
 		totalWidth += col.width
 	}
 	
-	sacrificeOrder := []column{colDiskIO, colNetIO, colMemGiB, colID, colStatus, colType, colMem, colCPU}
+	sacrificeOrder := []column{colNetTrend, colDiskTrend, colMemTrend, colCPUTrend, colCPUMin, colCPUMax, colCPUAvg, colCPUP95, colBackup, colTags, colDiskIO, colNetIO, colHA, colMemGiB, colID, colStatus, colType, colMem, colCPU}
 	
 	for _, col := range sacrificeOrder {
 		if totalWidth <= m.width {
@@ -195,9 +394,7 @@ func (m Model) getVisibleColumns() map[column]bool {
 func (m Model) formatHeaders(visible map[column]bool) string {
 	var parts []string
 	
-	columnOrder := []column{colID, colName, colType, colStatus, colCPU, colMem, colMemGiB, colDiskIO, colNetIO, colNode}
-	
-	for _, col := range columnOrder {
+	for _, col := range guestColumnOrder {
 		if !visible[col] {
 			continue
 		}
@@ -222,6 +419,28 @@ func (m Model) formatHeaders(visible map[column]bool) string {
 			parts = append(parts, fmt.Sprintf("%13s", "NET(KiB/s)"))
 		case colNode:
 			parts = append(parts, fmt.Sprintf("%-8s", "NODE"))
+		case colBackup:
+			parts = append(parts, fmt.Sprintf("%8s", "LAST BKP"))
+		case colTags:
+			parts = append(parts, fmt.Sprintf("%-*s", tagsWidth, "TAGS"))
+		case colHA:
+			parts = append(parts, fmt.Sprintf("%-8s", "HA"))
+		case colCPUTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "CPU HIST"))
+		case colMemTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "MEM HIST"))
+		case colDiskTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "DSK HIST"))
+		case colNetTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "NET HIST"))
+		case colCPUMin:
+			parts = append(parts, fmt.Sprintf("%6s", "MIN%"))
+		case colCPUAvg:
+			parts = append(parts, fmt.Sprintf("%6s", "AVG%"))
+		case colCPUMax:
+			parts = append(parts, fmt.Sprintf("%6s", "MAX%"))
+		case colCPUP95:
+			parts = append(parts, fmt.Sprintf("%6s", "P95%"))
 		}
 	}
 	
@@ -231,9 +450,7 @@ func (m Model) formatHeaders(visible map[column]bool) string {
 func (m Model) formatGuestRow(guest models.Guest, visible map[column]bool) string {
 	var parts []string
 	
-	columnOrder := []column{colID, colName, colType, colStatus, colCPU, colMem, colMemGiB, colDiskIO, colNetIO, colNode}
-	
-	for _, col := range columnOrder {
+	for _, col := range guestColumnOrder {
 		if !visible[col] {
 			continue
 		}
@@ -241,7 +458,10 @@ func (m Model) formatGuestRow(guest models.Guest, visible map[column]bool) strin
 		case colID:
 			parts = append(parts, fmt.Sprintf("%-6d", guest.VMID))
 		case colName:
-			if guest.Status != "running" {
+			if m.marked[guest.VMID] {
+				markStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Mauve).Bold(true)
+				parts = append(parts, markStyle.Render(fmt.Sprintf("%-20s", "● "+truncate(guest.Name, 18))))
+			} else if guest.Status != "running" {
 				greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
 				parts = append(parts, greyStyle.Render(fmt.Sprintf("%-20s", truncate(guest.Name, 20))))
 			} else {
@@ -325,12 +545,36 @@ func (m Model) formatGuestRow(guest models.Guest, visible map[column]bool) strin
 				parts = append(parts, fmt.Sprintf("%13s", netRate))
 			}
 		case colNode:
-			if guest.Status != "running" {
+			if m.isStale(guest.Node) {
+				staleStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Yellow)
+				parts = append(parts, staleStyle.Render(fmt.Sprintf("%-8s", truncate(guest.Node, 7)+"?")))
+			} else if guest.Status != "running" {
 				greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
 				parts = append(parts, greyStyle.Render(fmt.Sprintf("%-8s", guest.Node)))
 			} else {
 				parts = append(parts, fmt.Sprintf("%-8s", guest.Node))
 			}
+		case colBackup:
+			parts = append(parts, m.formatBackupAge(guest))
+		case colTags:
+			parts = append(parts, renderTagChips(guest.Tags, tagsWidth))
+		case colHA:
+			parts = append(parts, m.formatHAState(guest))
+		case colCPUTrend, colMemTrend, colDiskTrend, colNetTrend:
+			trendColor := theme.Catppuccin.Blue
+			if guest.Status != "running" {
+				trendColor = theme.Catppuccin.Overlay0
+			}
+			trendStyle := lipgloss.NewStyle().Foreground(trendColor)
+			parts = append(parts, trendStyle.Render(renderSparkline(m.guestTrend(guest, trendMetrics[col]), trendMetrics[col])))
+		case colCPUMin, colCPUAvg, colCPUMax, colCPUP95:
+			stats, ok := m.windowStats(guestHistoryKey(guest), metricCPU)
+			if !ok || guest.Status != "running" {
+				greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+				parts = append(parts, greyStyle.Render(fmt.Sprintf("%6s", "—")))
+			} else {
+				parts = append(parts, fmt.Sprintf("%6.1f", statsValue(stats, statsColumns[col])))
+			}
 		}
 	}
 	
@@ -357,12 +601,34 @@ func (m Model) getVisibleNodeColumns() map[nodeColumn]bool {
 		{nodeColVMs, 7},       
 		{nodeColCTs, 7},       
 	}
+	if m.hasTrends() {
+		columns = append(columns, []struct {
+			col   nodeColumn
+			width int
+		}{
+			{nodeColCPUTrend, sparkWidth + 1},
+			{nodeColMemTrend, sparkWidth + 1},
+			{nodeColDiskTrend, sparkWidth + 1},
+			{nodeColNetTrend, sparkWidth + 1},
+		}...)
+	}
+	if m.showStats {
+		columns = append(columns, []struct {
+			col   nodeColumn
+			width int
+		}{
+			{nodeColCPUMin, 7},
+			{nodeColCPUAvg, 7},
+			{nodeColCPUMax, 7},
+			{nodeColCPUP95, 7},
+		}...)
+	}
 	
 	for _, col := range columns {
 		totalWidth += col.width
 	}
 	
-	sacrificeOrder := []nodeColumn{nodeColDiskIO, nodeColNetIO, nodeColMemGiB, nodeColVMs, nodeColCTs, nodeColStatus, nodeColMem, nodeColCPU}
+	sacrificeOrder := []nodeColumn{nodeColNetTrend, nodeColDiskTrend, nodeColMemTrend, nodeColCPUTrend, nodeColCPUMin, nodeColCPUMax, nodeColCPUAvg, nodeColCPUP95, nodeColDiskIO, nodeColNetIO, nodeColMemGiB, nodeColVMs, nodeColCTs, nodeColStatus, nodeColMem, nodeColCPU}
 	
 	for _, col := range sacrificeOrder {
 		if totalWidth <= m.width {
@@ -389,7 +655,7 @@ func (m Model) getVisibleNodeColumns() map[nodeColumn]bool {
 func (m Model) formatNodeHeaders(visible map[nodeColumn]bool) string {
 	var parts []string
 	
-	columnOrder := []nodeColumn{nodeColName, nodeColStatus, nodeColCPU, nodeColMem, nodeColMemGiB, nodeColDiskIO, nodeColNetIO, nodeColVMs, nodeColCTs}
+	columnOrder := []nodeColumn{nodeColName, nodeColStatus, nodeColCPU, nodeColCPUMin, nodeColCPUAvg, nodeColCPUMax, nodeColCPUP95, nodeColCPUTrend, nodeColMem, nodeColMemTrend, nodeColMemGiB, nodeColDiskIO, nodeColDiskTrend, nodeColNetIO, nodeColNetTrend, nodeColVMs, nodeColCTs}
 	
 	for _, col := range columnOrder {
 		if !visible[col] {
@@ -414,6 +680,22 @@ func (m Model) formatNodeHeaders(visible map[nodeColumn]bool) string {
 			parts = append(parts, fmt.Sprintf("%6s", "#VMs"))
 		case nodeColCTs:
 			parts = append(parts, fmt.Sprintf("%6s", "#CTs"))
+		case nodeColCPUTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "CPU HIST"))
+		case nodeColMemTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "MEM HIST"))
+		case nodeColDiskTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "DSK HIST"))
+		case nodeColNetTrend:
+			parts = append(parts, fmt.Sprintf("%-8s", "NET HIST"))
+		case nodeColCPUMin:
+			parts = append(parts, fmt.Sprintf("%6s", "MIN%"))
+		case nodeColCPUAvg:
+			parts = append(parts, fmt.Sprintf("%6s", "AVG%"))
+		case nodeColCPUMax:
+			parts = append(parts, fmt.Sprintf("%6s", "MAX%"))
+		case nodeColCPUP95:
+			parts = append(parts, fmt.Sprintf("%6s", "P95%"))
 		}
 	}
 	
@@ -435,7 +717,7 @@ func (m Model) formatNodeRow(node models.Node, visible map[nodeColumn]bool) stri
 	memMaxGiB := float64(node.MaxMem) / (1024 * 1024 * 1024)
 	memGiBText := fmt.Sprintf("%5.1f / %-5.1f", memUsedGiB, memMaxGiB)
 	
-	columnOrder := []nodeColumn{nodeColName, nodeColStatus, nodeColCPU, nodeColMem, nodeColMemGiB, nodeColDiskIO, nodeColNetIO, nodeColVMs, nodeColCTs}
+	columnOrder := []nodeColumn{nodeColName, nodeColStatus, nodeColCPU, nodeColCPUMin, nodeColCPUAvg, nodeColCPUMax, nodeColCPUP95, nodeColCPUTrend, nodeColMem, nodeColMemTrend, nodeColMemGiB, nodeColDiskIO, nodeColDiskTrend, nodeColNetIO, nodeColNetTrend, nodeColVMs, nodeColCTs}
 	
 	for _, col := range columnOrder {
 		if !visible[col] {
@@ -445,12 +727,16 @@ func (m Model) formatNodeRow(node models.Node, visible map[nodeColumn]bool) stri
 		case nodeColName:
 			parts = append(parts, fmt.Sprintf("%-12s", node.Node))
 		case nodeColStatus:
+			statusText := node.Status
 			statusColor := theme.Catppuccin.Red
-			if node.Status == "online" {
+			if m.isStale(node.Node) {
+				statusText = "stale"
+				statusColor = theme.Catppuccin.Yellow
+			} else if node.Status == "online" {
 				statusColor = theme.Catppuccin.Green
 			}
 			statusStyle := lipgloss.NewStyle().Foreground(statusColor).Bold(true)
-			parts = append(parts, statusStyle.Render(fmt.Sprintf("%-8s", node.Status)))
+			parts = append(parts, statusStyle.Render(fmt.Sprintf("%-8s", statusText)))
 		case nodeColCPU:
 			cpuColor := theme.Catppuccin.Green
 			if cpuPercent > 80 {
@@ -479,6 +765,16 @@ func (m Model) formatNodeRow(node models.Node, visible map[nodeColumn]bool) stri
 			parts = append(parts, fmt.Sprintf("%6d", vmCount))
 		case nodeColCTs:
 			parts = append(parts, fmt.Sprintf("%6d", ctCount))
+		case nodeColCPUTrend, nodeColMemTrend, nodeColDiskTrend, nodeColNetTrend:
+			trendStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue)
+			parts = append(parts, trendStyle.Render(renderSparkline(m.nodeTrend(node.Node, nodeTrendMetrics[col]), nodeTrendMetrics[col])))
+		case nodeColCPUMin, nodeColCPUAvg, nodeColCPUMax, nodeColCPUP95:
+			stats, ok := m.windowStats(nodeHistoryKey(node.Node), metricCPU)
+			if !ok {
+				parts = append(parts, fmt.Sprintf("%6s", "—"))
+			} else {
+				parts = append(parts, fmt.Sprintf("%6.1f", statsValue(stats, nodeStatsColumns[col])))
+			}
 		}
 	}
 	
@@ -490,113 +786,416 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.ensureCursorVisible()
 		return m, tea.ClearScreen 
 
 	case tickMsg:
-		return m, tea.Batch(
-			tick(),
-			m.fetchData(),
-		)
+		if m.cancelFetch != nil {
+			m.cancelFetch()
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancelFetch = cancel
+		m.pruneTasks()
+		cmds := []tea.Cmd{tick(), m.fetchData(ctx), m.fetchClusterStatus(ctx), m.pollTasks(), m.fetchViewData(ctx)}
+		if m.viewMode == viewGuestDetail && m.detail != nil {
+			cmds = append(cmds, m.fetchGuestDetail(ctx, m.detail.guest))
+			if m.detailHistoryDue() {
+				cmds = append(cmds, m.fetchDetailHistory(m.detail.guest, m.detail.timeframe))
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case actionStartedMsg:
+		m.trackTask(msg)
+
+	case taskStatusMsg:
+		m.updateTask(msg)
+
+	case guestDetailMsg:
+		m.updateGuestDetail(msg)
+
+	case detailHistoryMsg:
+		m.updateDetailHistory(msg)
+
+	case cephMsg:
+		m.updateCeph(msg)
+
+	case haMsg:
+		m.updateHA(msg)
+
+	case clusterStatusMsg:
+		m.updateClusterStatus(msg)
+
+	case replicationMsg:
+		m.updateReplication(msg)
+
+	case replicationScheduledMsg:
+		return m.updateReplicationScheduled(msg)
+
+	case replicationLogMsg:
+		m.updateReplicationLog(msg)
+
+	case nodeDetailMsg:
+		m.updateNodeDetail(msg)
+
+	case taskListMsg:
+		m.updateTasks(msg)
+
+	case taskLogMsg:
+		m.updateTaskLog(msg)
+
+	case migrateCheckMsg:
+		m.updateMigrationCheck(msg)
+
+	case taskProgressMsg:
+		m.updateTaskProgress(msg)
+
+	case snapshotsMsg:
+		m.updateSnapshots(msg)
+
+	case backupsMsg:
+		m.updateBackups(msg)
+
+	case bulkStartMsg:
+		return m.startBulk(msg)
+
+	case bulkUpdateMsg:
+		return m.updateBulk(msg)
+
+	case bulkDoneMsg:
+		m = m.finishBulk(msg)
 
 	case dataMsg:
-		prevGuestMap := make(map[int]models.Guest, len(m.guests))
-		for _, guest := range m.guests {
-			prevGuestMap[guest.VMID] = guest
-		}
-		m.prevGuestMap = prevGuestMap
-		m.prevGuests = m.guests
-		m.guests = msg.guests
+		m.guests = m.carryStaleGuests(msg.guests, msg.nodeErrors)
 		m.nodes = msg.nodes
-		m.isCluster = len(msg.nodes) > 1
-		now := time.Now()
-		if !m.lastUpdate.IsZero() {
-			m.lastFetch = m.lastUpdate
+		m.nodeErrors = msg.nodeErrors
+		m.updateStorage(msg.storage, msg.storageErr)
+		m.err = nil
+		m.isCluster = m.detectCluster()
+		m.lastUpdate = time.Now()
+		m.recordSamples(m.lastUpdate)
+		m.sortGuests()
+		m.syncCursor()
+		m.refreshGuestDetail()
+		var cmds []tea.Cmd
+		if m.historyDue() {
+			m.historyLoading = true
+			cmds = append(cmds, m.fetchHistory())
 		}
-		m.lastUpdate = now
-		if m.lastFetch.IsZero() {
-			m.lastFetch = now
+		if m.backupsDue() {
+			m.backupsLoading = true
+			cmds = append(cmds, m.fetchBackups())
+		}
+		if m.haDue() {
+			m.haLoading = true
+			cmds = append(cmds, m.fetchHA())
+		}
+		if m.replicationDue() {
+			m.replLoading = true
+			cmds = append(cmds, m.fetchReplication(context.Background()))
+		}
+		if len(cmds) > 0 {
+			return m, tea.Batch(cmds...)
 		}
-		m.sortGuests()
+
+	case historyMsg:
+		m.updateHistory(msg)
 
 	case errMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
 		m.err = msg.err
 
 	case tea.KeyMsg:
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		if m.migrate != nil {
+			return m.updateMigrateKeys(msg)
+		}
+		if m.backup != nil {
+			return m.updateBackupKeys(msg)
+		}
+		if m.bulkForm != nil {
+			return m.updateBulkFormKeys(msg)
+		}
+		if m.filterPrompt != nil {
+			return m.updateFilterKeys(msg)
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.showBulk {
+			m.showBulk = false
+			return m, nil
+		}
+
+		if m.viewMode == viewGuestDetail && m.detail != nil {
+			if updated, cmd, handled := m.updateDetailKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewSnapshots && m.snapshots != nil {
+			if updated, cmd, handled := m.updateSnapshotKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewTaskLog && m.taskLog != nil {
+			if updated, cmd, handled := m.updateTaskLogKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewCeph {
+			if updated, cmd, handled := m.updateCephKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewHA {
+			if updated, cmd, handled := m.updateHAKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewNodeDetail && m.nodeDetail != nil {
+			if updated, cmd, handled := m.updateNodeDetailKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewReplication {
+			if updated, cmd, handled := m.updateReplicationKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.viewMode == viewReplicationLog && m.replLog != nil {
+			if updated, cmd, handled := m.updateReplicationLogKeys(msg); handled {
+				return updated, cmd
+			}
+		}
+
 		switch {
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+
 		case key.Matches(msg, m.keys.Quit):
+			if m.cancelFetch != nil {
+				m.cancelFetch()
+			}
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.Up):
-			if m.scrollOffset > 0 {
-				m.scrollOffset--
-			}
+			m.moveCursor(-1)
 
 		case key.Matches(msg, m.keys.Down):
-			contentHeight := m.height - 5 
-			if contentHeight < 1 {
-				contentHeight = 1
-			}
-			displayGuests := m.getDisplayGuests()
-			maxScroll := len(displayGuests) - contentHeight
-			if maxScroll < 0 {
-				maxScroll = 0
+			m.moveCursor(1)
+
+		case key.Matches(msg, m.keys.PageUp):
+			m.moveCursor(-m.pageSize())
+
+		case key.Matches(msg, m.keys.PageDown):
+			m.moveCursor(m.pageSize())
+
+		case key.Matches(msg, m.keys.Top):
+			m.setCursor(0)
+
+		case key.Matches(msg, m.keys.Bottom):
+			m.setCursor(m.rowCount() - 1)
+
+		case m.viewMode == viewGuests && m.grouping != groupNone && key.Matches(msg, m.keys.Open):
+			if _, ok := m.selectedGroup(); ok {
+				m.toggleGroup()
+				return m, nil
 			}
-			if m.scrollOffset < maxScroll {
-				m.scrollOffset++
+			return m.openGuestDetail()
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.Open):
+			return m.openGuestDetail()
+
+		case m.viewMode == viewNodes && key.Matches(msg, m.keys.Open):
+			return m.openNodeDetail()
+
+		case m.viewMode == viewTasks && key.Matches(msg, m.keys.Open):
+			return m.openTaskLog()
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Start):
+			return m.requestGuestAction(api.ActionStart)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Shutdown):
+			return m.requestGuestAction(api.ActionShutdown)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Stop):
+			return m.requestGuestAction(api.ActionStop)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Reboot):
+			return m.requestGuestAction(api.ActionReboot)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Suspend):
+			return m.requestGuestAction(api.ActionSuspend)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Resume):
+			return m.requestGuestAction(api.ActionResume)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Migrate):
+			return m.requestMigration()
+
+		case m.hasMarks() && key.Matches(msg, m.keys.Snapshots):
+			return m.openBulkForm(bulkSnapshot)
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Snapshots):
+			return m.openSnapshots()
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Tags):
+			return m.openBulkForm(bulkTags)
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.Mark):
+			m.toggleMark()
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.MarkAll):
+			m.toggleMarkAll()
+
+		case m.hasMarks() && key.Matches(msg, m.keys.Back):
+			m.clearMarks()
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.Group):
+			m.cycleGrouping()
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.Fold):
+			m.toggleGroup()
+
+		case m.viewMode == viewGuests && key.Matches(msg, m.keys.FoldAll):
+			m.toggleAllGroups()
+
+		case (m.viewMode == viewGuests || m.viewMode == viewNodes) && key.Matches(msg, m.keys.Filter):
+			return m.openFilterPrompt()
+
+		case (m.viewMode == viewGuests || m.viewMode == viewNodes) && m.filter != nil && key.Matches(msg, m.keys.Back):
+			m.applyFilter(nil)
+			m.setNotice("Filter cleared")
+
+		case key.Matches(msg, m.keys.Report):
+			if m.bulk == nil {
+				m.setNotice("No bulk action has run yet")
+				return m, nil
 			}
+			m.showBulk = true
+
+		case m.isGuestView() && key.Matches(msg, m.keys.Backup):
+			return m.requestBackup()
 
 		case key.Matches(msg, m.keys.SortVMID):
 			m.sortBy = sortByVMID
 			m.sortGuests()
-			m.scrollOffset = 0 
+			m.syncCursor()
 
 		case key.Matches(msg, m.keys.SortCPU):
 			m.sortBy = sortByCPU
 			m.sortGuests()
-			m.scrollOffset = 0
+			m.syncCursor()
 
 		case key.Matches(msg, m.keys.SortMem):
 			m.sortBy = sortByMem
 			m.sortGuests()
-			m.scrollOffset = 0
+			m.syncCursor()
 
 		case key.Matches(msg, m.keys.SortDiskIO):
 			m.sortBy = sortByDiskIO
 			m.sortGuests()
-			m.scrollOffset = 0
+			m.syncCursor()
 
 		case key.Matches(msg, m.keys.SortNetIO):
 			m.sortBy = sortByNetIO
 			m.sortGuests()
-			m.scrollOffset = 0
+			m.syncCursor()
 
 		case key.Matches(msg, m.keys.Reverse):
 			m.sortReverse = !m.sortReverse
 			m.sortGuests()
-			m.scrollOffset = 0
+			m.syncCursor()
+
+		case key.Matches(msg, m.keys.NextView):
+			return m.cycleView(1)
+
+		case key.Matches(msg, m.keys.PrevView):
+			return m.cycleView(-1)
+
+		case key.Matches(msg, m.keys.Stats):
+			m.showStats = !m.showStats
 
 		case key.Matches(msg, m.keys.ToggleAll):
 			m.showAll = !m.showAll
-			m.scrollOffset = 0 
-
-		case key.Matches(msg, m.keys.ToggleView):
-			if len(m.nodes) > 0 { 
-				if m.viewMode == viewGuests {
-					m.viewMode = viewNodes
-				} else {
-					m.viewMode = viewGuests
-				}
-				m.scrollOffset = 0 
+			m.syncCursor()
+
+		case m.viewMode != viewGuestDetail && key.Matches(msg, m.keys.ToggleView):
+			if m.viewMode == viewGuests {
+				return m.switchView(viewNodes)
 			}
+			return m.switchView(viewGuests)
 		}
 	}
 
 	return m, nil
 }
 
+func (m Model) isStale(nodeName string) bool {
+	_, stale := m.nodeErrors[nodeName]
+	return stale
+}
+
+func (m Model) carryStaleGuests(guests []models.Guest, nodeErrors map[string]error) []models.Guest {
+	if len(nodeErrors) == 0 {
+		return guests
+	}
+	
+	last := make(map[int]models.Guest)
+	for _, guest := range m.guests {
+		if _, stale := nodeErrors[guest.Node]; stale {
+			last[guest.VMID] = guest
+		}
+	}
+	
+	var merged []models.Guest
+	for _, guest := range guests {
+		if prev, ok := last[guest.VMID]; ok {
+			if guest.Status == "unknown" {
+				guest = prev
+			}
+			delete(last, guest.VMID)
+		}
+		merged = append(merged, guest)
+	}
+	
+	for _, guest := range m.guests {
+		if _, ok := last[guest.VMID]; ok {
+			merged = append(merged, guest)
+		}
+	}
+	
+	return merged
+}
+
+func (m Model) renderNodeErrors() string {
+	if len(m.nodeErrors) == 0 || m.width < widthSmall {
+		return ""
+	}
+	
+	var names []string
+	for name := range m.nodeErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	text := fmt.Sprintf(" unreachable: %s", strings.Join(names, ", "))
+	if len(names) == 1 && m.width >= widthLarge {
+		text = fmt.Sprintf(" %s unreachable (%v) - showing last known guests", names[0], m.nodeErrors[names[0]])
+	}
+	
+	warnStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Yellow)
+	return warnStyle.Render(truncate(text, m.width))
+}
+
 func (m Model) getDisplayGuests() []models.Guest {
 	displayGuests := m.guests
-	if !m.showAll {
+	if !m.showAll && !m.filter.hasField("status") {
 		var activeGuests []models.Guest
 		for _, g := range m.guests {
 			if g.Status == "running" {
@@ -605,6 +1204,15 @@ func (m Model) getDisplayGuests() []models.Guest {
 		}
 		displayGuests = activeGuests
 	}
+	if m.filter != nil {
+		var matching []models.Guest
+		for _, g := range displayGuests {
+			if m.filter.matchGuest(g) {
+				matching = append(matching, g)
+			}
+		}
+		displayGuests = matching
+	}
 	return displayGuests
 }
 
@@ -653,29 +1261,48 @@ func (m *Model) sortGuests() {
 }
 
 type dataMsg struct {
-	guests []models.Guest
-	nodes  []models.Node
+	guests     []models.Guest
+	nodes      []models.Node
+	nodeErrors map[string]error
+	storage    []models.Storage
+	storageErr error
 }
 
 type errMsg struct {
 	err error
 }
 
-func (m Model) fetchData() tea.Cmd {
+func (m Model) fetchData(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
-		nodes, err := m.client.GetNodes()
+		res, err := m.client.GetClusterResources(ctx)
 		if err != nil {
-			return errMsg{err: err}
+			if ctx.Err() != nil {
+				return errMsg{err: ctx.Err()}
+			}
+			fallback, fallbackErr := m.client.GetAllGuests(ctx)
+			if fallbackErr != nil {
+				return errMsg{err: errors.Join(err, fmt.Errorf("per-node fallback: %w", fallbackErr))}
+			}
+			return dataMsg{
+				guests:     fallback.Guests,
+				nodes:      fallback.Nodes,
+				nodeErrors: fallback.NodeErrors,
+				storageErr: err,
+			}
 		}
 		
-		guests, err := m.client.GetAllGuests()
-		if err != nil {
-			return errMsg{err: err}
+		nodeErrors := make(map[string]error)
+		for _, node := range res.Nodes {
+			if node.Status != "online" {
+				nodeErrors[node.Node] = fmt.Errorf("node is %s", node.Status)
+			}
 		}
 		
 		return dataMsg{
-			guests: guests,
-			nodes:  nodes,
+			guests:     res.Guests,
+			nodes:      res.Nodes,
+			nodeErrors: nodeErrors,
+			storage:    res.Storage,
 		}
 	}
 }
@@ -707,9 +1334,76 @@ func (m Model) View() string {
 
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Red)
-		return errorStyle.Render(fmt.Sprintf("Error: %v\n\nPress 'q' to quit.", m.err))
+		hint := "Retrying every 2s."
+		switch {
+		case api.IsCertificateMismatch(m.err):
+			hint = "WARNING: the server certificate changed. If expected, rerun 'pvetop --setup'."
+		case api.IsUnauthorized(m.err):
+			hint = "The API token was rejected. Run 'pvetop --setup' to create a new one."
+		case api.IsForbidden(m.err):
+			hint = "The API token lacks the permission above. Grant it PVEAuditor on / or rerun setup."
+		}
+		var nodes string
+		if len(m.nodeErrors) > 0 {
+			var names []string
+			for name := range m.nodeErrors {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				nodes += fmt.Sprintf("\n  %s: %v", name, m.nodeErrors[name])
+			}
+			nodes = "\n\nNodes:" + nodes
+		}
+		return errorStyle.Render(fmt.Sprintf("Error: %v%s\n\n%s\n\nPress 'q' to quit.", m.err, nodes, hint))
 	}
 
+	if m.showHelp {
+		return m.viewHelp()
+	}
+	if m.migrate != nil {
+		return m.viewMigrate()
+	}
+	if m.backup != nil {
+		return m.viewBackup()
+	}
+	if m.bulkForm != nil {
+		return m.viewBulkForm()
+	}
+	if m.showBulk && m.bulk != nil {
+		return m.viewBulkReport()
+	}
+
+	if m.viewMode == viewGuestDetail && m.detail != nil {
+		return m.viewGuestDetail()
+	}
+	if m.viewMode == viewNodeDetail && m.nodeDetail != nil {
+		return m.viewNodeDetail()
+	}
+	if m.viewMode == viewStorage {
+		return m.viewStorage()
+	}
+	if m.viewMode == viewCeph {
+		return m.viewCeph()
+	}
+	if m.viewMode == viewHA {
+		return m.viewHA()
+	}
+	if m.viewMode == viewReplication {
+		return m.viewReplication()
+	}
+	if m.viewMode == viewReplicationLog && m.replLog != nil {
+		return m.viewReplicationLog()
+	}
+	if m.viewMode == viewTasks {
+		return m.viewTasks()
+	}
+	if m.viewMode == viewSnapshots && m.snapshots != nil {
+		return m.viewSnapshots()
+	}
+	if m.viewMode == viewTaskLog && m.taskLog != nil {
+		return m.viewTaskLog()
+	}
 	if m.viewMode == viewNodes && len(m.nodes) > 0 {
 		return m.viewNodes()
 	}
@@ -738,16 +1432,16 @@ func (m Model) viewNodes() string {
 		if len(m.nodes) == 1 {
 			nodeViewTitle = "node"
 		}
-		headerText = fmt.Sprintf(" pvetop - %s (%d/%d online) - refresh: 2s ", 
-			nodeViewTitle, onlineNodes, len(m.nodes))
+		headerText = fmt.Sprintf(" pvetop - %s (%d/%d online)%s - refresh: 2s ", 
+			nodeViewTitle, onlineNodes, len(m.nodes), m.filterHeader(len(m.getDisplayNodes())))
 	} else if m.width >= widthSmall {
-		headerText = fmt.Sprintf(" pvetop nodes (%d/%d online) ", onlineNodes, len(m.nodes))
+		headerText = fmt.Sprintf(" pvetop nodes (%d/%d online)%s ", onlineNodes, len(m.nodes), m.filterHeader(len(m.getDisplayNodes())))
 	} else {
 		headerText = fmt.Sprintf(" pvetop (%d/%d) ", onlineNodes, len(m.nodes))
 	}
 	
-	s += headerStyle.Render(headerText)
-	s += "\n\n"
+	s += headerStyle.Render(truncate(headerText, m.width))
+	s += "\n" + m.renderClusterBanner() + "\n"
 
 	visibleNodeCols := m.getVisibleNodeColumns()
 	
@@ -764,14 +1458,29 @@ func (m Model) viewNodes() string {
 		contentHeight = 1
 	}
 	
-	for _, node := range m.nodes {
+	displayNodes := m.getDisplayNodes()
+	startIdx := m.scrollOffset
+	if startIdx > len(displayNodes) {
+		startIdx = len(displayNodes)
+	}
+	endIdx := startIdx + contentHeight
+	if endIdx > len(displayNodes) {
+		endIdx = len(displayNodes)
+	}
+	visibleNodes := displayNodes[startIdx:endIdx]
+	
+	for i, node := range visibleNodes {
 		rowStyle := lipgloss.NewStyle().Width(m.width)
 		
 		row := m.formatNodeRow(node, visibleNodeCols)
+		if startIdx+i == m.selectedRow {
+			s += highlightRow(row, m.width) + "\n"
+			continue
+		}
 		s += rowStyle.Render(row) + "\n"
 	}
 
-	usedHeight := 4 + len(m.nodes) 
+	usedHeight := 4 + len(visibleNodes) 
 	paddingLines := m.height - usedHeight - 1 
 	if paddingLines < 0 {
 		paddingLines = 0
@@ -787,7 +1496,7 @@ func (m Model) viewNodes() string {
 	
 	var helpText string
 	if m.width >= widthLarge {
-		helpText = "q:quit | n:switch-to-guests | c:sort-cpu | m:sort-mem | r:reverse"
+		helpText = "q:quit | ?:help | /:filter | ↑↓:select | enter:details | n:switch-to-guests | c:sort-cpu | m:sort-mem"
 	} else if m.width >= widthMedium {
 		helpText = "q:quit | n:guests | c/m:sort | r:reverse"
 	} else if m.width >= widthTiny {
@@ -796,7 +1505,7 @@ func (m Model) viewNodes() string {
 		helpText = "q:quit"
 	}
 	
-	s += "\n" + helpStyle.Render(helpText)
+	s += m.renderStatusBar() + "\n" + helpStyle.Render(helpText)
 
 	return s
 }
@@ -810,16 +1519,8 @@ func (m Model) viewGuests() string {
 		Background(theme.Catppuccin.Surface1).
 		Width(m.width)
 	
-	displayGuests := m.guests
-	if !m.showAll {
-		var activeGuests []models.Guest
-		for _, g := range m.guests {
-			if g.Status == "running" {
-				activeGuests = append(activeGuests, g)
-			}
-		}
-		displayGuests = activeGuests
-	}
+	displayGuests := m.getDisplayGuests()
+	rows := m.guestRows()
 	
 	totalGuests := len(m.guests)
 	activeGuests := 0
@@ -831,16 +1532,32 @@ func (m Model) viewGuests() string {
 	
 	var headerText string
 	if m.width >= widthLarge {
-		headerText = fmt.Sprintf(" pvetop - connected to %s (%d/%d running guests) - refresh: 2s ", 
-			"proxmox", activeGuests, totalGuests)
+		marked := ""
+		if n := len(m.markedGuests()); n > 0 {
+			marked = fmt.Sprintf(", %d marked", n)
+		}
+		if n := m.failingReplications(); n == 1 {
+			marked += ", 1 replication job failing"
+		} else if n > 1 {
+			marked += fmt.Sprintf(", %d replication jobs failing", n)
+		}
+		if missing := m.replicationUnavailable(); missing != "" {
+			marked += ", replication " + missing
+		}
+		grouped := ""
+		if m.grouping != groupNone {
+			grouped = " - grouped by " + m.grouping.String()
+		}
+		headerText = fmt.Sprintf(" pvetop - connected to %s (%d/%d running guests%s)%s%s - refresh: 2s ", 
+			m.clusterName(), activeGuests, totalGuests, marked, m.filterHeader(len(displayGuests)), grouped)
 	} else if m.width >= widthSmall {
-		headerText = fmt.Sprintf(" pvetop (%d/%d running) ", activeGuests, totalGuests)
+		headerText = fmt.Sprintf(" pvetop (%d/%d running)%s ", activeGuests, totalGuests, m.filterHeader(len(displayGuests)))
 	} else {
 		headerText = fmt.Sprintf(" pvetop (%d/%d) ", activeGuests, totalGuests)
 	}
 	
-	s += headerStyle.Render(headerText)
-	s += "\n\n"
+	s += headerStyle.Render(truncate(headerText, m.width))
+	s += "\n" + m.renderClusterBanner() + "\n"
 
 	visibleCols := m.getVisibleColumns()
 	
@@ -857,7 +1574,7 @@ func (m Model) viewGuests() string {
 		contentHeight = 1
 	}
 	
-	maxScroll := len(displayGuests) - contentHeight
+	maxScroll := len(rows) - contentHeight
 	if maxScroll < 0 {
 		maxScroll = 0
 	}
@@ -870,21 +1587,30 @@ func (m Model) viewGuests() string {
 	
 	startIdx := m.scrollOffset
 	endIdx := startIdx + contentHeight
-	if endIdx > len(displayGuests) {
-		endIdx = len(displayGuests)
+	if endIdx > len(rows) {
+		endIdx = len(rows)
 	}
 	
-	visibleGuests := displayGuests[startIdx:endIdx]
+	visibleRows := rows[startIdx:endIdx]
 
-	for _, guest := range visibleGuests {
+	for i, guestRow := range visibleRows {
 		
 		rowStyle := lipgloss.NewStyle().Width(m.width)
 		
-		row := m.formatGuestRow(guest, visibleCols)
+		var row string
+		if guestRow.header {
+			row = m.formatGroupRow(guestRow.group, visibleCols)
+		} else {
+			row = m.formatGuestRow(guestRow.guest, visibleCols)
+		}
+		if startIdx+i == m.selectedRow {
+			s += highlightRow(row, m.width) + "\n"
+			continue
+		}
 		s += rowStyle.Render(row) + "\n"
 	}
 
-	usedHeight := 4 + len(visibleGuests) 
+	usedHeight := 4 + len(visibleRows) 
 	paddingLines := m.height - usedHeight - 1 
 	if paddingLines < 0 {
 		paddingLines = 0
@@ -900,22 +1626,27 @@ func (m Model) viewGuests() string {
 	
 	var helpText string
 	if m.width >= widthLarge {
-		helpText = "q:quit | ↑↓/jk:scroll | c/m/d/i:sort | r:reverse | a:toggle-all"
+		helpText = "q:quit | ?:help | /:filter | ↑↓/jk:select | c/m/d/i:sort | r:reverse | a:all"
 		if len(m.nodes) > 0 {
 			helpText += " | n:nodes"
 		}
+		helpText += " | S/D/X/R/P/U:power"
+		if m.isCluster {
+			helpText += " | M:migrate"
+		}
+		helpText += " | s:snapshots | b:backup | space/x:mark | z:group"
 	} else if m.width >= widthMedium {
-		helpText = "q:quit | ↑↓:scroll | c/m:sort | r:reverse | a:all"
+		helpText = "q:quit | ↑↓:select | c/m:sort | r:reverse | a:all"
 		if len(m.nodes) > 0 {
 			helpText += " | n:nodes"
 		}
 	} else if m.width >= widthTiny {
-		helpText = "q:quit | ↑↓:scroll | c/m:sort"
+		helpText = "q:quit | ↑↓:select | c/m:sort"
 	} else {
 		helpText = "q:quit"
 	}
 	
-	s += "\n" + helpStyle.Render(helpText)
+	s += m.renderStatusBar() + "\n" + helpStyle.Render(truncate(helpText, m.width))
 
 	return s
 }
@@ -927,18 +1658,7 @@ func (m Model) getDiskRate(guest models.Guest) string {
 }
 
 func (m Model) getDiskRateNumeric(guest models.Guest) int64 {
-	if prev, ok := m.prevGuestMap[guest.VMID]; ok {
-		timeDiff := m.lastUpdate.Sub(m.lastFetch).Seconds()
-		if timeDiff > 0 {
-			readDiff := guest.DiskRead - prev.DiskRead
-			writeDiff := guest.DiskWrite - prev.DiskWrite
-			if readDiff < 0 { readDiff = 0 }
-			if writeDiff < 0 { writeDiff = 0 }
-			totalDiff := readDiff + writeDiff
-			return int64(float64(totalDiff) / timeDiff)
-		}
-	}
-	return 0
+	return int64(m.guestRate(guest).Disk)
 }
 
 func (m Model) getNetRate(guest models.Guest) string {
@@ -947,18 +1667,7 @@ func (m Model) getNetRate(guest models.Guest) string {
 }
 
 func (m Model) getNetRateNumeric(guest models.Guest) int64 {
-	if prev, ok := m.prevGuestMap[guest.VMID]; ok {
-		timeDiff := m.lastUpdate.Sub(m.lastFetch).Seconds()
-		if timeDiff > 0 {
-			inDiff := guest.NetIn - prev.NetIn
-			outDiff := guest.NetOut - prev.NetOut
-			if inDiff < 0 { inDiff = 0 }
-			if outDiff < 0 { outDiff = 0 }
-			totalDiff := inDiff + outDiff
-			return int64(float64(totalDiff) / timeDiff)
-		}
-	}
-	return 0
+	return int64(m.guestRate(guest).Net)
 }
 
 func (m Model) calculateHostCPUPercent(guest models.Guest) float64 {
@@ -1014,10 +1723,14 @@ func (m Model) getNodeNetRate(nodeName string) string {
 
 
 func truncate(s string, n int) string {
-	if len(s) <= n {
+	runes := []rune(s)
+	if len(runes) <= n {
 		return s
 	}
-	return s[:n-3] + "..."
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
 }
 
 func formatBytes(b int64) string {
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testResources(count int) models.ClusterResources {
+	res := models.ClusterResources{
+		Nodes: []models.Node{
+			{Node: "pve1", Status: "online", CPU: 0.2, MaxCPU: 16, Mem: 8 << 30, MaxMem: 64 << 30, Uptime: 3600},
+			{Node: "pve2", Status: "online", CPU: 0.4, MaxCPU: 16, Mem: 16 << 30, MaxMem: 64 << 30, Uptime: 3600},
+		},
+	}
+	for i := 0; i < count; i++ {
+		res.Guests = append(res.Guests, models.Guest{
+			VMID:   100 + i,
+			Name:   fmt.Sprintf("guest-%02d", i),
+			Type:   "qemu",
+			Status: "running",
+			Node:   res.Nodes[i%2].Node,
+			CPU:    float64((i*7)%count) / float64(count),
+			CPUs:   2,
+			Mem:    int64((i*3)%count+1) << 20,
+			MaxMem: int64(count) << 20,
+			Uptime: 600,
+		})
+	}
+	return res
+}
+
+func newTestModel(t *testing.T, res models.ClusterResources, width, height int) Model {
+	t.Helper()
+	m := NewModel(api.NewStaticSource(res))
+	var tm tea.Model = m
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	tm, _ = tm.Update(m.fetchData(context.Background())())
+	return tm.(Model)
+}
+
+func pressKeys(m Model, keys ...string) Model {
+	var tm tea.Model = m
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "pgdown":
+			msg = tea.KeyMsg{Type: tea.KeyPgDown}
+		case "pgup":
+			msg = tea.KeyMsg{Type: tea.KeyPgUp}
+		case "home":
+			msg = tea.KeyMsg{Type: tea.KeyHome}
+		case "end":
+			msg = tea.KeyMsg{Type: tea.KeyEnd}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		tm, _ = tm.Update(msg)
+	}
+	return tm.(Model)
+}
+
+func guestIDs(guests []models.Guest) []int {
+	ids := make([]int, len(guests))
+	for i, g := range guests {
+		ids[i] = g.VMID
+	}
+	return ids
+}
+
+func TestSortOrder(t *testing.T) {
+	res := models.ClusterResources{
+		Nodes: []models.Node{{Node: "pve1", Status: "online", MaxCPU: 8, MaxMem: 8 << 30}},
+		Guests: []models.Guest{
+			{VMID: 103, Name: "c", Type: "qemu", Status: "running", Node: "pve1", CPU: 0.10, Mem: 3 << 20, MaxMem: 4 << 20},
+			{VMID: 101, Name: "a", Type: "qemu", Status: "running", Node: "pve1", CPU: 0.50, Mem: 1 << 20, MaxMem: 4 << 20},
+			{VMID: 104, Name: "d", Type: "lxc", Status: "stopped", Node: "pve1", MaxMem: 4 << 20},
+			{VMID: 102, Name: "b", Type: "lxc", Status: "running", Node: "pve1", CPU: 0.30, Mem: 2 << 20, MaxMem: 4 << 20},
+			{VMID: 100, Name: "e", Type: "qemu", Status: "stopped", Node: "pve1", MaxMem: 4 << 20},
+		},
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+		want []int
+	}{
+		{"default is CPU descending", nil, []int{101, 102, 103, 100, 104}},
+		{"reverse", []string{"r"}, []int{103, 102, 101, 100, 104}},
+		{"VMID", []string{"v", "r"}, []int{101, 102, 103, 100, 104}},
+		{"memory descending", []string{"m"}, []int{103, 102, 101, 100, 104}},
+		{"memory ascending", []string{"m", "r"}, []int{101, 102, 103, 100, 104}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := pressKeys(newTestModel(t, res, 120, 30), tt.keys...)
+			m.showAll = true
+			if got := guestIDs(m.getDisplayGuests()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorAndScroll(t *testing.T) {
+	res := testResources(30)
+
+	tests := []struct {
+		name       string
+		keys       []string
+		wantRow    int
+		wantOffset int
+	}{
+		{"no selection", nil, -1, 0},
+		{"first page down selects the first row", []string{"pgdown"}, 0, 0},
+		{"page down", []string{"down", "pgdown"}, 10, 1},
+		{"two pages down", []string{"down", "pgdown", "pgdown"}, 20, 11},
+		{"page down stops at the end", []string{"end", "pgdown"}, 29, 20},
+		{"end", []string{"end"}, 29, 20},
+		{"G", []string{"G"}, 29, 20},
+		{"page up from the end", []string{"end", "pgup"}, 19, 19},
+		{"home", []string{"end", "home"}, 0, 0},
+		{"up stops at the top", []string{"down", "up", "up"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := pressKeys(newTestModel(t, res, 120, 15), tt.keys...)
+			if m.selectedRow != tt.wantRow || m.scrollOffset != tt.wantOffset {
+				t.Errorf("row %d offset %d, want row %d offset %d", m.selectedRow, m.scrollOffset, tt.wantRow, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestCursorFollowsGuestAcrossSort(t *testing.T) {
+	m := pressKeys(newTestModel(t, testResources(30), 120, 15), "down", "down", "down")
+	guest, ok := m.selectedGuest()
+	if !ok {
+		t.Fatal("no guest selected")
+	}
+
+	m = pressKeys(m, "v")
+	got, ok := m.selectedGuest()
+	if !ok || got.VMID != guest.VMID {
+		t.Errorf("selected %d after sorting, want %d", got.VMID, guest.VMID)
+	}
+}
+
+func TestColumnSacrifice(t *testing.T) {
+	all := []column{colID, colType, colStatus, colCPU, colMem, colMemGiB, colNetIO, colDiskIO}
+
+	tests := []struct {
+		width  int
+		hidden []column
+	}{
+		{120, nil},
+		{111, nil},
+		{110, []column{colDiskIO}},
+		{widthLarge + 20, []column{colDiskIO}},
+		{widthLarge, []column{colDiskIO, colNetIO, colMemGiB}},
+		{widthMedium, []column{colDiskIO, colNetIO, colMemGiB, colID}},
+		{widthSmall, []column{colDiskIO, colNetIO, colMemGiB, colID, colStatus, colType}},
+		{widthTiny, []column{colDiskIO, colNetIO, colMemGiB, colID, colStatus, colType, colMem}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("width %d", tt.width), func(t *testing.T) {
+			m := newTestModel(t, testResources(4), tt.width, 30)
+			visible := m.getVisibleColumns()
+			if !visible[colName] || !visible[colNode] {
+				t.Errorf("name and node must always be visible")
+			}
+
+			hidden := make(map[column]bool)
+			for _, col := range tt.hidden {
+				hidden[col] = true
+			}
+			for _, col := range all {
+				if visible[col] == hidden[col] {
+					t.Errorf("column %d visible = %v, want %v", col, visible[col], !hidden[col])
+				}
+			}
+		})
+	}
+}
+
+func TestPartialNodeFailureKeepsGuests(t *testing.T) {
+	m := newTestModel(t, testResources(4), 120, 30)
+
+	unknown := models.Guest{VMID: 101, Name: "guest-01", Type: "qemu", Status: "unknown", Node: "pve2"}
+	container := models.Guest{VMID: 200, Name: "ct", Type: "lxc", Status: "running", Node: "pve2"}
+
+	byVMID := map[int]models.Guest{}
+	for _, g := range m.guests {
+		byVMID[g.VMID] = g
+	}
+	var tm tea.Model = m
+	tm, _ = tm.Update(dataMsg{
+		guests:     []models.Guest{byVMID[100], byVMID[102], unknown, container},
+		nodes:      m.nodes,
+		nodeErrors: map[string]error{"pve2": errors.New("qemu listing failed")},
+	})
+	m = tm.(Model)
+
+	got := map[int]models.Guest{}
+	for _, g := range m.guests {
+		got[g.VMID] = g
+	}
+	if len(got) != 5 {
+		t.Fatalf("guests %v, want 100-103 and the container", guestIDs(m.guests))
+	}
+	if got[101].Status != "running" {
+		t.Errorf("guest 101 is %q, want its last known status", got[101].Status)
+	}
+	if got[103] != byVMID[103] {
+		t.Errorf("guest 103 = %+v, want it carried over", got[103])
+	}
+	if got[200] != container {
+		t.Errorf("container = %+v, want the fresh listing", got[200])
+	}
+}
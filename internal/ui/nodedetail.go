@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	ioWaitWarn   = 0.05
+	ioWaitCrit   = 0.15
+	pressureWarn = 0.10
+	pressureCrit = 0.25
+	busyDisks    = 5
+)
+
+type nodeDetail struct {
+	node   string
+	status *models.NodeStatus
+	err    error
+	scroll int
+}
+
+type nodeDetailMsg struct {
+	node   string
+	status *models.NodeStatus
+	err    error
+}
+
+func (m Model) selectedNodeName() (string, bool) {
+	nodes := m.getDisplayNodes()
+	if m.selectedRow < 0 || m.selectedRow >= len(nodes) {
+		return "", false
+	}
+	return nodes[m.selectedRow].Node, true
+}
+
+func (m Model) openNodeDetail() (Model, tea.Cmd) {
+	name, ok := m.selectedNodeName()
+	if !ok {
+		return m, nil
+	}
+
+	m.nodeDetail = &nodeDetail{node: name}
+	m.viewMode = viewNodeDetail
+	return m, m.fetchNodeDetail(context.Background())
+}
+
+func (m Model) fetchNodeDetail(ctx context.Context) tea.Cmd {
+	if m.nodeDetail == nil {
+		return nil
+	}
+	client := m.client
+	name := m.nodeDetail.node
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		status, err := client.GetNodeStatus(ctx, name)
+		return nodeDetailMsg{node: name, status: status, err: err}
+	}
+}
+
+func (m *Model) updateNodeDetail(msg nodeDetailMsg) {
+	if m.nodeDetail == nil || m.nodeDetail.node != msg.node {
+		return
+	}
+	if errors.Is(msg.err, context.Canceled) {
+		return
+	}
+	detail := *m.nodeDetail
+	detail.err = msg.err
+	if msg.status != nil {
+		detail.status = msg.status
+	}
+	m.nodeDetail = &detail
+}
+
+func (m Model) updateNodeDetailKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Open):
+		m.viewMode = viewNodes
+		m.nodeDetail = nil
+		m.syncCursor()
+		return m, nil, true
+	}
+
+	scroll, handled := m.scrollLines(msg, m.nodeDetail.scroll, len(m.nodeDetailLines()))
+	if !handled {
+		return m, nil, false
+	}
+	detail := *m.nodeDetail
+	detail.scroll = scroll
+	m.nodeDetail = &detail
+	return m, nil, true
+}
+
+func levelColor(value, warn, crit float64) lipgloss.Color {
+	switch {
+	case value >= crit:
+		return theme.Catppuccin.Red
+	case value >= warn:
+		return theme.Catppuccin.Yellow
+	}
+	return theme.Catppuccin.Green
+}
+
+func formatUsage(used, total int64) string {
+	if total <= 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%s / %s (%.1f%%)", formatBytes(used), formatBytes(total), float64(used)/float64(total)*100)
+}
+
+func usageRatio(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total)
+}
+
+func (m Model) nodeDetailLines() []string {
+	d := m.nodeDetail
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Catppuccin.Mauve)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(16)
+	greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+	valueWidth := m.width - 18
+	if valueWidth < 10 {
+		valueWidth = 10
+	}
+
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, " "+sectionStyle.Render(title))
+	}
+	field := func(label, value string, color lipgloss.Color) {
+		if value == "" {
+			return
+		}
+		lines = append(lines, " "+labelStyle.Render(label)+" "+lipgloss.NewStyle().Foreground(color).Render(truncate(value, valueWidth)))
+	}
+	text := theme.Catppuccin.Text
+
+	var node models.Node
+	for _, n := range m.nodes {
+		if n.Node == d.node {
+			node = n
+		}
+	}
+	var running, total int
+	var guests []models.Guest
+	for _, g := range m.guests {
+		if g.Node != d.node {
+			continue
+		}
+		total++
+		if g.Status == "running" {
+			running++
+			if m.getDiskRateNumeric(g) > 0 {
+				guests = append(guests, g)
+			}
+		}
+	}
+
+	section("Status")
+	statusColor := theme.Catppuccin.Green
+	if node.Status != "online" {
+		statusColor = theme.Catppuccin.Red
+	}
+	field("Status", node.Status, statusColor)
+	field("Guests", fmt.Sprintf("%d running of %d", running, total), text)
+
+	if d.err != nil {
+		section("Error")
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(queryProblem("node status", d.err), m.width-2)))
+	}
+	s := d.status
+	if s == nil {
+		if d.err == nil {
+			lines = append(lines, " loading...")
+		}
+		return lines
+	}
+
+	field("Uptime", formatUptime(s.Uptime), text)
+	field("CPU", fmt.Sprintf("%.1f%% of %d threads", s.CPU*100, s.CPUs), levelColor(s.CPU, 0.5, 0.8))
+	field("IO delay", fmt.Sprintf("%.1f%% of CPU time waiting for I/O", s.IOWait*100), levelColor(s.IOWait, ioWaitWarn, ioWaitCrit))
+	loadPerCPU := 0.0
+	if s.CPUs > 0 {
+		loadPerCPU = s.LoadAvg[0] / float64(s.CPUs)
+	}
+	field("Load average", fmt.Sprintf("%.2f  %.2f  %.2f  (1/5/15 min, %.2f per thread)", s.LoadAvg[0], s.LoadAvg[1], s.LoadAvg[2], loadPerCPU), levelColor(loadPerCPU, 0.7, 1))
+
+	section("Memory and disk")
+	field("Memory", formatUsage(s.MemUsed, s.MemTotal), levelColor(usageRatio(s.MemUsed, s.MemTotal), 0.5, 0.8))
+	if s.KSMShared > 0 {
+		field("KSM sharing", formatBytes(s.KSMShared), text)
+	} else {
+		field("KSM sharing", "none", theme.Catppuccin.Overlay0)
+	}
+	if s.SwapTotal > 0 {
+		field("Swap", formatUsage(s.SwapUsed, s.SwapTotal), levelColor(usageRatio(s.SwapUsed, s.SwapTotal), 0.1, 0.5))
+	} else {
+		field("Swap", "none", theme.Catppuccin.Overlay0)
+	}
+	field("Root FS", formatUsage(s.RootUsed, s.RootTotal), levelColor(usageRatio(s.RootUsed, s.RootTotal)*100, storageWarnPercent, storageCritPercent))
+
+	section("Pressure stall (1 min avg)")
+	if p := s.Pressure; p != nil {
+		field("CPU", fmt.Sprintf("some %.1f%%", p.CPUSome*100), levelColor(p.CPUSome, pressureWarn, pressureCrit))
+		field("IO", fmt.Sprintf("some %.1f%%  full %.1f%%", p.IOSome*100, p.IOFull*100), levelColor(p.IOSome, pressureWarn, pressureCrit))
+		field("Memory", fmt.Sprintf("some %.1f%%  full %.1f%%", p.MemorySome*100, p.MemoryFull*100), levelColor(p.MemorySome, pressureWarn, pressureCrit))
+	} else {
+		lines = append(lines, " "+greyStyle.Render("not reported by this node"))
+	}
+
+	if s.IOWait >= ioWaitWarn && len(guests) > 0 {
+		sort.SliceStable(guests, func(i, j int) bool {
+			return m.getDiskRateNumeric(guests[i]) > m.getDiskRateNumeric(guests[j])
+		})
+		if len(guests) > busyDisks {
+			guests = guests[:busyDisks]
+		}
+		section("Busiest guest disks")
+		for _, g := range guests {
+			field(truncate(fmt.Sprintf("%d %s", g.VMID, g.Name), 16), formatBytes(m.getDiskRateNumeric(g))+"/s", text)
+		}
+	}
+
+	section("Hardware")
+	field("CPU model", s.CPUModel, text)
+	if s.Sockets > 0 {
+		field("Topology", fmt.Sprintf("%d socket(s) x %d core(s), %d threads", s.Sockets, s.Cores, s.CPUs), text)
+	}
+	if s.CPUMHz > 0 {
+		field("Clock", fmt.Sprintf("%.0f MHz", s.CPUMHz), text)
+	}
+	if s.BootMode != "" {
+		boot := s.BootMode
+		if s.SecureBoot {
+			boot += " (secure boot)"
+		}
+		field("Boot mode", boot, text)
+	}
+
+	section("Software")
+	field("PVE version", s.PVEVersion, text)
+	field("Kernel", s.Kernel, text)
+
+	return lines
+}
+
+func (m Model) viewNodeDetail() string {
+	help := "esc:back | ↑↓/PgUp/PgDn:scroll | n:guests | q:quit"
+	if m.width < widthMedium {
+		help = "esc:back | ↑↓:scroll | q:quit"
+	}
+
+	title := fmt.Sprintf(" pvetop - node %s - refresh: 2s ", m.nodeDetail.node)
+	return m.renderLines(title, m.nodeDetailLines(), m.nodeDetail.scroll, help)
+}
@@ -0,0 +1,417 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const replicationRefresh = time.Minute
+
+type replicationMsg struct {
+	jobs       []models.ReplicationJob
+	nodeErrors map[string]error
+	err        error
+}
+
+type replicationScheduledMsg struct {
+	id  string
+	err error
+}
+
+type replicationLog struct {
+	job    models.ReplicationJob
+	lines  []string
+	err    error
+	scroll int
+	loaded bool
+}
+
+type replicationLogMsg struct {
+	id    string
+	lines []models.TaskLogLine
+	err   error
+}
+
+func (m Model) replicationDue() bool {
+	return !m.replLoading && time.Since(m.replAt) >= replicationRefresh
+}
+
+func (m Model) replicationUnused() bool {
+	return m.replJobs != nil && len(m.replJobs) == 0 && len(m.replNodeErrors) == 0
+}
+
+func (m Model) fetchReplication(ctx context.Context) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		result, err := client.GetReplication(ctx)
+		if result == nil {
+			return replicationMsg{err: err}
+		}
+		return replicationMsg{jobs: result.Jobs, nodeErrors: result.NodeErrors, err: err}
+	}
+}
+
+func (m *Model) updateReplication(msg replicationMsg) {
+	m.replLoading = false
+	if errors.Is(msg.err, context.Canceled) {
+		return
+	}
+	m.replAt = time.Now()
+	m.replNodeErrors = msg.nodeErrors
+	if msg.err != nil {
+		m.replErr = msg.err
+		return
+	}
+	m.replErr = nil
+	m.replJobs = msg.jobs
+	sort.SliceStable(m.replJobs, func(i, j int) bool {
+		fi, fj := replicationFailing(m.replJobs[i]), replicationFailing(m.replJobs[j])
+		if fi != fj {
+			return fi
+		}
+		return m.replJobs[i].Guest < m.replJobs[j].Guest
+	})
+
+	if m.replLog != nil {
+		for _, job := range m.replJobs {
+			if job.ID == m.replLog.job.ID {
+				log := *m.replLog
+				log.job = job
+				m.replLog = &log
+				break
+			}
+		}
+	}
+	if m.viewMode == viewReplication {
+		m.syncCursor()
+	}
+}
+
+func replicationFailing(job models.ReplicationJob) bool {
+	return job.FailCount > 0 || job.Error != ""
+}
+
+func (m Model) failingReplications() int {
+	failing := 0
+	for _, job := range m.replJobs {
+		if replicationFailing(job) {
+			failing++
+		}
+	}
+	return failing
+}
+
+func (m Model) replicationUnavailable() string {
+	if len(m.replNodeErrors) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("jobs on %s unavailable", strings.Join(errorNodes(m.replNodeErrors), ", "))
+}
+
+func (m Model) replicationKeys() []string {
+	keys := make([]string, len(m.replJobs))
+	for i, job := range m.replJobs {
+		keys[i] = job.ID
+	}
+	return keys
+}
+
+func (m Model) selectedReplication() (models.ReplicationJob, bool) {
+	if m.viewMode == viewReplicationLog && m.replLog != nil {
+		return m.replLog.job, true
+	}
+	if m.selectedRow < 0 || m.selectedRow >= len(m.replJobs) {
+		return models.ReplicationJob{}, false
+	}
+	return m.replJobs[m.selectedRow], true
+}
+
+func replicationLabel(job models.ReplicationJob) string {
+	return fmt.Sprintf("%s (%s → %s)", job.ID, job.Source, job.Target)
+}
+
+func formatUntil(ts int64) string {
+	if ts == 0 {
+		return "—"
+	}
+	d := time.Until(time.Unix(ts, 0))
+	if d <= 0 {
+		return "due"
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("in %ds", int(d.Seconds()))
+	}
+	return "in " + formatAge(d)
+}
+
+func replicationState(job models.ReplicationJob) (string, lipgloss.Color) {
+	switch {
+	case job.Running:
+		return "syncing", theme.Catppuccin.Yellow
+	case job.Disabled:
+		return "disabled", theme.Catppuccin.Overlay0
+	case job.Error != "":
+		return job.Error, theme.Catppuccin.Red
+	case job.FailCount > 0:
+		return "failed", theme.Catppuccin.Red
+	case job.LastSync == 0:
+		return "pending", theme.Catppuccin.Overlay0
+	}
+	return "OK", theme.Catppuccin.Green
+}
+
+func (m Model) formatReplicationRow(job models.ReplicationJob, names map[int]string) string {
+	lastSync := "never"
+	if job.LastSync > 0 {
+		lastSync = formatSince(job.LastSync)
+	}
+	row := fmt.Sprintf("%-8s %-16s %-8s %-8s %-9s %10s %8s %8s %5d ",
+		truncate(job.ID, 8),
+		truncate(names[job.Guest], 16),
+		truncate(job.Source, 8),
+		truncate(job.Target, 8),
+		truncate(job.Schedule, 9),
+		lastSync,
+		formatUntil(job.NextSync),
+		formatTaskDuration(time.Duration(job.Duration*float64(time.Second))),
+		job.FailCount,
+	)
+	state, color := replicationState(job)
+	remaining := m.width - len([]rune(row))
+	if remaining < 1 {
+		row = truncate(row, m.width)
+		state = ""
+	} else {
+		state = truncate(state, remaining)
+	}
+	if replicationFailing(job) {
+		return lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(row + state)
+	}
+	return row + lipgloss.NewStyle().Foreground(color).Render(state)
+}
+
+func (m Model) viewReplication() string {
+	headers := fmt.Sprintf("%-8s %-16s %-8s %-8s %-9s %10s %8s %8s %5s %s", "JOB", "GUEST", "SOURCE", "TARGET", "SCHEDULE", "LAST SYNC", "NEXT", "DURATION", "FAILS", "STATUS")
+
+	names := make(map[int]string, len(m.guests))
+	for _, guest := range m.guests {
+		names[guest.VMID] = guest.Name
+	}
+
+	var rows []string
+	for _, job := range m.replJobs {
+		rows = append(rows, m.formatReplicationRow(job, names))
+	}
+
+	failing := fmt.Sprintf("%d failing", m.failingReplications())
+	missing := m.replicationUnavailable()
+	if missing != "" {
+		failing += ", " + missing
+	}
+	title := fmt.Sprintf(" pvetop - replication (%d jobs, %s) - refresh: 2s ", len(m.replJobs), failing)
+	if m.width < widthLarge {
+		title = fmt.Sprintf(" pvetop replication (%s) ", failing)
+	}
+
+	var status, problem string
+	switch {
+	case m.replErr != nil:
+		problem = queryProblem("replication", m.replErr)
+	case missing != "":
+		problem = m.replicationNodeProblem()
+	case m.replJobs == nil:
+		status = "loading replication jobs..."
+	case len(m.replJobs) == 0:
+		status = "no replication jobs are configured"
+	}
+
+	help := "q:quit | ?:help | ↑↓:select | enter:log | s:sync now | tab:next view | n:guests"
+	if m.width < widthMedium {
+		help = "q:quit | enter:log | s:sync now"
+	}
+
+	return m.renderTable(tableView{
+		title:   title,
+		status:  status,
+		problem: problem,
+		columns: headers,
+		rows:    rows,
+		help:    help,
+	})
+}
+
+func (m Model) replicationNodeProblem() string {
+	names := errorNodes(m.replNodeErrors)
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = fmt.Sprintf("%s: %v", name, m.replNodeErrors[name])
+	}
+	return "replication jobs unavailable - " + strings.Join(problems, "; ")
+}
+
+func (m Model) requestReplicationNow() (Model, tea.Cmd) {
+	job, ok := m.selectedReplication()
+	if !ok {
+		m.setNotice("Select a replication job with ↑/↓ first")
+		return m, nil
+	}
+	if job.Running {
+		m.setNotice("Replication job %s is already running", job.ID)
+		return m, nil
+	}
+
+	client := m.client
+	m.confirm = &confirmDialog{
+		prompt: fmt.Sprintf("Run replication job %s now?", replicationLabel(job)),
+		onYes: func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+			defer cancel()
+
+			err := client.ScheduleReplication(ctx, job.Source, job.ID)
+			return replicationScheduledMsg{id: job.ID, err: err}
+		},
+	}
+	return m, nil
+}
+
+func (m Model) updateReplicationScheduled(msg replicationScheduledMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setNotice("Scheduling replication job %s failed: %v", msg.id, msg.err)
+		return m, nil
+	}
+	m.setNotice("Replication job %s scheduled, it starts within a minute", msg.id)
+	return m, m.fetchReplication(context.Background())
+}
+
+func (m Model) updateReplicationKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Open):
+		updated, cmd := m.openReplicationLog()
+		return updated, cmd, true
+	case msg.String() == "s":
+		updated, cmd := m.requestReplicationNow()
+		return updated, cmd, true
+	}
+	return m, nil, false
+}
+
+func (m Model) openReplicationLog() (Model, tea.Cmd) {
+	job, ok := m.selectedReplication()
+	if !ok {
+		return m, nil
+	}
+
+	m.replLog = &replicationLog{job: job}
+	m.viewMode = viewReplicationLog
+	return m, m.fetchReplicationLog(context.Background())
+}
+
+func (m Model) fetchReplicationLog(ctx context.Context) tea.Cmd {
+	if m.replLog == nil {
+		return nil
+	}
+	client := m.client
+	job := m.replLog.job
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		lines, err := client.GetReplicationLog(ctx, job.Source, job.ID)
+		return replicationLogMsg{id: job.ID, lines: lines, err: err}
+	}
+}
+
+func (m *Model) updateReplicationLog(msg replicationLogMsg) {
+	if m.replLog == nil || m.replLog.job.ID != msg.id {
+		return
+	}
+	log := *m.replLog
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			log.err = msg.err
+			m.replLog = &log
+		}
+		return
+	}
+
+	log.err = nil
+	log.loaded = true
+	log.lines = log.lines[:0:0]
+	for _, line := range msg.lines {
+		log.lines = append(log.lines, line.T)
+	}
+	m.replLog = &log
+}
+
+func (m Model) updateReplicationLogKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Open):
+		m.viewMode = viewReplication
+		m.replLog = nil
+		m.syncCursor()
+		return m, nil, true
+	case msg.String() == "s":
+		updated, cmd := m.requestReplicationNow()
+		return updated, cmd, true
+	}
+
+	scroll, handled := m.scrollLines(msg, m.replLog.scroll, len(m.replLog.lines))
+	if !handled {
+		return m, nil, false
+	}
+	log := *m.replLog
+	log.scroll = scroll
+	m.replLog = &log
+	return m, nil, true
+}
+
+func replicationLogColor(line string) lipgloss.Color {
+	switch {
+	case strings.Contains(line, "with error"), strings.Contains(line, "failed"):
+		return theme.Catppuccin.Red
+	case strings.HasSuffix(line, "end replication job"):
+		return theme.Catppuccin.Green
+	}
+	return theme.Catppuccin.Text
+}
+
+func (m Model) viewReplicationLog() string {
+	log := m.replLog
+	state, _ := replicationState(log.job)
+	if replicationFailing(log.job) {
+		state = fmt.Sprintf("%d failures", log.job.FailCount)
+	}
+	title := fmt.Sprintf(" pvetop - replication log: %s, %s ", replicationLabel(log.job), state)
+
+	var lines []string
+	switch {
+	case log.err != nil:
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Red).Render(truncate(queryProblem("log", log.err), m.width-1)), "")
+	case !log.loaded:
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render("loading replication log..."))
+	case len(log.lines) == 0:
+		lines = append(lines, " "+lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render("the job has not run yet"))
+	}
+	for _, line := range log.lines {
+		lines = append(lines, lipgloss.NewStyle().Foreground(replicationLogColor(line)).Render(truncate(line, m.width)))
+	}
+
+	help := "esc:back | ↑↓/PgUp/PgDn:scroll | s:sync now | q:quit"
+	if m.width < widthMedium {
+		help = "esc:back | ↑↓:scroll | q:quit"
+	}
+	return m.renderLines(title, lines, log.scroll, help)
+}
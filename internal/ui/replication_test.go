@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type slowReplicationSource struct {
+	*api.StaticSource
+	release chan struct{}
+}
+
+func (s slowReplicationSource) GetReplication(ctx context.Context) (*api.ReplicationResult, error) {
+	select {
+	case <-s.release:
+		return &api.ReplicationResult{Jobs: []models.ReplicationJob{{ID: "100-0", Guest: 100, Source: "pve1", Target: "pve2"}}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func runBatch(cmd tea.Cmd) <-chan tea.Msg {
+	msgs := make(chan tea.Msg, 16)
+	go func() {
+		msg := cmd()
+		batch, ok := msg.(tea.BatchMsg)
+		if !ok {
+			msgs <- msg
+			return
+		}
+		for _, c := range batch {
+			if c != nil {
+				go func(c tea.Cmd) { msgs <- c() }(c)
+			}
+		}
+	}()
+	return msgs
+}
+
+func TestReplicationFetchOutlastsTick(t *testing.T) {
+	src := slowReplicationSource{api.NewStaticSource(testResources(2)), make(chan struct{})}
+	var tm tea.Model = NewModel(src)
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	tm, _ = tm.Update(tickMsg{})
+
+	m := tm.(Model)
+	tm, cmd := tm.Update(m.fetchData(context.Background())())
+	if cmd == nil {
+		t.Fatal("no follow-up fetches after the first refresh")
+	}
+	msgs := runBatch(cmd)
+
+	// The next tick cancels the refresh that started the replication fetch.
+	tm, _ = tm.Update(tickMsg{})
+	close(src.release)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-msgs:
+			repl, ok := msg.(replicationMsg)
+			if !ok {
+				continue
+			}
+			tm, _ = tm.Update(repl)
+			m = tm.(Model)
+			if m.replErr != nil || len(m.replJobs) != 1 {
+				t.Fatalf("jobs %v, error %v; want the job to load", m.replJobs, m.replErr)
+			}
+			if m.replicationDue() {
+				t.Error("replication is due again right after loading")
+			}
+			return
+		case <-timeout:
+			t.Fatal("the replication fetch never finished")
+		}
+	}
+}
+
+func TestReplicationNodeErrors(t *testing.T) {
+	m := newTestModel(t, testResources(2), 160, 30)
+	m.updateReplication(replicationMsg{
+		jobs:       []models.ReplicationJob{{ID: "100-0", Guest: 100, Source: "pve1", Target: "pve2", FailCount: 1}},
+		nodeErrors: map[string]error{"pve2": errors.New("node is offline")},
+	})
+	if m.replicationUnused() {
+		t.Error("the view is skipped although a node did not answer")
+	}
+
+	m.viewMode = viewReplication
+	view := ansiEscape.ReplaceAllString(m.View(), "")
+	for _, want := range []string{"1 failing, jobs on pve2 unavailable", "pve2: node is offline"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("replication view does not show %q:\n%s", want, view)
+		}
+	}
+
+	m.viewMode = viewGuests
+	if view := ansiEscape.ReplaceAllString(m.View(), ""); !strings.Contains(view, "1 replication job failing, replication jobs on pve2 unavailable") {
+		t.Errorf("guest header does not name the missing node:\n%s", view)
+	}
+
+	m.updateReplication(replicationMsg{jobs: []models.ReplicationJob{}})
+	if !m.replicationUnused() || m.replicationUnavailable() != "" {
+		t.Error("the node error outlived a complete refresh")
+	}
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/metrics"
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+const (
+	refreshInterval  = 2 * time.Second
+	defaultRetention = 30 * time.Minute
+	rateWindow       = 10 * time.Second
+)
+
+type statistic int
+
+const (
+	statMin statistic = iota
+	statAvg
+	statMax
+	statP95
+)
+
+var statsColumns = map[column]statistic{
+	colCPUMin: statMin,
+	colCPUAvg: statAvg,
+	colCPUMax: statMax,
+	colCPUP95: statP95,
+}
+
+var nodeStatsColumns = map[nodeColumn]statistic{
+	nodeColCPUMin: statMin,
+	nodeColCPUAvg: statAvg,
+	nodeColCPUMax: statMax,
+	nodeColCPUP95: statP95,
+}
+
+func statsValue(stats metrics.Stats, which statistic) float64 {
+	switch which {
+	case statMin:
+		return stats.Min
+	case statAvg:
+		return stats.Avg
+	case statMax:
+		return stats.Max
+	default:
+		return stats.P95
+	}
+}
+
+func (m *Model) SetRetention(retention time.Duration) {
+	m.samples = metrics.NewHistory(retention, refreshInterval)
+}
+
+func (m *Model) recordSamples(now time.Time) {
+	keep := make(map[string]bool)
+	for _, guest := range m.guests {
+		key := guestHistoryKey(guest)
+		keep[key] = true
+		if m.isStale(guest.Node) {
+			continue
+		}
+		m.samples.Record(key, metrics.Sample{
+			Time:      now,
+			CPU:       guest.CPU,
+			Mem:       guest.Mem,
+			MaxMem:    guest.MaxMem,
+			DiskRead:  guest.DiskRead,
+			DiskWrite: guest.DiskWrite,
+			NetIn:     guest.NetIn,
+			NetOut:    guest.NetOut,
+		})
+	}
+	for _, node := range m.nodes {
+		key := nodeHistoryKey(node.Node)
+		keep[key] = true
+		if node.Status != "online" {
+			continue
+		}
+		m.samples.Record(key, metrics.Sample{Time: now, CPU: node.CPU, Mem: node.Mem, MaxMem: node.MaxMem})
+	}
+	m.samples.Retain(keep)
+}
+
+func sampleValues(ring *metrics.Ring, which metric) []float64 {
+	if which == metricDisk || which == metricNet {
+		rates := ring.Rates()
+		values := make([]float64, len(rates))
+		for i, rate := range rates {
+			values[i] = rate.Disk
+			if which == metricNet {
+				values[i] = rate.Net
+			}
+		}
+		return values
+	}
+
+	values := make([]float64, ring.Len())
+	for i := range values {
+		sample := ring.At(i)
+		if which == metricCPU {
+			values[i] = sample.CPU * 100
+		} else if sample.MaxMem > 0 {
+			values[i] = float64(sample.Mem) / float64(sample.MaxMem) * 100
+		}
+	}
+	return values
+}
+
+func (m Model) hasSamples(key string) bool {
+	ring := m.samples.Get(key)
+	return ring != nil && ring.Len() > sparkWidth
+}
+
+func (m Model) hasTrends() bool {
+	if len(m.history) > 0 {
+		return true
+	}
+	for _, guest := range m.guests {
+		if m.hasSamples(guestHistoryKey(guest)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Model) guestTrend(guest models.Guest, which metric) []float64 {
+	key := guestHistoryKey(guest)
+	if m.hasSamples(key) {
+		return sampleValues(m.samples.Get(key), which)
+	}
+	return metricValues(m.history[key], which)
+}
+
+func (m Model) nodeTrend(node string, which metric) []float64 {
+	key := nodeHistoryKey(node)
+	if !m.hasSamples(key) {
+		return metricValues(m.nodeHistory(node), which)
+	}
+	if which == metricCPU || which == metricMem {
+		return sampleValues(m.samples.Get(key), which)
+	}
+
+	sum := make([]float64, m.samples.Get(key).Len()-1)
+	for _, guest := range m.guests {
+		if guest.Node != node {
+			continue
+		}
+		ring := m.samples.Get(guestHistoryKey(guest))
+		if ring == nil {
+			continue
+		}
+		values := sampleValues(ring, which)
+		for i := 1; i <= len(values) && i <= len(sum); i++ {
+			sum[len(sum)-i] += values[len(values)-i]
+		}
+	}
+	return sum
+}
+
+func (m Model) windowStats(key string, which metric) (metrics.Stats, bool) {
+	ring := m.samples.Get(key)
+	if ring == nil || ring.Len() < 2 {
+		return metrics.Stats{}, false
+	}
+	return metrics.Summarize(sampleValues(ring, which)), true
+}
+
+func (m Model) guestRate(guest models.Guest) metrics.Rate {
+	ring := m.samples.Get(guestHistoryKey(guest))
+	if ring == nil {
+		return metrics.Rate{}
+	}
+	return ring.Rate(rateWindow)
+}
+
+func formatRetention(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
@@ -0,0 +1,406 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const currentSnapshot = "current"
+
+type snapshotPanel struct {
+	guest     models.Guest
+	back      viewMode
+	snapshots []models.Snapshot
+	err       error
+	form      *snapshotForm
+}
+
+type snapshotForm struct {
+	name        textinput.Model
+	description textinput.Model
+	vmstate     bool
+	focus       int
+}
+
+type snapshotsMsg struct {
+	vmid      int
+	snapshots []models.Snapshot
+	err       error
+}
+
+type snapshotRow struct {
+	snapshot models.Snapshot
+	prefix   string
+}
+
+func (m Model) openSnapshots() (Model, tea.Cmd) {
+	guest, ok := m.selectedGuest()
+	if !ok {
+		m.setNotice("Select a guest with ↑/↓ first")
+		return m, nil
+	}
+
+	m.snapshots = &snapshotPanel{guest: guest, back: m.viewMode}
+	m.viewMode = viewSnapshots
+	delete(m.selectedKeys, viewSnapshots)
+	m.scrollOffset = 0
+	m.syncCursor()
+	return m, m.fetchSnapshots(context.Background())
+}
+
+func (m Model) closeSnapshots() Model {
+	m.viewMode = m.snapshots.back
+	if m.viewMode == viewGuestDetail && m.detail == nil {
+		m.viewMode = viewGuests
+	}
+	m.snapshots = nil
+	m.scrollOffset = 0
+	m.syncCursor()
+	return m
+}
+
+func (m Model) fetchSnapshots(ctx context.Context) tea.Cmd {
+	if m.snapshots == nil {
+		return nil
+	}
+	client := m.client
+	guest := m.snapshots.guest
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		snapshots, err := client.GetSnapshots(ctx, guest.Node, guest.Type, guest.VMID)
+		return snapshotsMsg{vmid: guest.VMID, snapshots: snapshots, err: err}
+	}
+}
+
+func (m *Model) updateSnapshots(msg snapshotsMsg) {
+	if m.snapshots == nil || m.snapshots.guest.VMID != msg.vmid {
+		return
+	}
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			m.snapshots.err = msg.err
+		}
+		return
+	}
+	m.snapshots.err = nil
+	m.snapshots.snapshots = msg.snapshots
+	if m.viewMode == viewSnapshots {
+		m.syncCursor()
+	}
+}
+
+func snapshotTree(snapshots []models.Snapshot) []snapshotRow {
+	known := make(map[string]bool)
+	for _, snap := range snapshots {
+		known[snap.Name] = true
+	}
+	children := make(map[string][]models.Snapshot)
+	for _, snap := range snapshots {
+		parent := snap.Parent
+		if !known[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], snap)
+	}
+	for _, list := range children {
+		sort.SliceStable(list, func(i, j int) bool {
+			if (list[i].Name == currentSnapshot) != (list[j].Name == currentSnapshot) {
+				return list[j].Name == currentSnapshot
+			}
+			return list[i].SnapTime < list[j].SnapTime
+		})
+	}
+
+	var rows []snapshotRow
+	var walk func(parent, prefix string, root bool)
+	walk = func(parent, prefix string, root bool) {
+		list := children[parent]
+		for i, snap := range list {
+			last := i == len(list)-1
+			connector, indent := "├─ ", "│  "
+			if last {
+				connector, indent = "└─ ", "   "
+			}
+			if root {
+				connector, indent = "", ""
+			}
+			rows = append(rows, snapshotRow{snapshot: snap, prefix: prefix + connector})
+			walk(snap.Name, prefix+indent, false)
+		}
+	}
+	walk("", "", true)
+	return rows
+}
+
+func (m Model) snapshotKeys() []string {
+	if m.snapshots == nil {
+		return nil
+	}
+	var keys []string
+	for _, row := range snapshotTree(m.snapshots.snapshots) {
+		keys = append(keys, row.snapshot.Name)
+	}
+	return keys
+}
+
+func (m Model) selectedSnapshot() (models.Snapshot, bool) {
+	rows := snapshotTree(m.snapshots.snapshots)
+	if m.selectedRow < 0 || m.selectedRow >= len(rows) {
+		return models.Snapshot{}, false
+	}
+	return rows[m.selectedRow].snapshot, true
+}
+
+func (m Model) updateSnapshotKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.snapshots.form != nil {
+		updated, cmd := m.updateSnapshotForm(msg)
+		return updated, cmd, true
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		return m.closeSnapshots(), nil, true
+	case msg.String() == "c":
+		m.snapshots = m.snapshots.withForm(newSnapshotForm())
+		return m, textinput.Blink, true
+	case msg.String() == "r":
+		updated, cmd := m.requestSnapshotAction("rollback")
+		return updated, cmd, true
+	case msg.String() == "d":
+		updated, cmd := m.requestSnapshotAction("delete")
+		return updated, cmd, true
+	}
+	return m, nil, false
+}
+
+func (p *snapshotPanel) withForm(form *snapshotForm) *snapshotPanel {
+	panel := *p
+	panel.form = form
+	return &panel
+}
+
+func newSnapshotForm() *snapshotForm {
+	name := textinput.New()
+	name.Placeholder = "pre_maintenance"
+	name.CharLimit = 40
+	name.Focus()
+
+	description := textinput.New()
+	description.CharLimit = 200
+
+	return &snapshotForm{name: name, description: description}
+}
+
+func (m Model) updateSnapshotForm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	form := *m.snapshots.form
+	fields := 2
+	if m.snapshots.guest.Type == "qemu" && m.snapshots.guest.Status == "running" {
+		fields = 3
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.snapshots = m.snapshots.withForm(nil)
+		m.setNotice("Cancelled")
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(form.name.Value())
+		if name == "" {
+			m.setNotice("Enter a snapshot name")
+			return m, nil
+		}
+		guest := m.snapshots.guest
+		description := strings.TrimSpace(form.description.Value())
+		label := fmt.Sprintf("Snapshot %s of %s", name, guestLabel(guest))
+		prompt := label
+		if form.vmstate {
+			prompt += " including RAM"
+		}
+		m.snapshots = m.snapshots.withForm(nil)
+		m.confirm = &confirmDialog{
+			prompt: prompt + "?",
+			onYes: m.runSnapshotAction(guest, label, func(ctx context.Context) (string, error) {
+				return m.client.CreateSnapshot(ctx, guest.Node, guest.Type, guest.VMID, name, description, form.vmstate)
+			}),
+		}
+		return m, nil
+	case "tab", "down":
+		form.focus = (form.focus + 1) % fields
+	case "shift+tab", "up":
+		form.focus = (form.focus + fields - 1) % fields
+	case " ":
+		if form.focus == 2 {
+			form.vmstate = !form.vmstate
+			m.snapshots = m.snapshots.withForm(&form)
+			return m, nil
+		}
+	}
+
+	form.name.Blur()
+	form.description.Blur()
+	var cmd tea.Cmd
+	switch form.focus {
+	case 0:
+		form.name.Focus()
+		form.name, cmd = form.name.Update(msg)
+	case 1:
+		form.description.Focus()
+		form.description, cmd = form.description.Update(msg)
+	}
+	m.snapshots = m.snapshots.withForm(&form)
+	return m, cmd
+}
+
+func (m Model) requestSnapshotAction(action string) (Model, tea.Cmd) {
+	snap, ok := m.selectedSnapshot()
+	if !ok || snap.Name == currentSnapshot {
+		m.setNotice("Select a snapshot with ↑/↓ first")
+		return m, nil
+	}
+
+	guest := m.snapshots.guest
+	client := m.client
+	var label, prompt string
+	var run func(ctx context.Context) (string, error)
+	if action == "rollback" {
+		label = fmt.Sprintf("Rollback %s to %s", guestLabel(guest), snap.Name)
+		prompt = label + ", discarding all changes since"
+		if guest.Type == "qemu" && !snap.VMState {
+			prompt += " and stopping the VM"
+		}
+		run = func(ctx context.Context) (string, error) {
+			return client.RollbackSnapshot(ctx, guest.Node, guest.Type, guest.VMID, snap.Name)
+		}
+	} else {
+		label = fmt.Sprintf("Delete snapshot %s of %s", snap.Name, guestLabel(guest))
+		prompt = label
+		run = func(ctx context.Context) (string, error) {
+			return client.DeleteSnapshot(ctx, guest.Node, guest.Type, guest.VMID, snap.Name)
+		}
+	}
+
+	m.confirm = &confirmDialog{
+		prompt: prompt + "?",
+		onYes:  m.runSnapshotAction(guest, label, run),
+	}
+	return m, nil
+}
+
+func (m Model) runSnapshotAction(guest models.Guest, label string, run func(ctx context.Context) (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		upid, err := run(ctx)
+		return actionStartedMsg{node: guest.Node, upid: upid, label: label, err: err}
+	}
+}
+
+func formatSnapshotRow(row snapshotRow, width int) string {
+	snap := row.snapshot
+	if snap.Name == currentSnapshot {
+		hereStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Green).Bold(true)
+		return hereStyle.Render(truncate(row.prefix+"● NOW", width))
+	}
+
+	name := fmt.Sprintf("%-32s", truncate(row.prefix+snap.Name, 32))
+	date := time.Unix(snap.SnapTime, 0).Format("2006-01-02 15:04:05")
+	ram := "no "
+	if snap.VMState {
+		ram = "yes"
+	}
+	line := fmt.Sprintf("%s %-19s %-4s", name, date, ram)
+	if remaining := width - len([]rune(line)) - 1; remaining > 0 && snap.Description != "" {
+		description := strings.ReplaceAll(strings.TrimSpace(snap.Description), "\n", " ")
+		line += " " + lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Render(truncate(description, remaining))
+	}
+	return line
+}
+
+func (m Model) viewSnapshots() string {
+	panel := m.snapshots
+	if panel.form != nil {
+		return m.viewSnapshotForm()
+	}
+
+	var rows []string
+	tree := snapshotTree(panel.snapshots)
+	for _, row := range tree {
+		rows = append(rows, formatSnapshotRow(row, m.width))
+	}
+
+	count := len(tree)
+	if count > 0 {
+		count--
+	}
+	title := fmt.Sprintf(" pvetop - snapshots of %s on %s (%d) ", guestLabel(panel.guest), panel.guest.Node, count)
+
+	var status, problem string
+	if panel.err != nil {
+		problem = queryProblem("snapshot", panel.err)
+	} else if panel.snapshots == nil {
+		status = "loading snapshots..."
+	}
+
+	help := "esc:back | ↑↓:select | c:create | r:rollback | d:delete | q:quit"
+	if m.width < widthMedium {
+		help = "esc:back | c:create | r:rollback | d:delete"
+	}
+
+	return m.renderTable(tableView{
+		title:   title,
+		status:  status,
+		problem: problem,
+		columns: fmt.Sprintf("%-32s %-19s %-4s %s", "NAME", "DATE", "RAM", "DESCRIPTION"),
+		rows:    rows,
+		help:    help,
+	})
+}
+
+func (m Model) viewSnapshotForm() string {
+	panel := m.snapshots
+	form := panel.form
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1).Width(14)
+	focusStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue).Bold(true).Width(14)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+
+	label := func(text string, focus int) string {
+		if form.focus == focus {
+			return focusStyle.Render(text)
+		}
+		return labelStyle.Render(text)
+	}
+
+	s := titleStyle.Render(truncate(fmt.Sprintf(" pvetop - new snapshot of %s on %s ", guestLabel(panel.guest), panel.guest.Node), m.width)) + "\n\n"
+	s += " " + label("Name", 0) + form.name.View() + "\n"
+	s += " " + label("Description", 1) + form.description.View() + "\n"
+	if panel.guest.Type == "qemu" && panel.guest.Status == "running" {
+		check := "[ ]"
+		if form.vmstate {
+			check = "[x]"
+		}
+		s += " " + label("Include RAM", 2) + check + "\n"
+	}
+	s += "\n" + descStyle.Render(" tab:next field | space:toggle RAM | enter:create | esc:cancel")
+	return s
+}
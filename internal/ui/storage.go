@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	storageWarnPercent = 75
+	storageCritPercent = 90
+)
+
+func (m *Model) updateStorage(storage []models.Storage, err error) {
+	if err != nil {
+		m.storageErr = err
+		return
+	}
+	m.storageErr = nil
+	m.storage = append([]models.Storage{}, storage...)
+	sort.SliceStable(m.storage, func(i, j int) bool {
+		return storageUsage(m.storage[i]) > storageUsage(m.storage[j])
+	})
+	if m.viewMode == viewStorage {
+		m.syncCursor()
+	}
+}
+
+func storageKey(st models.Storage) string {
+	return st.Node + "/" + st.Storage
+}
+
+func (m Model) storageKeys() []string {
+	keys := make([]string, len(m.storage))
+	for i, st := range m.storage {
+		keys[i] = storageKey(st)
+	}
+	return keys
+}
+
+func storageUsage(st models.Storage) float64 {
+	if !st.Active || st.Total <= 0 {
+		return -1
+	}
+	return float64(st.Used) / float64(st.Total) * 100
+}
+
+func (m Model) formatStorageRow(st models.Storage, wide bool) string {
+	greyStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Overlay0)
+
+	var state string
+	var stateColor lipgloss.Color
+	switch {
+	case !st.Enabled:
+		state, stateColor = "disabled", theme.Catppuccin.Overlay0
+	case !st.Active:
+		state, stateColor = "inactive", theme.Catppuccin.Red
+	default:
+		state, stateColor = "active", theme.Catppuccin.Green
+	}
+
+	usage := storageUsage(st)
+	useText := greyStyle.Render(fmt.Sprintf("%6s", "—"))
+	sizeText := greyStyle.Render(fmt.Sprintf("%17s", "—"))
+	if usage >= 0 {
+		usageColor := theme.Catppuccin.Green
+		if usage >= storageCritPercent {
+			usageColor = theme.Catppuccin.Red
+		} else if usage >= storageWarnPercent {
+			usageColor = theme.Catppuccin.Yellow
+		}
+		useText = lipgloss.NewStyle().Foreground(usageColor).Bold(usage >= storageCritPercent).Render(fmt.Sprintf("%6.1f", usage))
+		sizeText = fmt.Sprintf("%17s", formatBytesShort(st.Used)+" / "+formatBytesShort(st.Total))
+	}
+
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Text)
+	if !st.Enabled {
+		nameStyle = greyStyle
+	}
+	parts := []string{
+		nameStyle.Render(fmt.Sprintf("%-16s", truncate(st.Storage, 16))),
+		fmt.Sprintf("%-10s", truncate(st.Node, 10)),
+	}
+	if wide {
+		shared := "no"
+		if st.Shared {
+			shared = "yes"
+		}
+		parts = append(parts,
+			lipgloss.NewStyle().Foreground(theme.Catppuccin.Blue).Render(fmt.Sprintf("%-9s", truncate(st.Type, 9))),
+			fmt.Sprintf("%-6s", shared),
+		)
+	}
+	parts = append(parts, useText)
+	if wide {
+		parts = append(parts, sizeText)
+	}
+	parts = append(parts, lipgloss.NewStyle().Foreground(stateColor).Render(fmt.Sprintf("%-8s", state)))
+	if wide && m.width > storageWideWidth {
+		parts = append(parts, truncate(strings.ReplaceAll(st.Content, ",", " "), m.width-storageWideWidth-1))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+const storageWideWidth = 16 + 1 + 10 + 1 + 9 + 1 + 6 + 1 + 6 + 1 + 17 + 1 + 8
+
+func (m Model) viewStorage() string {
+	wide := m.width >= storageWideWidth
+
+	var headers []string
+	headers = append(headers, fmt.Sprintf("%-16s", "STORAGE"), fmt.Sprintf("%-10s", "NODE"))
+	if wide {
+		headers = append(headers, fmt.Sprintf("%-9s", "TYPE"), fmt.Sprintf("%-6s", "SHARED"))
+	}
+	headers = append(headers, fmt.Sprintf("%6s", "USED%"))
+	if wide {
+		headers = append(headers, fmt.Sprintf("%17s", "USED / TOTAL"))
+	}
+	headers = append(headers, fmt.Sprintf("%-8s", "STATE"))
+	if wide && m.width > storageWideWidth {
+		headers = append(headers, "CONTENT")
+	}
+
+	var rows []string
+	critical := 0
+	for _, st := range m.storage {
+		if storageUsage(st) >= storageCritPercent {
+			critical++
+		}
+		rows = append(rows, m.formatStorageRow(st, wide))
+	}
+
+	missing := ""
+	if nodes := errorNodes(m.nodeErrors); len(nodes) > 0 {
+		missing = fmt.Sprintf(", storage on %s unavailable", strings.Join(nodes, ", "))
+	}
+	title := fmt.Sprintf(" pvetop - storage (%d entries, %d above %d%%%s) - refresh: 2s ", len(m.storage), critical, storageCritPercent, missing)
+	if m.width < widthLarge {
+		title = fmt.Sprintf(" pvetop storage (%d, %d full%s) ", len(m.storage), critical, missing)
+	}
+
+	var status, problem string
+	if m.storageErr != nil {
+		problem = queryProblem("storage", m.storageErr)
+	} else if m.storage == nil {
+		status = "loading storage..."
+	}
+
+	help := "q:quit | ?:help | ↑↓:select | tab:next view | n:guests"
+	if m.width < widthMedium {
+		help = "q:quit | tab:next view"
+	}
+
+	return m.renderTable(tableView{
+		title:   title,
+		status:  status,
+		problem: problem,
+		columns: strings.Join(headers, " "),
+		rows:    rows,
+		help:    help,
+	})
+}
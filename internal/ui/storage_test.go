@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+)
+
+func TestStorageFromClusterResources(t *testing.T) {
+	res := testResources(2)
+	res.Nodes[1].Status = "offline"
+	res.Storage = []models.Storage{
+		{Storage: "local", Node: "pve1", Type: "dir", Status: "available", Enabled: true, Active: true, Used: 95, Total: 100},
+		{Storage: "local", Node: "pve2", Type: "dir", Status: "unknown", Enabled: true},
+	}
+
+	m := newTestModel(t, res, 120, 30)
+	if len(m.storage) != 2 || m.storageErr != nil {
+		t.Fatalf("storage = %v, error %v; want both entries from the refresh", m.storage, m.storageErr)
+	}
+
+	m, _ = m.switchView(viewStorage)
+	view := ansiEscape.ReplaceAllString(m.View(), "")
+	if !strings.Contains(view, "1 above 90%, storage on pve2 unavailable") {
+		t.Errorf("storage header does not name the offline node:\n%s", view)
+	}
+}
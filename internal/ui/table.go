@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/berocorpdotnet/pvetop/internal/api"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type tableView struct {
+	title   string
+	status  string
+	problem string
+	columns string
+	rows    []string
+	help    string
+}
+
+func queryProblem(what string, err error) string {
+	if errors.Is(err, api.ErrNotRecorded) {
+		return what + " unavailable: " + err.Error()
+	}
+	return fmt.Sprintf("%s query failed: %v", what, err)
+}
+
+func errorNodes(errs map[string]error) []string {
+	var names []string
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m Model) renderTable(t tableView) string {
+	var s string
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	s += headerStyle.Render(truncate(t.title, m.width))
+
+	if t.problem != "" {
+		problemStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Red)
+		s += "\n" + problemStyle.Render(truncate(" "+t.problem, m.width)) + "\n"
+	} else if t.status != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Subtext1)
+		s += "\n" + statusStyle.Render(truncate(" "+t.status, m.width)) + "\n"
+	} else {
+		s += "\n" + m.renderNodeErrors() + "\n"
+	}
+
+	colHeaderStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Subtext1).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	s += colHeaderStyle.Render(truncate(t.columns, m.width)) + "\n"
+
+	contentHeight := m.pageSize()
+	startIdx := m.scrollOffset
+	if startIdx > len(t.rows) {
+		startIdx = len(t.rows)
+	}
+	endIdx := startIdx + contentHeight
+	if endIdx > len(t.rows) {
+		endIdx = len(t.rows)
+	}
+
+	rowStyle := lipgloss.NewStyle().Width(m.width)
+	for i, row := range t.rows[startIdx:endIdx] {
+		if startIdx+i == m.selectedRow {
+			s += highlightRow(row, m.width) + "\n"
+			continue
+		}
+		s += rowStyle.Render(row) + "\n"
+	}
+
+	for i := endIdx - startIdx; i < contentHeight; i++ {
+		s += "\n"
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Catppuccin.Subtext1).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	s += m.renderStatusBar() + "\n" + helpStyle.Render(truncate(t.help, m.width))
+
+	return s
+}
+
+func (m Model) renderLines(title string, lines []string, scroll int, help string) string {
+	var s string
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	s += headerStyle.Render(truncate(title, m.width))
+	s += "\n\n"
+
+	contentHeight := m.height - 4
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+	start := scroll
+	if start > len(lines) {
+		start = len(lines)
+	}
+	end := start + contentHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	for _, line := range lines[start:end] {
+		s += line + "\n"
+	}
+	for i := end - start; i < contentHeight; i++ {
+		s += "\n"
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Catppuccin.Subtext1).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+	s += m.renderStatusBar() + "\n" + helpStyle.Render(truncate(help, m.width))
+	return s
+}
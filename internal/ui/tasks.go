@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/berocorpdotnet/pvetop/internal/models"
+	"github.com/berocorpdotnet/pvetop/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type taskListMsg struct {
+	tasks []models.TaskStatus
+	err   error
+}
+
+type taskLog struct {
+	task     models.TaskStatus
+	lines    []string
+	err      error
+	scroll   int
+	follow   bool
+	complete bool
+}
+
+type taskLogMsg struct {
+	upid  string
+	start int
+	lines []models.TaskLogLine
+	final bool
+	err   error
+}
+
+func (m Model) fetchTasks(ctx context.Context) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		tasks, err := client.GetTasks(ctx)
+		return taskListMsg{tasks: tasks, err: err}
+	}
+}
+
+func (m *Model) updateTasks(msg taskListMsg) {
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			m.taskListErr = msg.err
+		}
+		return
+	}
+	m.taskListErr = nil
+	m.taskList = msg.tasks
+	sort.SliceStable(m.taskList, func(i, j int) bool {
+		ri, rj := m.taskList[i].Status == "running", m.taskList[j].Status == "running"
+		if ri != rj {
+			return ri
+		}
+		return m.taskList[i].StartTime > m.taskList[j].StartTime
+	})
+
+	if m.taskLog != nil {
+		for _, task := range m.taskList {
+			if task.UPID == m.taskLog.task.UPID {
+				m.taskLog.task = task
+				break
+			}
+		}
+	}
+	if m.viewMode == viewTasks {
+		m.syncCursor()
+	}
+}
+
+func (m Model) taskKeys() []string {
+	keys := make([]string, len(m.taskList))
+	for i, task := range m.taskList {
+		keys[i] = task.UPID
+	}
+	return keys
+}
+
+func taskDuration(task models.TaskStatus) time.Duration {
+	end := time.Now()
+	if task.EndTime > 0 {
+		end = time.Unix(task.EndTime, 0)
+	}
+	d := end.Sub(time.Unix(task.StartTime, 0))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func formatTaskDuration(d time.Duration) string {
+	seconds := int64(d.Seconds())
+	switch {
+	case seconds < 60:
+		return fmt.Sprintf("%ds", seconds)
+	case seconds < 3600:
+		return fmt.Sprintf("%dm%02ds", seconds/60, seconds%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", seconds/3600, (seconds%3600)/60)
+	}
+}
+
+func formatTaskStart(started int64) string {
+	t := time.Unix(started, 0)
+	now := time.Now()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("Jan 02 15:04")
+}
+
+func taskResult(task models.TaskStatus) (string, lipgloss.Color) {
+	switch {
+	case task.Status == "running":
+		return "running", theme.Catppuccin.Yellow
+	case task.ExitStatus == "OK":
+		return "OK", theme.Catppuccin.Green
+	case strings.HasPrefix(task.ExitStatus, "WARNINGS"):
+		return task.ExitStatus, theme.Catppuccin.Peach
+	case task.ExitStatus == "":
+		return "unknown", theme.Catppuccin.Overlay0
+	default:
+		return task.ExitStatus, theme.Catppuccin.Red
+	}
+}
+
+func formatTaskRow(task models.TaskStatus, width int) string {
+	result, color := taskResult(task)
+	row := fmt.Sprintf("%-12s %-10s %-10s %-8s %-14s %9s ",
+		formatTaskStart(task.StartTime),
+		truncate(task.Node, 10),
+		truncate(task.Type, 10),
+		truncate(task.ID, 8),
+		truncate(task.User, 14),
+		formatTaskDuration(taskDuration(task)),
+	)
+	remaining := width - len([]rune(row))
+	if remaining < 1 {
+		return row
+	}
+	return row + lipgloss.NewStyle().Foreground(color).Render(truncate(result, remaining))
+}
+
+func (m Model) viewTasks() string {
+	headers := fmt.Sprintf("%-12s %-10s %-10s %-8s %-14s %9s %s", "STARTED", "NODE", "TYPE", "ID", "USER", "DURATION", "STATUS")
+
+	var rows []string
+	running, failed := 0, 0
+	for _, task := range m.taskList {
+		if task.Status == "running" {
+			running++
+		} else if task.ExitStatus != "OK" {
+			failed++
+		}
+		rows = append(rows, formatTaskRow(task, m.width))
+	}
+
+	title := fmt.Sprintf(" pvetop - tasks (%d running, %d failed, %d total) - refresh: 2s ", running, failed, len(m.taskList))
+	if m.width < widthLarge {
+		title = fmt.Sprintf(" pvetop tasks (%d running) ", running)
+	}
+
+	var status, problem string
+	if m.taskListErr != nil {
+		problem = queryProblem("task", m.taskListErr)
+	} else if m.taskList == nil {
+		status = "loading tasks..."
+	}
+
+	help := "q:quit | ?:help | ↑↓:select | enter:log | tab:next view | n:guests"
+	if m.width < widthMedium {
+		help = "q:quit | enter:log | tab:next view"
+	}
+
+	return m.renderTable(tableView{
+		title:   title,
+		status:  status,
+		problem: problem,
+		columns: headers,
+		rows:    rows,
+		help:    help,
+	})
+}
+
+func (m Model) openTaskLog() (Model, tea.Cmd) {
+	if m.selectedRow < 0 || m.selectedRow >= len(m.taskList) {
+		return m, nil
+	}
+
+	m.taskLog = &taskLog{task: m.taskList[m.selectedRow], follow: true}
+	m.viewMode = viewTaskLog
+	return m, m.fetchTaskLog(context.Background())
+}
+
+func (m Model) fetchTaskLog(ctx context.Context) tea.Cmd {
+	if m.taskLog == nil || m.taskLog.complete {
+		return nil
+	}
+	client := m.client
+	task := m.taskLog.task
+	start := len(m.taskLog.lines)
+	final := task.Status != "running"
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+		defer cancel()
+
+		lines, err := client.GetTaskLog(ctx, task.Node, task.UPID, start)
+		return taskLogMsg{upid: task.UPID, start: start, lines: lines, final: final, err: err}
+	}
+}
+
+func (m *Model) updateTaskLog(msg taskLogMsg) {
+	if m.taskLog == nil || m.taskLog.task.UPID != msg.upid || msg.start != len(m.taskLog.lines) {
+		return
+	}
+	if msg.err != nil {
+		if !errors.Is(msg.err, context.Canceled) {
+			m.taskLog.err = msg.err
+		}
+		return
+	}
+
+	m.taskLog.err = nil
+	for _, line := range msg.lines {
+		m.taskLog.lines = append(m.taskLog.lines, line.T)
+	}
+	if msg.final && len(msg.lines) == 0 {
+		m.taskLog.complete = true
+	}
+	if m.taskLog.follow {
+		m.taskLog.scroll = m.taskLogMaxScroll()
+	}
+}
+
+func (m Model) taskLogHeight() int {
+	height := m.height - 4
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+func (m Model) taskLogMaxScroll() int {
+	bottom := len(m.taskLog.lines) - m.taskLogHeight()
+	if bottom < 0 {
+		return 0
+	}
+	return bottom
+}
+
+func (m Model) updateTaskLogKeys(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	page := m.taskLogHeight()
+	scroll := m.taskLog.scroll
+
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Open):
+		m.viewMode = viewTasks
+		m.taskLog = nil
+		m.syncCursor()
+		return m, nil, true
+	case key.Matches(msg, m.keys.Up):
+		scroll--
+	case key.Matches(msg, m.keys.Down):
+		scroll++
+	case key.Matches(msg, m.keys.PageUp):
+		scroll -= page
+	case key.Matches(msg, m.keys.PageDown):
+		scroll += page
+	case key.Matches(msg, m.keys.Top):
+		scroll = 0
+	case key.Matches(msg, m.keys.Bottom):
+		scroll = m.taskLogMaxScroll()
+	default:
+		return m, nil, false
+	}
+
+	bottom := m.taskLogMaxScroll()
+	if scroll > bottom {
+		scroll = bottom
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	log := *m.taskLog
+	log.scroll = scroll
+	log.follow = scroll == bottom
+	m.taskLog = &log
+	return m, nil, true
+}
+
+func taskLogColor(line string) lipgloss.Color {
+	switch {
+	case strings.HasPrefix(line, "TASK OK"):
+		return theme.Catppuccin.Green
+	case strings.HasPrefix(line, "TASK ERROR"), strings.Contains(line, "ERROR:"):
+		return theme.Catppuccin.Red
+	case strings.HasPrefix(line, "TASK WARNINGS"), strings.Contains(line, "WARN:"):
+		return theme.Catppuccin.Peach
+	default:
+		return theme.Catppuccin.Text
+	}
+}
+
+func (m Model) viewTaskLog() string {
+	var s string
+	log := m.taskLog
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Catppuccin.Text).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+
+	result, _ := taskResult(log.task)
+	title := fmt.Sprintf(" pvetop - task log: %s %s on %s (%s, %s) ", log.task.Type, log.task.ID, log.task.Node, result, formatTaskDuration(taskDuration(log.task)))
+	s += headerStyle.Render(truncate(title, m.width))
+
+	if log.err != nil {
+		problemStyle := lipgloss.NewStyle().Foreground(theme.Catppuccin.Red)
+		s += "\n" + problemStyle.Render(truncate(" "+queryProblem("log", log.err), m.width)) + "\n"
+	} else {
+		s += "\n\n"
+	}
+
+	height := m.taskLogHeight()
+	start := log.scroll
+	if start > len(log.lines) {
+		start = len(log.lines)
+	}
+	end := start + height
+	if end > len(log.lines) {
+		end = len(log.lines)
+	}
+
+	for _, line := range log.lines[start:end] {
+		s += lipgloss.NewStyle().Foreground(taskLogColor(line)).Render(truncate(line, m.width)) + "\n"
+	}
+	for i := end - start; i < height; i++ {
+		s += "\n"
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(theme.Catppuccin.Subtext1).
+		Background(theme.Catppuccin.Surface1).
+		Width(m.width)
+
+	var helpText string
+	if m.width >= widthMedium {
+		follow := "End:follow"
+		if log.follow {
+			follow = "following"
+		}
+		helpText = fmt.Sprintf("esc:back | ↑↓/PgUp/PgDn:scroll | %s | q:quit", follow)
+	} else {
+		helpText = "esc:back | ↑↓:scroll | q:quit"
+	}
+
+	s += m.renderStatusBar() + "\n" + helpStyle.Render(truncate(helpText, m.width))
+	return s
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var viewOrder = []viewMode{viewGuests, viewNodes, viewStorage, viewCeph, viewHA, viewReplication, viewTasks}
+
+func (m Model) isGuestView() bool {
+	return m.viewMode == viewGuests || m.viewMode == viewGuestDetail
+}
+
+func (m Model) baseView() viewMode {
+	switch m.viewMode {
+	case viewGuestDetail, viewSnapshots:
+		return viewGuests
+	case viewTaskLog:
+		return viewTasks
+	case viewReplicationLog:
+		return viewReplication
+	case viewNodeDetail:
+		return viewNodes
+	}
+	return m.viewMode
+}
+
+func (m Model) switchView(mode viewMode) (Model, tea.Cmd) {
+	if mode == viewNodes && len(m.nodes) == 0 {
+		return m, nil
+	}
+	m.viewMode = mode
+	m.detail = nil
+	m.taskLog = nil
+	m.snapshots = nil
+	m.replLog = nil
+	m.nodeDetail = nil
+	m.scrollOffset = 0
+	m.cephScroll = 0
+	m.haScroll = 0
+	m.syncCursor()
+	return m, m.fetchViewData(context.Background())
+}
+
+func (m Model) cycleView(delta int) (Model, tea.Cmd) {
+	current := 0
+	for i, mode := range viewOrder {
+		if mode == m.baseView() {
+			current = i
+		}
+	}
+	for step := 1; step <= len(viewOrder); step++ {
+		n := len(viewOrder)
+		next := viewOrder[((current+delta*step)%n+n)%n]
+		if next == viewNodes && len(m.nodes) == 0 || next == viewCeph && m.cephMissing || next == viewHA && m.haUnused() || next == viewReplication && m.replicationUnused() {
+			continue
+		}
+		return m.switchView(next)
+	}
+	return m, nil
+}
+
+func (m Model) fetchViewData(ctx context.Context) tea.Cmd {
+	switch m.viewMode {
+	case viewCeph:
+		return m.fetchCeph(ctx)
+	case viewHA:
+		return m.fetchHA()
+	case viewReplication:
+		return m.fetchReplication(ctx)
+	case viewReplicationLog:
+		return tea.Batch(m.fetchReplication(ctx), m.fetchReplicationLog(ctx))
+	case viewNodeDetail:
+		return m.fetchNodeDetail(ctx)
+	case viewTasks:
+		return m.fetchTasks(ctx)
+	case viewTaskLog:
+		return tea.Batch(m.fetchTasks(ctx), m.fetchTaskLog(ctx))
+	case viewSnapshots:
+		return m.fetchSnapshots(ctx)
+	}
+	return nil
+}
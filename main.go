@@ -1,41 +1,190 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/berocorpdotnet/pvetop/internal/api"
 	"github.com/berocorpdotnet/pvetop/internal/config"
+	"github.com/berocorpdotnet/pvetop/internal/fakepve"
 	"github.com/berocorpdotnet/pvetop/internal/setup"
 	"github.com/berocorpdotnet/pvetop/internal/ui"
 )
 
 func main() {
+	if hasArg("--demo") {
+		runDemo()
+		return
+	}
+
+	if path, ok := argValue("--replay"); ok {
+		source, err := api.LoadStaticSource(path)
+		if err != nil {
+			fmt.Printf("Error loading recording: %v\n", err)
+			os.Exit(1)
+		}
+		runUI(source)
+		return
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
+	if caFile, ok := argValue("--ca-file"); ok && caFile != cfg.CAFile {
+		cfg.CAFile = caFile
+		if err := config.Save(cfg); err != nil {
+			fmt.Printf("Failed to save configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Fingerprint == "" && cfg.CAFile == "" {
+		if err := pinCertificate(cfg); err != nil {
+			fmt.Printf("Failed to pin server certificate: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	client := api.NewClientWithToken(cfg.Host, cfg.Port, cfg.Token)
+	if err := client.ConfigureTLS(api.TLSConfig{Fingerprint: cfg.Fingerprint, CAFile: cfg.CAFile}); err != nil {
+		fmt.Printf("Invalid TLS configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if workers, ok := argValue("--workers"); ok {
+		n, err := strconv.Atoi(workers)
+		if err != nil || n < 1 {
+			fmt.Printf("Invalid --workers value: %s\n", workers)
+			os.Exit(1)
+		}
+		client.SetConcurrency(n)
+	}
 	
-	_, err = client.GetNodes()
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	_, err = client.GetNodes(ctx)
+	cancel()
 	if err != nil {
 		fmt.Printf("Failed to connect to Proxmox: %v\n", err)
+		switch {
+		case api.IsCertificateMismatch(err):
+			fmt.Println()
+			fmt.Println("@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@")
+			fmt.Println("@    WARNING: PROXMOX SERVER CERTIFICATE HAS CHANGED!     @")
+			fmt.Println("@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@")
+			fmt.Println("Someone could be intercepting the connection, or the certificate")
+			fmt.Printf("of %s was renewed. pvetop refuses to connect.\n", cfg.Host)
+			fmt.Println("If the change is expected, run 'pvetop --setup' to trust the new certificate.")
+			os.Exit(1)
+		case api.IsUnauthorized(err):
+			fmt.Println("The stored API token was rejected - it may have been revoked or expired.")
+			fmt.Println("Run 'pvetop --setup' to create a new token.")
+			os.Exit(1)
+		case api.IsForbidden(err):
+			fmt.Printf("The API token for %s lacks the permission above.\n", cfg.Username)
+			fmt.Println("Grant it at least the PVEAuditor role on / or run 'pvetop --setup' again.")
+			os.Exit(1)
+		}
 		fmt.Printf("Config details - Host: %s, Port: %s, Username: %s, Token length: %d\n", 
 			cfg.Host, cfg.Port, cfg.Username, len(cfg.Token))
 		fmt.Println("Your configuration may be invalid. Run 'pvetop --setup' to reconfigure.")
 		os.Exit(1)
 	}
 	
-	p := tea.NewProgram(ui.NewModel(client), tea.WithAltScreen())
+	if path, ok := argValue("--dump"); ok {
+		if err := dumpCluster(client, path); err != nil {
+			fmt.Printf("Failed to record cluster state: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Cluster state written to %s (replay with 'pvetop --replay %s')\n", path, path)
+		return
+	}
+
+	runUI(client)
+}
+
+func runDemo() {
+	opts := fakepve.Options{}
+	for name, target := range map[string]*int{"--demo-nodes": &opts.Nodes, "--demo-guests-per-node": &opts.GuestsPerNode} {
+		if value, ok := argValue(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				fmt.Printf("Invalid %s value: %s\n", name, value)
+				os.Exit(1)
+			}
+			*target = n
+		}
+	}
+
+	server := fakepve.New(opts)
+	defer server.Close()
+
+	host, port := server.HostPort()
+	client := api.NewClientWithToken(host, port, server.Token())
+	if err := client.ConfigureTLS(api.TLSConfig{Fingerprint: server.Fingerprint()}); err != nil {
+		fmt.Printf("Failed to start demo: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(newModel(client), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+	}
+}
+
+func newModel(source api.DataSource) ui.Model {
+	model := ui.NewModel(source)
+	if value, ok := argValue("--retention"); ok {
+		retention, err := time.ParseDuration(value)
+		if err != nil || retention < time.Minute {
+			fmt.Printf("Invalid --retention value: %s (use e.g. 30m or 2h)\n", value)
+			os.Exit(1)
+		}
+		model.SetRetention(retention)
+	}
+	if value, ok := argValue("--backup-max-age"); ok {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge <= 0 {
+			fmt.Printf("Invalid --backup-max-age value: %s (use e.g. 24h or 168h)\n", value)
+			os.Exit(1)
+		}
+		model.SetBackupMaxAge(maxAge)
+	}
+	filters, err := config.LoadFilters()
+	if err != nil {
+		fmt.Printf("Error loading saved filters: %v\n", err)
+		os.Exit(1)
+	}
+	model.SetSavedFilters(filters, config.SaveFilters)
+	return model
+}
+
+func runUI(source api.DataSource) {
+	p := tea.NewProgram(newModel(source), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+func dumpCluster(client *api.Client, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resources, err := client.GetClusterResources(ctx)
+	if err != nil {
+		return err
+	}
+	return api.SaveRecording(path, resources)
+}
+
 func loadConfig() (*config.Config, error) {
 	for _, arg := range os.Args[1:] {
 		if arg == "--setup" || arg == "--configure" {
@@ -69,10 +218,56 @@ func runSetup(force bool) (*config.Config, error) {
 		}
 	}
 
-	cfg, err := setup.RunSetupWizard()
+	caFile, _ := argValue("--ca-file")
+	cfg, err := setup.RunSetupWizard(caFile)
 	if err != nil {
 		return nil, fmt.Errorf("setup failed: %w", err)
 	}
 
 	return cfg, nil
 }
+
+func hasArg(name string) bool {
+	for _, arg := range os.Args[1:] {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
+
+func argValue(name string) (string, bool) {
+	args := os.Args[1:]
+	for i, arg := range args {
+		if strings.HasPrefix(arg, name+"=") {
+			return strings.TrimPrefix(arg, name+"="), true
+		}
+		if arg == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+func pinCertificate(cfg *config.Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	fingerprint, err := api.FetchFingerprint(ctx, cfg.Host, cfg.Port)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("No certificate is pinned for %s yet. The server presented:\n", cfg.Host)
+	fmt.Printf("  SHA-256 %s\n", fingerprint)
+	fmt.Println("Compare it with the node's certificate in the web UI before trusting it.")
+	fmt.Print("Trust this certificate? [y/N] ")
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return fmt.Errorf("certificate not trusted; run 'pvetop --setup' or pass --ca-file")
+	}
+
+	cfg.Fingerprint = fingerprint
+	return config.Save(cfg)
+}